@@ -0,0 +1,274 @@
+package decimal
+
+import (
+	"math"
+	"math/big"
+)
+
+// Bridges to math/big.
+//
+// These functions allocate by nature (every *big.Int is a heap object), so they are kept out of the
+// hot paths: use them at the boundary with code that already works with math/big values.
+
+// bigTen is shared read-only by the math/big bridges, it must never be modified.
+var bigTen = big.NewInt(10)
+
+// bigTenPow returns 10^n as a new *big.Int.
+func bigTenPow(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+// bigIntVme returns the VME tuple of |i| * 10^exp, rounding the mantissa to the nearest when it
+// does not fit in maxM and setting loss bit accordingly. v must already hold the sign.
+func bigIntVme(v uint64, i *big.Int, exp int64, maxM uint64) (uint64, uint64, int64) {
+	a := new(big.Int).Abs(i)
+
+	if a.IsUint64() && a.Uint64() <= maxM {
+		return v, a.Uint64(), exp
+	}
+
+	// a < 2^n so a / 10^k <= maxM as soon as 10^k >= 2^(n-57), start just below and adjust upward
+	k := int64(0)
+	if n := int64(a.BitLen()) - 58; n > 0 {
+		k = n * 30103 / 100000 // log10(2) ~ 0.30103
+	}
+
+	p := bigTenPow(k)
+	q, r := new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(a, p, r)
+		if q.IsUint64() && q.Uint64() <= maxM {
+			break
+		}
+		p.Mul(p, bigTen)
+		k++
+	}
+
+	m := q.Uint64()
+	if r.Sign() != 0 {
+		v |= loss
+
+		// round to the nearest
+		if r.Lsh(r, 1).Cmp(p) >= 0 {
+			m++
+		}
+	}
+
+	return v, m, exp + k
+}
+
+// NewFromBigInt returns a new Decimal from a big.Int, value * 10 ^ exp, compatible with shopspring/decimal NewFromBigInt function.
+//
+// When value has more significant digits than the 57 bits mantissa can hold, the result is rounded
+// to the nearest and its loss bit is set.
+func NewFromBigInt(value *big.Int, exp int32) Decimal {
+	if value.Sign() == 0 {
+		return Zero
+	}
+
+	var v uint64
+	if value.Sign() < 0 {
+		v = sign
+	}
+
+	return vmeAsDecimal(bigIntVme(v, value, int64(exp), MaxInt))
+}
+
+// BigInt returns the integer component of the decimal as a big.Int (truncated towards zero).
+//
+// Null, Zero, NearZero, NearPositiveZero and NearNegativeZero return 0 while NaN, PositiveInfinity
+// and NegativeInfinity return nil as they have no integer value.
+func (d Decimal) BigInt() *big.Int {
+	v, m, e := d.vme()
+
+	if m == 0 {
+		if v&loss != 0 && e != 0 && e != math.MinInt64 {
+			return nil
+		}
+
+		return new(big.Int)
+	}
+
+	i := new(big.Int).SetUint64(m)
+	if e > 0 {
+		i.Mul(i, bigTenPow(e))
+	} else if e < 0 {
+		i.Quo(i, bigTenPow(-e))
+	}
+
+	if v&sign != 0 {
+		i.Neg(i)
+	}
+
+	return i
+}
+
+// NewFromBigFloat returns a new Decimal from a big.Float.
+//
+// The value is rounded to the nearest decimal holding in the 57 bits mantissa and its loss bit is
+// set whenever some digits are dropped. Infinities map to PositiveInfinity and NegativeInfinity,
+// +0 maps to Zero and -0 to NearNegativeZero (like NewFromFloat does with a float64 -0), and values
+// too close to zero keep their sign as NearPositiveZero or NearNegativeZero.
+func NewFromBigFloat(value *big.Float) Decimal {
+	if value.IsInf() {
+		if value.Signbit() {
+			return NegativeInfinity
+		}
+
+		return PositiveInfinity
+	}
+
+	if value.Sign() == 0 {
+		if value.Signbit() && !DenormalizeToZero {
+			return NearNegativeZero
+		}
+
+		return Zero
+	}
+
+	var v uint64
+	if value.Signbit() {
+		v = sign
+	}
+
+	// |value| = mant * 2^exp2 with mant in [0.5, 1): quickly reject values out of Decimal range,
+	// the largest Decimal is below 2^108 and anything below 2^-64 rounds to a near zero.
+	f := new(big.Float)
+	exp2 := value.MantExp(f)
+	if exp2 > 108 {
+		return vmeAsDecimal(v|loss, 0, math.MaxInt64)
+	} else if exp2 < -64 {
+		return vmeAsDecimal(v|loss, 0, math.MinInt64)
+	}
+
+	// keep at most 128 bits (~38 digits) of the mantissa, well above what the 57 bits mantissa can hold
+	if f.MinPrec() > 128 {
+		v |= loss
+		f.SetPrec(128)
+	}
+
+	// turn the mantissa into an integer: |value| = i * 2^exp2
+	prec := int(f.MinPrec())
+	f.SetMantExp(f, prec)
+	exp2 -= prec
+
+	i, _ := f.Abs(f).Int(nil)
+	if exp2 >= 0 {
+		return vmeAsDecimal(bigIntVme(v, i.Lsh(i, uint(exp2)), 0, MaxInt))
+	}
+
+	// i * 2^exp2 = i * 5^-exp2 * 10^exp2 exactly
+	i.Mul(i, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(-exp2)), nil))
+
+	return vmeAsDecimal(bigIntVme(v, i, int64(exp2), MaxInt))
+}
+
+// BigFloat returns the decimal as a big.Float with a precision of 128 bits, enough to hold any
+// Decimal integer exactly and any fractional Decimal to the nearest.
+//
+// NearPositiveZero and NearNegativeZero return a signed zero, PositiveInfinity and NegativeInfinity
+// return a signed infinity while NaN returns nil as big.Float has no NaN.
+func (d Decimal) BigFloat() *big.Float {
+	v, m, e := d.vme()
+
+	f := new(big.Float).SetPrec(128)
+
+	if m == 0 {
+		if v&loss != 0 {
+			switch e {
+			case math.MaxInt64:
+				return f.SetInf(v&sign != 0)
+			case math.MinInt64:
+				if v&sign != 0 {
+					return f.Neg(f)
+				}
+			case 0:
+			default:
+				return nil
+			}
+		}
+
+		return f
+	}
+
+	f.SetUint64(m)
+	if e > 0 {
+		f.Mul(f, new(big.Float).SetInt(bigTenPow(e)))
+	} else if e < 0 {
+		f.Quo(f, new(big.Float).SetInt(bigTenPow(-e)))
+	}
+
+	if v&sign != 0 {
+		f.Neg(f)
+	}
+
+	return f
+}
+
+// Rat returns the exact rational value of the decimal (mantissa * 10 ^ exponent) as a big.Rat.
+//
+// Null, Zero, NearZero, NearPositiveZero and NearNegativeZero return 0 while NaN, PositiveInfinity
+// and NegativeInfinity return nil as they are not rational numbers.
+func (d Decimal) Rat() *big.Rat {
+	v, m, e := d.vme()
+
+	if m == 0 {
+		if v&loss != 0 && e != 0 && e != math.MinInt64 {
+			return nil
+		}
+
+		return new(big.Rat)
+	}
+
+	num := new(big.Int).SetUint64(m)
+	if v&sign != 0 {
+		num.Neg(num)
+	}
+
+	if e >= 0 {
+		return new(big.Rat).SetInt(num.Mul(num, bigTenPow(e)))
+	}
+
+	return new(big.Rat).SetFrac(num, bigTenPow(-e))
+}
+
+// NewFromBigRat returns a new Decimal from a big.Rat rounded to precision digits after the decimal point
+// (an integer multiple of 10^(-precision)), following the package Round semantics. Negative precision is allowed.
+//
+// The loss bit is set when r is not exactly representable, either because of the rounding to precision
+// or because the result has more significant digits than the 57 bits mantissa can hold.
+func NewFromBigRat(r *big.Rat, precision int32) Decimal {
+	if r.Sign() == 0 {
+		return Zero
+	}
+
+	var v uint64
+	if r.Sign() < 0 {
+		v = sign
+	}
+
+	num := new(big.Int).Abs(r.Num())
+	den := new(big.Int).Set(r.Denom())
+	if precision >= 0 {
+		num.Mul(num, bigTenPow(int64(precision)))
+	} else {
+		den.Mul(den, bigTenPow(-int64(precision)))
+	}
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		v |= loss
+
+		// round to the nearest, ties towards +Inf like Round
+		if c := rem.Lsh(rem, 1).Cmp(den); c > 0 || c == 0 && v&sign == 0 {
+			q.Add(q, big.NewInt(1))
+		}
+
+		// like Div, a non-zero value rounded to 0 is too close to zero and keeps its sign
+		if q.Sign() == 0 {
+			return vmeAsDecimal(v, 0, math.MinInt64)
+		}
+	}
+
+	return vmeAsDecimal(bigIntVme(v, q, -int64(precision), MaxInt))
+}
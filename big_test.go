@@ -0,0 +1,282 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNewFromBigInt(t *testing.T) {
+	if d := NewFromBigInt(big.NewInt(0), 5); d != Zero {
+		t.Errorf(`NewFromBigInt(0, 5) should be Zero and not %v`, d)
+	}
+	if d := NewFromBigInt(big.NewInt(12345), -2); d != New(12345, -2) || !d.IsExact() {
+		t.Errorf(`NewFromBigInt(12345, -2) should be exactly 123.45 and not %v`, d)
+	}
+	if d := NewFromBigInt(big.NewInt(-12345), 3); d != -12345000 || !d.IsExact() {
+		t.Errorf(`NewFromBigInt(-12345, 3) should be exactly -12345000 and not %v`, d)
+	}
+
+	// 10^30 is exact as trailing zeros are moved to the exponent
+	b, _ := new(big.Int).SetString("1000000000000000000000000000000", 10)
+	if d := NewFromBigInt(b, -20); d != 10000000000 || !d.IsExact() {
+		t.Errorf(`NewFromBigInt(10^30, -20) should be exactly 10000000000 and not %v`, d)
+	}
+
+	// more digits than the mantissa can hold: rounded to the nearest with loss bit
+	b, _ = new(big.Int).SetString("123456789012345678901234567890", 10)
+	if d := NewFromBigInt(b, 0); d.IsExact() || d.String() != "~123456789012345679000000000000" {
+		t.Errorf(`NewFromBigInt(123456789012345678901234567890, 0) should be ~123456789012345679000000000000 and not %v`, d)
+	}
+	b.Neg(b)
+	if d := NewFromBigInt(b, -30); d.IsExact() || d.String() != "~-0.1234567890123457" {
+		t.Errorf(`NewFromBigInt(-123456789012345678901234567890, -30) should be ~-0.1234567890123457 and not %v`, d)
+	}
+
+	// too big for the exponent range
+	b, _ = new(big.Int).SetString("1234567890123456789012345678901234567890", 10)
+	if d := NewFromBigInt(b, 0); d != PositiveInfinity {
+		t.Errorf(`NewFromBigInt(1234567890123456789012345678901234567890, 0) should be +Inf and not %v`, d)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		want string
+	}{
+		{Null, "0"},
+		{Zero, "0"},
+		{NearZero, "0"},
+		{NearNegativeZero, "0"},
+		{123, "123"},
+		{-123, "-123"},
+		{New(12399, -2), "123"},
+		{New(-12399, -2), "-123"},
+		{New(5, -1), "0"},
+		{New(123456789, 12), "123456789000000000000"},
+		{New(-1, 15), "-1000000000000000"},
+	}
+
+	for _, c := range cases {
+		if i := c.d.BigInt(); i == nil || i.String() != c.want {
+			t.Errorf(`%v.BigInt() should be %s and not %v`, c.d, c.want, i)
+		}
+	}
+
+	for _, d := range []Decimal{NaN, PositiveInfinity, NegativeInfinity} {
+		if i := d.BigInt(); i != nil {
+			t.Errorf(`%v.BigInt() should be nil and not %v`, d, i)
+		}
+	}
+
+	// round trip through big.Int keeps every digit
+	b, _ := new(big.Int).SetString("144115188075855871000", 10)
+	if i := NewFromBigInt(b, 0).BigInt(); i.Cmp(b) != 0 {
+		t.Errorf(`NewFromBigInt(%v, 0).BigInt() should be %v and not %v`, b, b, i)
+	}
+}
+
+func TestNewFromBigFloat(t *testing.T) {
+	cases := []struct {
+		f     *big.Float
+		want  string
+		exact bool
+	}{
+		{big.NewFloat(0), "0", true},
+		{big.NewFloat(1.5), "1.5", true},
+		{big.NewFloat(-0.125), "-0.125", true},
+		{big.NewFloat(1e20), "100000000000000000000", true},
+		{new(big.Float).SetInt64(-1234567), "-1234567", true},
+		{new(big.Float).SetPrec(200).SetFloat64(0.1), "~0.1", false},
+		{new(big.Float).SetPrec(200).Quo(big.NewFloat(2), big.NewFloat(3)), "~0.6666666666666667", false},
+		{new(big.Float).SetPrec(300).Quo(big.NewFloat(-1), big.NewFloat(3)), "~-0.3333333333333333", false},
+		{big.NewFloat(1e-30), "+~0", false},
+		{big.NewFloat(-1e-30), "-~0", false},
+		{big.NewFloat(1e40), "+Inf", false},
+		{new(big.Float).SetInf(false), "+Inf", false},
+		{new(big.Float).SetInf(true), "-Inf", false},
+		{new(big.Float).Neg(big.NewFloat(0)), "-~0", false},
+	}
+
+	for _, c := range cases {
+		if d := NewFromBigFloat(c.f); d.String() != c.want || d.IsExact() != c.exact {
+			t.Errorf(`NewFromBigFloat(%v) should be %s (exact = %t) and not %v`, c.f, c.want, c.exact, d)
+		}
+	}
+
+	if d := NewFromBigFloat(new(big.Float).Neg(big.NewFloat(0))); d != NearNegativeZero {
+		t.Errorf(`NewFromBigFloat(-0) should be NearNegativeZero and not %v`, d)
+	}
+	if d := NewFromBigFloat(big.NewFloat(0)); d != Zero {
+		t.Errorf(`NewFromBigFloat(+0) should be Zero and not %v`, d)
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		want string
+	}{
+		{Null, "0"},
+		{Zero, "0"},
+		{NearZero, "0"},
+		{NearPositiveZero, "0"},
+		{NearNegativeZero, "-0"},
+		{PositiveInfinity, "+Inf"},
+		{NegativeInfinity, "-Inf"},
+		{123, "123"},
+		{New(-12345, -2), "-123.45"},
+		{New(1, -16), "1e-16"},
+		{New(MaxInt, 15), "1.44115188075855871e+32"},
+	}
+
+	for _, c := range cases {
+		if f := c.d.BigFloat(); f == nil || f.Text('g', 20) != c.want {
+			t.Errorf(`%v.BigFloat() should be %s and not %v`, c.d, c.want, f)
+		}
+	}
+
+	if f := NaN.BigFloat(); f != nil {
+		t.Errorf(`NaN.BigFloat() should be nil and not %v`, f)
+	}
+
+	// round trip through big.Float gives back the same digits for any finite Decimal (fractional ones are flagged as inexact)
+	for _, d := range []Decimal{1, -7, New(12345, -2), New(1, -16), New(-MaxInt, -16), New(MaxInt, 15), NewFromFloat(0.1)} {
+		if r := NewFromBigFloat(d.BigFloat()); !r.Equal(d) || r.Mantissa() != d.Mantissa() || r.Exponent() != d.Exponent() {
+			t.Errorf(`NewFromBigFloat(%v.BigFloat()) should be %v and not %v`, d, d, r)
+		}
+	}
+}
+
+func TestRat(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		want string
+	}{
+		{Null, "0/1"},
+		{Zero, "0/1"},
+		{NearZero, "0/1"},
+		{NearNegativeZero, "0/1"},
+		{123, "123/1"},
+		{New(-12345, -2), "-2469/20"},
+		{New(1, -16), "1/10000000000000000"},
+		{New(7, 15), "7000000000000000/1"},
+		{Decimal(1).Div(3), "3333333333333333/10000000000000000"},
+	}
+
+	for _, c := range cases {
+		if r := c.d.Rat(); r == nil || r.String() != c.want {
+			t.Errorf(`%v.Rat() should be %s and not %v`, c.d, c.want, r)
+		}
+	}
+
+	for _, d := range []Decimal{NaN, PositiveInfinity, NegativeInfinity} {
+		if r := d.Rat(); r != nil {
+			t.Errorf(`%v.Rat() should be nil and not %v`, d, r)
+		}
+	}
+}
+
+func TestNewFromBigRat(t *testing.T) {
+	cases := []struct {
+		r         *big.Rat
+		precision int32
+		want      string
+	}{
+		{big.NewRat(0, 1), 2, "0"},
+		{big.NewRat(1, 4), 2, "0.25"},
+		{big.NewRat(1, 4), 1, "~0.3"},
+		{big.NewRat(-1, 4), 1, "~-0.2"},
+		{big.NewRat(1, 3), 5, "~0.33333"},
+		{big.NewRat(-2, 3), 16, "~-0.6666666666666667"},
+		{big.NewRat(2, 3), 30, "~0.6666666666666667"},
+		{big.NewRat(12345, 1), -2, "~12300"},
+		{big.NewRat(1, 1000), 2, "+~0"},
+		{big.NewRat(-1, 1000), 2, "-~0"},
+		{big.NewRat(5, 1000), 2, "~0.01"},
+	}
+
+	for _, c := range cases {
+		if d := NewFromBigRat(c.r, c.precision); d.String() != c.want {
+			t.Errorf(`NewFromBigRat(%v, %d) should be %s and not %v`, c.r, c.precision, c.want, d)
+		}
+	}
+
+	// round trip through big.Rat is exact
+	for _, d := range []Decimal{1, -7, New(12345, -2), New(1, -16), New(-MaxInt, -16), New(MaxInt, 15)} {
+		if r := NewFromBigRat(d.Rat(), 16); r != d || !r.IsExact() {
+			t.Errorf(`NewFromBigRat(%v.Rat(), 16) should be exactly %v and not %v`, d, d, r)
+		}
+	}
+}
+
+func TestDivCorrectlyRounded(t *testing.T) {
+	cases := []struct {
+		d1, d2 Decimal
+		want   string
+	}{
+		{New(14569568235, -3), New(5607988, -5), "~259800.27480443967"}, // BenchmarkDecimalQuoRem
+		{New(65289684351, 2), 8, "816121054387.5"},
+		{New(4860395401, 0), 4400, "~1104635.31840909091"},
+		{New(-842407799894, -3), New(418282, -3), "~-2013970.9571389637"},
+		{New(18349418808, -4), 23800, "~77.098398352941176"},
+		{New(-15445, -7), 400000000, "~-0.0000000000038613"}, // tie, rounded away from zero
+		{1, New(45359237, -8), "~2.2046226218487758"},
+		{New(1, 15), 7, "~142857142857142.857"},
+		{2, 3, "~0.6666666666666667"},
+		{-2, 3, "~-0.6666666666666667"},
+		{1, New(3, 17), "+~0"},
+		{-1, New(3, 17), "-~0"},
+	}
+
+	for _, c := range cases {
+		if r := c.d1.Div(c.d2); r.String() != c.want {
+			t.Errorf(`%v / %v should be %s and not %v`, c.d1, c.d2, c.want, r)
+		}
+	}
+
+	// the quotient is within half a unit of its last digit from the exact quotient, and exact only if the division is
+	random := uint64(88172645463325252)
+	next := func() uint64 {
+		random ^= random << 13
+		random ^= random >> 7
+		random ^= random << 17
+
+		return random
+	}
+
+	for i := 0; i < 2000; i++ {
+		d1 := New(int64(next()&MaxInt>>(next()%57))-int64(MaxInt>>1), int32(next()%20)-10)
+		d2 := New(int64(next()&MaxInt>>(next()%57))+1, int32(next()%20)-10)
+		if d1.IsZero() {
+			continue
+		}
+
+		q := d1.Div(d2)
+		x := new(big.Rat).Quo(d1.Rat(), d2.Rat())
+
+		if q.IsInfinite() {
+			// beyond the largest decimal
+			if x.Abs(x).Cmp(New(1, 32).Rat()) < 0 {
+				t.Errorf(`%v / %v should not be infinite`, d1, d2)
+			}
+			continue
+		}
+		if q.IsZero() {
+			// below the smallest decimal
+			if x.Abs(x).Cmp(big.NewRat(1, 20000000000000000)) > 0 {
+				t.Errorf(`%v / %v should not be near zero`, d1, d2)
+			}
+			continue
+		}
+
+		_, _, e := q.vme()
+		ulp := New(1, int32(e)).Rat()
+		diff := new(big.Rat).Sub(q.Rat(), x)
+		diff.Abs(diff).Mul(diff, big.NewRat(2, 1))
+
+		if diff.Cmp(ulp) > 0 || q.IsExact() != (diff.Sign() == 0) {
+			t.Errorf(`%v / %v should be %s rounded to 10^%d and not %v`, d1, d2, x.FloatString(20), e, q)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package decimal
+
+import (
+	"math"
+)
+
+// RoundingMode selects how a Context rounds its results, each mode matching a rounding method of Decimal.
+type RoundingMode int
+
+const (
+	// RoundingHalfUp rounds to the nearest, a tie toward +infinity, like Round (the zero RoundingMode).
+	RoundingHalfUp RoundingMode = iota
+	// RoundingHalfEven rounds to the nearest, a tie to the even neighbour, like RoundBank.
+	RoundingHalfEven
+	// RoundingCeil rounds toward +infinity, like RoundCeil.
+	RoundingCeil
+	// RoundingFloor rounds toward -infinity, like RoundFloor.
+	RoundingFloor
+	// RoundingUp rounds away from zero, like RoundUp.
+	RoundingUp
+	// RoundingDown rounds toward zero, like RoundDown and Truncate.
+	RoundingDown
+)
+
+// Context holds the precision and the rounding mode of the operations which cannot be exact, so that they are passed explicitly
+// rather than through the package variables DivisionPrecision and PowPrecisionNegativeExponent: these globals are shared by all the goroutines,
+// changing them while another goroutine divides is a data race. A Context is a plain value, safe for concurrent use.
+//
+// The zero Context divides to integers rounded with RoundingHalfUp, use DefaultContext for the precision of the package defaults.
+//
+// Example:
+//
+//	c := Context{DivisionPrecision: 2, Rounding: RoundingHalfEven}
+//	c.Div(1, 8) // ~0.12
+type Context struct {
+	// DivisionPrecision has the number of decimal places of a result which cannot be exact.
+	DivisionPrecision int32
+	// Rounding is the rounding mode of a result which cannot be exact.
+	Rounding RoundingMode
+}
+
+// DefaultContext returns the Context of the package defaults, 16 decimal places rounded with RoundingHalfUp,
+// whatever the current value of DivisionPrecision.
+func DefaultContext() Context {
+	return Context{DivisionPrecision: 16, Rounding: RoundingHalfUp}
+}
+
+// Round rounds d to places decimal places with the rounding mode of c.
+func (c Context) Round(d Decimal, places int32) Decimal {
+	switch c.Rounding {
+	case RoundingHalfEven:
+		return d.RoundBank(places)
+	case RoundingCeil:
+		return d.RoundCeil(places)
+	case RoundingFloor:
+		return d.RoundFloor(places)
+	case RoundingUp:
+		return d.RoundUp(places)
+	case RoundingDown:
+		return d.RoundDown(places)
+	default:
+		return d.Round(places)
+	}
+}
+
+// Div returns d1 / d2 rounded to c.DivisionPrecision decimal places with the rounding mode of c, or to as many as the mantissa can hold.
+// If it doesn't divide exactly, loss bit is set.
+func (c Context) Div(d1, d2 Decimal) Decimal {
+	v1, m1, e1 := d1.vme()
+	v2, m2, e2 := d2.vme()
+
+	if m1 == 0 || m2 == 0 {
+		return vmeAsDecimal(vmeDiv(v1, m1, e1, v2, m2, e2, c.DivisionPrecision, MaxInt, decimalMinE))
+	}
+
+	p := c.DivisionPrecision
+	q, r := d1.QuoRem(d2, p)
+	if r.IsExactlyZero() || (v1|v2)&loss == 0 && !q.IsExact() {
+		// exact, or already rounded to the mantissa
+		return q
+	}
+
+	// the truncated quotient q is incremented by one unit of its last place depending on the rounding mode,
+	// half is compared with the remainder in units of d2 * 10^-p
+	neg := (v1^v2)&sign != 0
+	half := r.Abs().Mul(2).Compare(d2.Abs().Shift(-p))
+
+	var inc bool
+	switch c.Rounding {
+	case RoundingHalfEven:
+		inc = half > 0 || half == 0 && isOddAt(q, p)
+	case RoundingCeil:
+		inc = !neg
+	case RoundingFloor:
+		inc = neg
+	case RoundingUp:
+		inc = true
+	case RoundingDown:
+		inc = false
+	default:
+		inc = half > 0 || half == 0 && !neg
+	}
+
+	if inc {
+		if neg {
+			q = q.Sub(New(1, -p))
+		} else {
+			q = q.Add(New(1, -p))
+		}
+	}
+
+	v, m, e := q.vme()
+	if m == 0 {
+		// a quotient rounded to zero is a signed near zero
+		v, e = v&^sign, math.MinInt64
+		if neg {
+			v |= sign
+		}
+	}
+
+	return vmeAsDecimal(v|loss, m, e)
+}
+
+// isOddAt returns whether q, a multiple of 10^-p, is an odd multiple of it, that is q.Shift(p) is an odd integer,
+// whatever the exponent q is stored with.
+func isOddAt(q Decimal, p int32) bool {
+	_, m, e := q.vme()
+	if m == 0 {
+		return false
+	}
+
+	// q.Shift(p) is m * 10^k, even as soon as k > 0
+	k := e + int64(p)
+	if k > 0 {
+		return false
+	}
+	if -k >= int64(len(tenPow)) {
+		return false
+	}
+
+	return (m/tenPow[-k])&1 == 1
+}
+
+// QuoRem returns d1.QuoRem(d2, c.DivisionPrecision), the quotient truncated to c.DivisionPrecision decimal places and the remainder.
+func (c Context) QuoRem(d1, d2 Decimal) (Decimal, Decimal) {
+	return d1.QuoRem(d2, c.DivisionPrecision)
+}
+
+// PowInt32 returns d to the power of exp like d.PowInt32(exp), but a negative exponent is computed as 1 / d^-exp with c.Div.
+func (c Context) PowInt32(d Decimal, exp int32) (Decimal, error) {
+	if exp >= 0 {
+		return d.PowInt32(exp)
+	}
+
+	r, err := d.PowInt32(-exp)
+	if err != nil {
+		return r, err
+	}
+
+	return c.Div(1, r), nil
+}
+
+// Pow returns d1**d2: an integer exponent goes through PowInt32, any other through Pow, its result being rounded
+// to c.DivisionPrecision decimal places with the rounding mode of c.
+func (c Context) Pow(d1, d2 Decimal) Decimal {
+	if d2.IsInteger() && d2.IsExact() && d2.Abs().LessThanOrEqual(math.MaxInt32) {
+		if r, err := c.PowInt32(d1, int32(d2.Int64())); err == nil {
+			return r
+		}
+
+		return NaN
+	}
+
+	return c.Round(d1.Pow(d2), c.DivisionPrecision)
+}
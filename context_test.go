@@ -0,0 +1,201 @@
+package decimal
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestContextDiv(t *testing.T) {
+	cases := []struct {
+		d1, d2 Decimal
+		mode   RoundingMode
+		s      string
+	}{
+		{1, 8, RoundingHalfUp, "~0.13"},
+		{-1, 8, RoundingHalfUp, "~-0.12"},
+		{1, 8, RoundingHalfEven, "~0.12"},
+		{-3, 8, RoundingHalfEven, "~-0.38"},
+		{1, 3, RoundingCeil, "~0.34"},
+		{-2, 3, RoundingCeil, "~-0.66"},
+		{1, 3, RoundingFloor, "~0.33"},
+		{-2, 3, RoundingFloor, "~-0.67"},
+		{1, 3, RoundingUp, "~0.34"},
+		{-1, 300, RoundingUp, "~-0.01"},
+		{-2, 3, RoundingDown, "~-0.66"},
+		{1, 300, RoundingHalfUp, "+~0"},
+		{-1, 300, RoundingDown, "-~0"},
+		{1, 300, RoundingCeil, "~0.01"},
+		{1, 4, RoundingUp, "0.25"},
+		{0, 3, RoundingUp, "0"},
+		{1, 0, RoundingHalfUp, "NaN"},
+	}
+
+	for _, c := range cases {
+		if r := (Context{DivisionPrecision: 2, Rounding: c.mode}).Div(c.d1, c.d2); r.String() != c.s {
+			t.Errorf(`Context{2, %d}.Div(%v, %v) should be %s and not %v`, c.mode, c.d1, c.d2, c.s, r)
+		}
+	}
+
+	// the default context divides like Div
+	for _, c := range []struct{ d1, d2 Decimal }{{1, 3}, {2, 3}, {-2, 3}, {1, 7}, {22, 7}, {New(1, -10), 3}} {
+		if r, d := DefaultContext().Div(c.d1, c.d2), c.d1.Div(c.d2); r != d {
+			t.Errorf(`DefaultContext().Div(%v, %v) should be %v like Div and not %v`, c.d1, c.d2, d, r)
+		}
+	}
+}
+
+// ratRound returns x rounded to a multiple of 10^-p with mode, computed exactly with big.Rat
+func ratRound(x *big.Rat, p int32, mode RoundingMode) *big.Rat {
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(p))), nil))
+	y := new(big.Rat).Set(x)
+	if p >= 0 {
+		y.Mul(y, scale)
+	} else {
+		y.Quo(y, scale)
+	}
+
+	// n is y truncated toward zero, f is |y - n| compared to 1/2
+	n := new(big.Int).Quo(y.Num(), y.Denom())
+	f := new(big.Rat).Sub(y, new(big.Rat).SetInt(n))
+	f.Abs(f)
+	half := f.Cmp(big.NewRat(1, 2))
+	neg := y.Sign() < 0
+
+	var inc bool
+	switch mode {
+	case RoundingHalfEven:
+		inc = half > 0 || half == 0 && n.Bit(0) == 1
+	case RoundingCeil:
+		inc = f.Sign() != 0 && !neg
+	case RoundingFloor:
+		inc = f.Sign() != 0 && neg
+	case RoundingUp:
+		inc = f.Sign() != 0
+	case RoundingDown:
+		inc = false
+	default:
+		inc = half > 0 || half == 0 && !neg
+	}
+	if inc {
+		if neg {
+			n.Sub(n, big.NewInt(1))
+		} else {
+			n.Add(n, big.NewInt(1))
+		}
+	}
+
+	r := new(big.Rat).SetInt(n)
+	if p >= 0 {
+		return r.Quo(r, scale)
+	}
+
+	return r.Mul(r, scale)
+}
+
+func abs32(p int32) int32 {
+	if p < 0 {
+		return -p
+	}
+
+	return p
+}
+
+func TestContextDivRat(t *testing.T) {
+	values := []struct{ d1, d2 Decimal }{
+		{878535, 21},
+		{New(-372756, -1), New(-24, -2)},
+		{25, 10},
+		{-35, 10},
+		{150, 1},
+		{-250, 1},
+		{7, 3},
+		{-2, 3},
+		{1, 8},
+		{-3, 8},
+		{New(12345, -3), New(5, -1)},
+		{999, 2},
+	}
+
+	for _, v := range values {
+		x := new(big.Rat).Quo(v.d1.Rat(), v.d2.Rat())
+
+		for p := int32(-2); p <= 2; p++ {
+			for mode := RoundingHalfUp; mode <= RoundingDown; mode++ {
+				r := (Context{DivisionPrecision: p, Rounding: mode}).Div(v.d1, v.d2)
+				want := ratRound(x, p, mode)
+
+				if want.Sign() == 0 {
+					if !r.IsZero() {
+						t.Errorf(`Context{%d, %d}.Div(%v, %v) should be zero and not %v`, p, mode, v.d1, v.d2, r)
+					}
+				} else if r.Rat().Cmp(want) != 0 {
+					t.Errorf(`Context{%d, %d}.Div(%v, %v) should be %s and not %v`, p, mode, v.d1, v.d2, want.FloatString(2), r)
+				}
+			}
+		}
+	}
+}
+
+func TestContextRound(t *testing.T) {
+	cases := []struct {
+		mode RoundingMode
+		s    string
+	}{
+		{RoundingHalfUp, "-12.34"},
+		{RoundingHalfEven, "-12.34"},
+		{RoundingCeil, "-12.34"},
+		{RoundingFloor, "-12.35"},
+		{RoundingUp, "-12.35"},
+		{RoundingDown, "-12.34"},
+	}
+
+	d := New(-12345, -3)
+	for _, c := range cases {
+		if r := (Context{Rounding: c.mode}).Round(d, 2); r.String() != c.s {
+			t.Errorf(`Context{Rounding: %d}.Round(%v, 2) should be %s and not %v`, c.mode, d, c.s, r)
+		}
+	}
+}
+
+func TestContextPow(t *testing.T) {
+	c := Context{DivisionPrecision: 4, Rounding: RoundingDown}
+
+	if r, err := c.PowInt32(3, -2); err != nil || r.String() != "~0.1111" {
+		t.Errorf(`Context{4, RoundingDown}.PowInt32(3, -2) should be ~0.1111 and not %v, %v`, r, err)
+	}
+	if r, err := c.PowInt32(3, 2); err != nil || r != 9 {
+		t.Errorf(`Context{4, RoundingDown}.PowInt32(3, 2) should be 9 and not %v, %v`, r, err)
+	}
+	if r := c.Pow(2, -3); r.String() != "0.125" {
+		t.Errorf(`Context{4, RoundingDown}.Pow(2, -3) should be 0.125 and not %v`, r)
+	}
+	if r := c.Pow(2, New(5, -1)); r.String() != "1.4142" {
+		t.Errorf(`Context{4, RoundingDown}.Pow(2, 0.5) should be 1.4142 and not %v`, r)
+	}
+	if r := (Context{DivisionPrecision: 4, Rounding: RoundingUp}).Pow(2, New(5, -1)); r.String() != "1.4143" {
+		t.Errorf(`Context{4, RoundingUp}.Pow(2, 0.5) should be 1.4143 and not %v`, r)
+	}
+}
+
+func TestContextConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := int32(0); i < 8; i++ {
+		wg.Add(1)
+		go func(p int32) {
+			defer wg.Done()
+
+			c := Context{DivisionPrecision: p}
+			want := NewFromInt(2).Div(3).Round(p).String()
+			for j := 0; j < 100; j++ {
+				if r := c.Div(2, 3); r.Round(p).String() != want {
+					t.Errorf(`Context{%d}.Div(2, 3) should round to %s and not %v`, p, want, r)
+					return
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/binary"
 	"math"
 	"math/bits"
-	"sync/atomic"
 	"unicode"
+	"unicode/utf8"
 )
 
 type unit struct {
@@ -14,6 +14,18 @@ type unit struct {
 	v uint64
 	h uint64
 	c Decimal
+	s Decimal // for a parse only alias, the number of units of code v in one alias unit (Null otherwise)
+	o Decimal // for an affine unit (Temperature), the offset added after the factor c to get the conversion scale (Null otherwise)
+}
+
+// factor returns the number of base units (kg for Weight, m for Length) in one unit,
+// an integer c being the power of ten of the factor.
+func (t *unit) factor() Decimal {
+	if t.c.IsInteger() {
+		return New(1, int32(t.c.Int64()))
+	}
+
+	return t.c
 }
 
 const (
@@ -94,22 +106,33 @@ func vmeNormalize(v, m uint64, e int64, maxM uint64, minE, maxE int64) (uint64,
 			}
 		}
 
-		// normalize m while it is divisible by 10 or greather than max_m
-		for m > maxM || e <= maxE && m > 9 && m&1 == 0 {
-			q, r := bits.Div64(0, m, 10)
-			if r != 0 {
-				// division tried has reminder, if mantissa was in acceptable range ignore division.
-				if m <= maxM {
-					break
-				}
+		// reduce m while greather than max_m, rounding only once from all the dropped digits (rounding each digit in turn
+		// would round twice, 1234567890123456749 giving ...750 and not ...700), rounding up may need another reduction
+		for m > maxM {
+			var r, rs uint64 // last dropped digit and sticky of the previous dropped digits
+
+			for m > maxM {
+				rs |= r
+				m, r = bits.Div64(0, m, 10)
+				e++
+			}
 
+			if r|rs != 0 {
 				v |= loss
 
 				// round to the nearest, but using round bank approach to minimize errors
-				if r > 5 || r == 5 && q&1 == 1 {
-					q++
+				if r > 5 || r == 5 && (rs != 0 || m&1 == 1) {
+					m++
 				}
 			}
+		}
+
+		// normalize m while it is divisible by 10
+		for e <= maxE && m > 9 && m&1 == 0 {
+			q, r := bits.Div64(0, m, 10)
+			if r != 0 {
+				break
+			}
 
 			m = q
 			e++
@@ -142,6 +165,12 @@ func vmeNormalizeExponent(v, m uint64, e int64, maxM uint64, minE, maxE int64) (
 
 		// e is now min_e
 		e = minE
+
+		// dropped digits may leave trailing zeros, remove them to keep a unique representation
+		for m > 9 && m%10 == 0 {
+			m /= 10
+			e++
+		}
 	}
 
 	// normalize too big exponent
@@ -239,6 +268,11 @@ func vmhmeReduce(v, mh, m uint64, e int64) (uint64, uint64, int64) {
 
 // extract a VME tuple from bytes which need to be normalized
 func vmeFromBytes(b []byte, units []unit) (v, m uint64, e int64, err error) {
+	// no decimal needs that many bytes, reject it before doing any work
+	if MaxParseLength > 0 && len(b) > MaxParseLength {
+		return 0, 0, 0, ErrTooLong
+	}
+
 	// take care of utf8 encoding with TrimSpace which is no more needed in the following code or a syntax error is raised
 	b = bytes.TrimSpace(b)
 
@@ -254,6 +288,21 @@ func vmeFromBytes(b []byte, units []unit) (v, m uint64, e int64, err error) {
 		return 0, 0, 0, nil
 	}
 
+	// accounting style negative number fully wrapped in parentheses: "(123.45)" is -123.45
+	parens := false
+	if b[i] == '(' || b[j] == ')' {
+		if i == j || b[i] != '(' || b[j] != ')' {
+			return 0, 0, 0, ErrSyntax
+		}
+		parens = true
+
+		i++
+		j--
+		if i > j {
+			return 0, 0, 0, ErrSyntax
+		}
+	}
+
 	// allow ~ to be first byte
 	if b[i] == '~' {
 		v |= loss
@@ -296,6 +345,14 @@ func vmeFromBytes(b []byte, units []unit) (v, m uint64, e int64, err error) {
 		}
 	}
 
+	// no sign allowed inside parentheses, which already mean a negative number
+	if parens {
+		if parsedSign {
+			return 0, 0, 0, ErrSyntax
+		}
+		v |= sign
+	}
+
 	doti := -1
 
 Loop:
@@ -315,8 +372,11 @@ Loop:
 					e--
 				}
 			} else {
-				if e >= 0 && b[i] != '0' {
+				// the digit is dropped: keep track of a non-zero one by making the last kept digit odd (rounding to odd),
+				// normalization drops at least 2 more digits (m > 1.8e18 > 10*MaxInt) so that it rounds to the nearest correctly
+				if b[i] != '0' {
 					v |= loss
+					m |= 1
 				}
 				if doti < 0 {
 					e++
@@ -326,18 +386,40 @@ Loop:
 			i++
 
 			continue
-		case b[i] == '.':
+		case b[i] == '_' || b[i] == ' ':
+			// digit group separator, only allowed between two digits ("1_000_000" or "1 000 000"),
+			// while a space may as well separate the number from its unit
+			if i > 0 && b[i-1] >= '0' && b[i-1] <= '9' && i < j && b[i+1] >= '0' && b[i+1] <= '9' {
+				i++
+
+				continue
+			}
+			if b[i] == '_' {
+				return 0, 0, 0, ErrSyntax
+			}
+
+			break Loop
+		case b[i] == '.' || DecimalSeparator != '.' && decimalSeparatorLen(b[i:j+1]) > 0:
 			if doti < 0 { // only one dot is allowed or a syntax error is raised
 				doti = i
 			} else {
 				return 0, 0, 0, ErrSyntax
 			}
 
-			i++
+			if b[i] == '.' {
+				i++
+			} else {
+				i += decimalSeparatorLen(b[i : j+1])
+			}
 
 			continue
 		case (b[i] | 0x20) == 'e': // a little more compact and probably faster and equivalent to b[i] == 'e' || b[i] == 'E'
 			if i < j && (b[i+1] == '-' || b[i+1] == '+' || b[i+1] >= '0' && b[i+1] <= '9') {
+				// an exponent needs a mantissa before it, "e5" or ".e5" are not numbers
+				if !parsedDigit {
+					return 0, 0, 0, ErrSyntax
+				}
+
 				negE := false
 
 				i++
@@ -354,15 +436,25 @@ Loop:
 				}
 				var _e int64
 				for i <= j && b[i] >= '0' && b[i] <= '9' {
-					_e = 10*_e + int64(b[i]-'0')
+					// saturate far beyond any representable exponent so that a huge exponent cannot wrap around
+					if _e < math.MaxInt32 {
+						_e = 10*_e + int64(b[i]-'0')
+					}
 					i++
 				}
+				// no digit group separator in the exponent
+				if i <= j && b[i] == '_' {
+					return 0, 0, 0, ErrSyntax
+				}
 
 				if negE {
 					e -= _e
 				} else {
 					e += _e
 				}
+			} else if i == j || b[i+1] == '_' {
+				// a trailing e is an exponent without digits ("1.5e" or "1.5E"), not a unit
+				return 0, 0, 0, ErrSyntax
 			}
 
 			break Loop
@@ -371,6 +463,17 @@ Loop:
 		}
 	}
 
+	// a dot is only allowed next to at least one digit ("5.", ".5" or "5.5"), a lone "." is not a number
+	// and so is a percent, per mille or basis point suffix without digit ("%", "-%" or "bp")
+	if doti >= 0 && !parsedDigit || !parsedDigit && i <= j && (b[i] == '%' || units == nil && scaleSuffix(b[i:j+1]) > 0) {
+		return 0, 0, 0, ErrSyntax
+	}
+
+	// parentheses only wrap a number, not a magic value such as "(nan)"
+	if parens && !parsedDigit {
+		return 0, 0, 0, ErrSyntax
+	}
+
 	// FIXME: NaN does not occurs here, so fix v and e to avoid NaN report
 	if m == 0 {
 		if v&loss != 0 {
@@ -391,6 +494,31 @@ Loop:
 	return vmeUnitOrMagicFromBytes(b[i:j+1], v, m, e, units)
 }
 
+// vmeConvert converts the VME tuple of a value expressed in unit from to unit to, through the conversion factors c of the units.
+// Zero and magic values (infinities, NaN and near zeros) are the same in any unit, only a mantissa is converted.
+func vmeConvert(v, m uint64, e int64, from, to *unit) (uint64, uint64, int64) {
+	if m == 0 {
+		return v, m, e
+	}
+
+	if from.c.IsInteger() {
+		e += from.c.Int64()
+	} else {
+		vc, mc, ec := from.c.vme()
+		v, m, e = vmeMul(v, m, e, vc, mc, ec)
+	}
+	if to.c.IsInteger() {
+		e -= to.c.Int64()
+	} else {
+		vc, mc, ec := to.c.vme()
+
+		// all the types with units share the mantissa and exponent range of Weight
+		v, m, e = vmeDiv(v, m, e, vc, mc, ec, int32(DivisionPrecision), WeightMaxInt, weightMinE)
+	}
+
+	return v, m, e
+}
+
 // compute unit hash and return error if overflow, this hash can be used for fast unit compare.
 func unitHash(s string) (h uint64) {
 	for _, r := range s {
@@ -409,22 +537,63 @@ func unitHash(s string) (h uint64) {
 	return
 }
 
+// hashUnits computes the hash of every unit, it is called by the init function of each unit table
+// so that parsing only compares hashes and the tables are never written once shared between goroutines.
+func hashUnits(units []unit) {
+	for i := range units {
+		units[i].h = unitHash(units[i].u)
+	}
+}
+
+// decimalSeparatorLen returns the length in bytes of DecimalSeparator if b starts with it, 0 otherwise
+func decimalSeparatorLen(b []byte) int {
+	if r, n := utf8.DecodeRune(b); r == DecimalSeparator && r != utf8.RuneError {
+		return n
+	}
+
+	return 0
+}
+
+// scaleSuffix returns the power of ten a suffix of a plain decimal divides its value by, or 0 if b is not such a suffix
+func scaleSuffix(b []byte) int64 {
+	switch string(bytes.TrimSpace(b)) {
+	case "%":
+		return 2
+	case "‰":
+		return 3
+	case "‱", "bp":
+		return 4
+	}
+
+	return 0
+}
+
 // interpret optional unit
 func vmeUnitOrMagicFromBytes(b []byte, v, m uint64, e int64, units []unit) (uint64, uint64, int64, error) {
+	// a percent sign is a suffix of a plain decimal (no unit) dividing its value by 100,
+	// so are the per mille sign by 1000 and the basis point (per ten thousand sign or "bp") by 10000
+	if units == nil {
+		if scale := scaleSuffix(b); scale > 0 {
+			if m != 0 {
+				e -= scale
+			}
+
+			return v, m, e, nil
+		}
+	}
+
 	if h := unitHash(string(b)); h > 0 {
 		for i := range units {
 			u := &units[i]
 
 			if u.u != "" {
-				// translate unit as unique uint64 hash for faster analysis
-				_h := atomic.LoadUint64(&u.h)
-				if _h == 0 {
-					_h = unitHash(u.u)
-
-					atomic.StoreUint64(&u.h, _h)
-				}
-
-				if h == _h {
+				// units are compared by their unique uint64 hash, computed once by hashUnits
+				if h == u.h {
+					// a parse only alias has no unit code of its own, its value is converted to the unit code v
+					if u.s != Null && m != 0 {
+						vs, ms, es := u.s.vme()
+						v, m, e = vmeMul(v, m, e, vs, ms, es)
+					}
 					v = v | u.v
 
 					return v, m, e, nil
@@ -435,23 +604,51 @@ func vmeUnitOrMagicFromBytes(b []byte, v, m uint64, e int64, units []unit) (uint
 		//check if a magic has been found, magic are only valid if m is zero
 		if m == 0 {
 			switch h {
-			case 28637, 8018001: // on, yes
+			case 28637, 8018001, 7359810: // on, yes, one
 				return v, 1, 0, nil
 
-			case 28381, 7357755: // no, off
+			case 28381, 7357755, 2077600704: // no, off, zero
 				if v&loss != 0 {
 					return v, 0, e, nil
 				} else {
 					return sign, 0, 0, nil
 				}
 
+			// small English number words, as the yes/no words above
+			case 7692378: // two
+				return v, 2, 0, nil
+			case 507819479832: // three
+				return v, 3, 0, nil
+			case 1738770108: // four
+				return v, 4, 0, nil
+			case 1738374058: // five
+				return v, 5, 0, nil
+			case 7622740: // six
+				return v, 6, 0, nil
+			case 503406349857: // seven
+				return v, 7, 0, nil
+			case 442398672657: // eight
+				return v, 8, 0, nil
+			case 1874168746: // nine
+				return v, 9, 0, nil
+			case 7687751: // ten
+				return v, 10, 0, nil
+			case 438139303968: // dozen
+				return v, 12, 0, nil
+			case 30097884680874218: // hundred
+				return v, 100, 0, nil
+			case 8620025694198835046: // thousand
+				return v, 1000, 0, nil
+			case 31525106269999860: // million
+				return v, 1000000, 0, nil
+
 			case 7290429: // nan
 				return loss, 0, 1, nil
 
 			case 7292483, 1874960827: // nil, null
 				return 0, 0, 0, nil
 
-			case 6963517: // inf
+			case 6963517, 7807181617369163882: // inf, infinity
 				return v | loss, 0, math.MaxInt64, nil
 			}
 		}
@@ -465,6 +662,11 @@ func vmeUnitOrMagicFromBytes(b []byte, v, m uint64, e int64, units []unit) (uint
 // ext is a boolean value to allow extended output (~ if loss), Inf for Infinite and NaN for not-a-number
 // str is a boolean value to add double quote before and after output
 func vmetBytesTo(b []byte, v, m uint64, e int64, places int32, t *unit, ext, str bool) []byte {
+	// infinities and not-a-number have no JSON number: they are output as is, never quoted twice nor followed by a unit
+	if !ext && m == 0 && v&loss != 0 && e != 0 && e != math.MinInt64 {
+		return veMagicBytesTo(b, v, e, ext)
+	}
+
 	if str {
 		b = append(b, '"')
 	}
@@ -504,7 +706,8 @@ func vmetBytesTo(b []byte, v, m uint64, e int64, places int32, t *unit, ext, str
 			m = r
 		}
 
-		for e += int64(i); e >= int64(places); e-- {
+		// trailing integer zeros of a positive exponent, the fractional zeros of places are added below
+		for e += int64(i); e >= 0; e-- {
 			b = append(b, '0')
 		}
 		if e0 >= 0 && places > 0 && e+int64(places) >= 0 {
@@ -517,8 +720,8 @@ func vmetBytesTo(b []byte, v, m uint64, e int64, places int32, t *unit, ext, str
 	} else {
 		if v&loss != 0 {
 			b = veMagicBytesTo(b, v, e, ext)
-			// avoid unit if infinity or not-a-number
-			if e == math.MaxInt64 || e != 0 {
+			// avoid unit if infinity or not-a-number, a signed near zero keeps it like an unsigned one
+			if e != 0 && e != math.MinInt64 {
 				if str {
 					b = append(b, '"')
 				}
@@ -571,16 +774,109 @@ func veMagicBytesTo(b []byte, v uint64, e int64, ext bool) []byte {
 			b = append(b, '~', '0')
 		}
 	} else {
+		// valid JSON: near zero is 0, infinities are the quoted "Infinity" or "-Infinity" and not-a-number is the quoted "NaN"
 		if e == 0 || e == math.MinInt64 {
 			b = append(b, '0')
+		} else if e == math.MaxInt64 {
+			if v&sign != 0 {
+				b = append(b, '"', '-', 'I', 'n', 'f', 'i', 'n', 'i', 't', 'y', '"')
+			} else {
+				b = append(b, '"', 'I', 'n', 'f', 'i', 'n', 'i', 't', 'y', '"')
+			}
 		} else {
-			b = append(b, 'n', 'u', 'l', 'l')
+			b = append(b, '"', 'N', 'a', 'N', '"')
 		}
 	}
 
 	return b
 }
 
+// mDigits returns the number of digits of a mantissa in base 10, 1 for 0.
+func mDigits(m uint64) int {
+	// iterating from 19 down to 1 always finds the first tenPow[i] <= m or falls through to a single-digit mantissa
+	for i := len(tenPow) - 1; i > 0; i-- {
+		if m >= tenPow[i] {
+			return i + 1
+		}
+	}
+
+	return 1
+}
+
+// vmeExpBytesTo appends the scientific notation of a VME tuple to b: a single leading digit, digits digits after the
+// decimal point (as many as needed if digits < 0) and a signed exponent of at least two digits introduced by exp ('e' or 'E').
+// The mantissa is rounded to the nearest like vmeRound when it has more digits than requested.
+// ext is a boolean value to allow extended output (~ if loss), magic values are output like veMagicBytesTo does.
+func vmeExpBytesTo(b []byte, v, m uint64, e int64, digits int, exp byte, ext bool) []byte {
+	var n int
+
+	if m == 0 {
+		if v&loss != 0 {
+			b = veMagicBytesTo(b, v, e, ext)
+			// no exponent if infinity or not-a-number
+			if e != 0 && e != math.MinInt64 {
+				return b
+			}
+		} else {
+			b = append(b, '0')
+		}
+		if digits > 0 {
+			b = append(b, '.')
+		}
+		n, e = 1, 0
+	} else {
+		n = mDigits(m)
+		if digits < 0 {
+			for m%10 == 0 {
+				m /= 10
+				e++
+				n--
+			}
+		} else if n-1 > digits {
+			p := tenPow[n-1-digits]
+			q, r := bits.Div64(0, m, p)
+			if (r<<1) > p || (r<<1) == p && v&sign == 0 {
+				q++
+			}
+
+			m, e, n = q, e+int64(n-1-digits), digits+1
+			// 9.99 may have been rounded to 10.0
+			if m == tenPow[n] {
+				m /= 10
+				e++
+			}
+		}
+
+		if ext && v&loss != 0 {
+			b = append(b, '~')
+		}
+		if v&sign != 0 {
+			b = append(b, '-')
+		}
+		for i := n - 1; i >= 0; i-- {
+			b = append(b, byte(m/tenPow[i]%10)+'0')
+			if i == n-1 && (i > 0 || digits > 0) {
+				b = append(b, '.')
+			}
+		}
+	}
+
+	for i := n - 1; i < digits; i++ {
+		b = append(b, '0')
+	}
+	x := e + int64(n) - 1
+	b = append(b, exp)
+	if x < 0 {
+		b = append(b, '-')
+		x = -x
+	} else {
+		b = append(b, '+')
+	}
+
+	// x is at most 32 (17 digits and exponent 15)
+	return append(b, byte(x/10)+'0', byte(x%10)+'0')
+}
+
 func vmeAddMagic1(v1 uint64, e1 int64, v2, m2 uint64, e2 int64) (v, m uint64, e int64) {
 	// m1 is already 0 and loss bit is set so check if d1 is ~0, ~+0, ~-0, NaN, -Inf or +Inf
 	switch e1 {
@@ -941,6 +1237,203 @@ func vmeDivRem(v1, m1 uint64, e1 int64, v2, m2 uint64, e2 int64, precision int32
 	return
 }
 
+// vmeQuoRem returns the VME tuples of the quotient q, truncated toward zero to a multiple of 10^-precision, and of the remainder r
+// of (v1, m1, e1) / (v2, m2, e2), such that d1 = d2 * q + r exactly, r having the sign of d1 and |r| < |d2| * 10^-precision.
+// Both are Zero when null, and they have the loss bit of the operands, or when q has more digits than a decimal can hold.
+func vmeQuoRem(v1, m1 uint64, e1 int64, v2, m2 uint64, e2 int64, precision int32) (v, m uint64, e int64, rv, rm uint64, re int64) {
+	if m1 == 0 || m2 == 0 {
+		v, m, e, rm, re = vmeDivRem(v1, m1, e1, v2, m2, e2, precision)
+
+		return v, m, e, v, rm, re
+	}
+
+	v = (v1|v2)&loss | (v1^v2)&sign
+	rv = (v1|v2)&loss | v1&sign
+	e = -int64(precision)
+
+	// both d1 and d2 * 10^-precision are integers A and B in units of 10^re, the remainder being a multiple of 10^re,
+	// with A = m1 * 10^a and B = m2 * 10^b where either a or b is 0
+	re = e1
+	if e2+e < re {
+		re = e2 + e
+	}
+	a, b := e1-re, e2+e-re
+
+	overflow := false
+	if b > 0 {
+		// A = m1 < 2^57 is less than B when B does not fit in 64 bits
+		m, rm = 0, m1
+		if b < int64(len(tenPow)) {
+			if h, l := bits.Mul64(m2, tenPow[b]); h == 0 {
+				m, rm = m1/l, m1%l
+			}
+		}
+	} else if a < int64(len(tenPow)) {
+		if h, l := bits.Mul64(m1, tenPow[a]); h < m2 {
+			m, rm = bits.Div64(h, l, m2)
+		} else {
+			overflow = true
+		}
+	} else {
+		overflow = true
+	}
+
+	if overflow {
+		// the quotient does not fit in 64 bits: long division of m1 * 10^a by m2 one digit at a time until the decimal mantissa is full,
+		// the remaining digits of the quotient being rounded to the nearest while the remainder is still below m2
+		m, rm = m1/m2, m1%m2
+		for ; a > 0; a-- {
+			d := rm * 10 / m2 // rm < m2 < 2^57 never overflows
+			if m > (MaxInt-d)/10 {
+				break
+			}
+			m, rm = m*10+d, rm*10%m2
+		}
+
+		if a > 0 {
+			e += a
+			d := rm * 10 / m2
+			rm = rm * 10 % m2
+			a--
+
+			// the dropped digits after d are not all zero when rm * 10^a >= m2
+			if d != 0 || rm != 0 && (a >= int64(len(tenPow)) || vmeMulHigh(rm, tenPow[a], m2)) {
+				v |= loss
+			}
+			if d >= 5 {
+				m++
+			}
+
+			rm = vmeMulMod(rm, vmePowMod(10, a, m2), m2)
+		}
+	}
+
+	if m == 0 {
+		v, e = sign, 0
+	}
+	if rm == 0 {
+		rv, re = sign, 0
+	}
+
+	return
+}
+
+// vmeMulHigh returns whether x * y >= m.
+func vmeMulHigh(x, y, m uint64) bool {
+	h, l := bits.Mul64(x, y)
+
+	return h != 0 || l >= m
+}
+
+// vmeMulMod returns x * y mod m, x and y being less than m.
+func vmeMulMod(x, y, m uint64) uint64 {
+	h, l := bits.Mul64(x, y)
+	_, r := bits.Div64(h, l, m) // x * y < m * 2^64 never overflows
+
+	return r
+}
+
+// vmePowMod returns x^n mod m, x being less than m.
+func vmePowMod(x uint64, n int64, m uint64) uint64 {
+	r := 1 % m
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			r = vmeMulMod(r, x, m)
+		}
+		x = vmeMulMod(x, x, m)
+	}
+
+	return r
+}
+
+// vmeDiv returns the VME tuple of (v1, m1, e1) / (v2, m2, e2) rounded to the nearest (half away from zero) with at most precision digits
+// after the decimal point, and as many digits as a mantissa of maxM and an exponent of at least minE can hold, so that it is rounded only once.
+// The loss bit is set if the division is not exact or one of the operands is not exact.
+func vmeDiv(v1, m1 uint64, e1 int64, v2, m2 uint64, e2 int64, precision int32, maxM uint64, minE int64) (v, m uint64, e int64) {
+	if m1 == 0 || m2 == 0 {
+		v, m, e, _, _ = vmeDivRem(v1, m1, e1, v2, m2, e2, precision)
+
+		return
+	}
+
+	v = v1 & ^uint64(sign|loss) | (v1|v2)&loss | (v1^v2)&sign // initialize v with v1 unit
+	e = e1 - e2
+
+	// scale m1 so that the integer part of m1 / m2 has n digits and m1 * 10^k / m2 fits 64 bits for k <= 18 - n
+	n := int64(1)
+	if m1 < m2 {
+		for m1 < m2 {
+			m1 *= 10 // m1 < m2 < 2^57 never overflows
+			e--
+		}
+	} else {
+		for q := m1 / m2; q >= 10; q /= 10 {
+			n++
+		}
+	}
+
+	// k is the number of digits of the quotient after the ones of its integer part
+	k := 18 - n
+	if p := e + int64(precision); k > p {
+		k = p
+	}
+	if p := e - minE; k > p {
+		k = p
+	}
+
+	var r uint64
+	if k >= 0 {
+		for {
+			h, l := bits.Mul64(m1, tenPow[k])
+			m, r = bits.Div64(h, l, m2)
+
+			if m <= maxM || k == 0 {
+				break
+			}
+			k--
+		}
+
+		// round to the nearest, half away from zero, r < m2 < 2^57 never overflows
+		if (r << 1) >= m2 {
+			m++
+		}
+	} else {
+		// fewer digits than the integer part: round m1 / m2 to a multiple of 10^-k
+		q, r1 := m1/m2, m1%m2
+		if -k >= int64(len(tenPow)) {
+			// m1 / m2 < 2^57 is below half of 10^-k
+			m, r = 0, 1
+		} else {
+			var r2 uint64
+
+			p := tenPow[-k]
+			m, r2 = q/p, q%p
+			r = r2 | r1
+
+			// (r2 + r1 / m2) / p >= 1/2 <=> 2 * (r2 * m2 + r1) >= p * m2
+			h, l := bits.Mul64(r2, m2)
+			l, c := bits.Add64(l, r1, 0)
+			h += c
+			h, l = h<<1|l>>63, l<<1
+			ph, pl := bits.Mul64(p, m2)
+			if h > ph || h == ph && l >= pl {
+				m++
+			}
+		}
+	}
+	e -= k
+
+	if r != 0 {
+		v |= loss
+	}
+	if m == 0 {
+		// a quotient rounded to zero is a signed near zero
+		e = math.MinInt64
+	}
+
+	return
+}
+
 func vmeRound(v, m uint64, e int64, places int32) (uint64, uint64, int64) {
 	// no rouding nan or infinity but only 0 or near 0
 	if m == 0 {
@@ -1046,9 +1539,14 @@ func vmeRoundCeil(v, m uint64, e int64, places int32) (uint64, uint64, int64) {
 							m++
 						}
 					}
+					if m == 0 {
+						return sign, 0, 0 // Zero, never a signed zero
+					}
 
 					e = -int64(places)
 				}
+			} else if v&sign == 0 {
+				return 0, 1, -int64(places) // first decimal above Zero, may overflow to +Inf
 			} else {
 				return sign, 0, 0 // Zero
 			}
@@ -1089,13 +1587,14 @@ func vmeRoundFloor(v, m uint64, e int64, places int32) (uint64, uint64, int64) {
 							m++
 						}
 					}
+					if m == 0 {
+						return sign, 0, 0 // Zero, never a signed zero
+					}
 
 					e = -int64(places)
-
-					if m == 0 && e == 0 {
-						v = sign // Zero
-					}
 				}
+			} else if v&sign != 0 {
+				return sign, 1, -int64(places) // first decimal below Zero, may overflow to -Inf
 			} else {
 				return sign, 0, 0 // Zero
 			}
@@ -1121,7 +1620,7 @@ func newFromFloat(v, m2 uint64, e2 int64) Decimal {
 
 	z := bits.TrailingZeros64(m2)
 	if z == 64 {
-		if v != 0 {
+		if v != 0 && !DenormalizeToZero {
 			return NearNegativeZero
 		} else {
 			return Zero
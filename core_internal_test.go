@@ -89,15 +89,18 @@ func TestVmetBytesToQuoted(t *testing.T) {
 }
 
 func TestVeMagicBytesToCompact(t *testing.T) {
-	// ext=false → JSON-friendly output: "0" for ~0 and ±~0; "null" for NaN and ±Inf
+	// ext=false → JSON-friendly output: "0" for ~0 and ±~0; quoted "NaN" for NaN and quoted "Infinity" for ±Inf
 	if s := string(veMagicBytesTo(nil, sign|loss, 0, false)); s != "0" {
 		t.Errorf(`veMagicBytesTo ext=false on ~0 should be "0", got %q`, s)
 	}
-	if s := string(veMagicBytesTo(nil, loss, math.MaxInt64, false)); s != "null" {
-		t.Errorf(`veMagicBytesTo ext=false on +Inf should be "null", got %q`, s)
+	if s := string(veMagicBytesTo(nil, loss, math.MaxInt64, false)); s != `"Infinity"` {
+		t.Errorf(`veMagicBytesTo ext=false on +Inf should be "\"Infinity\"", got %q`, s)
 	}
-	if s := string(veMagicBytesTo(nil, loss, 1, false)); s != "null" {
-		t.Errorf(`veMagicBytesTo ext=false on NaN should be "null", got %q`, s)
+	if s := string(veMagicBytesTo(nil, sign|loss, math.MaxInt64, false)); s != `"-Infinity"` {
+		t.Errorf(`veMagicBytesTo ext=false on -Inf should be "\"-Infinity\"", got %q`, s)
+	}
+	if s := string(veMagicBytesTo(nil, loss, 1, false)); s != `"NaN"` {
+		t.Errorf(`veMagicBytesTo ext=false on NaN should be "\"NaN\"", got %q`, s)
 	}
 	if s := string(veMagicBytesTo(nil, loss, math.MinInt64, false)); s != "0" {
 		t.Errorf(`veMagicBytesTo ext=false on +~0 should be "0", got %q`, s)
@@ -262,6 +265,13 @@ func TestVmeRoundFamilyPlacesUnderflow(t *testing.T) {
 	if v, m, e := vmeRoundFloor(0, 1, 0, -100); v != sign || m != 0 || e != 0 {
 		t.Errorf(`vmeRoundFloor underflow should be Zero, got (%x,%d,%d)`, v, m, e)
 	}
+	// while the opposite sign rounds away from zero to the first decimal above or below Zero
+	if v, m, e := vmeRoundCeil(0, 1, 0, -100); v != 0 || m != 1 || e != 100 {
+		t.Errorf(`vmeRoundCeil(1, -100) should be (0,1,100), got (%x,%d,%d)`, v, m, e)
+	}
+	if v, m, e := vmeRoundFloor(sign, 1, 0, -100); v != sign || m != 1 || e != 100 {
+		t.Errorf(`vmeRoundFloor(-1, -100) should be (sign,1,100), got (%x,%d,%d)`, v, m, e)
+	}
 
 	// And the 1004-1006 / 992-994 branches in vmeRoundBank: m << 1 < p path
 	if v, m, e := vmeRoundBank(0, 1, -3, 1); v != sign || m != 0 || e != 0 {
@@ -272,6 +282,16 @@ func TestVmeRoundFamilyPlacesUnderflow(t *testing.T) {
 	// Already covered by underflow tests above.
 }
 
+func TestVmeNormalizeExponentTrailingZeros(t *testing.T) {
+	// 100000000000000005e-18 is rounded to 1000000000000000e-16 which must be reduced to 1e-1 (~0.1)
+	if v, m, e := vmeNormalize(0, 100000000000000005, -18, MaxInt, decimalMinE, decimalMaxE); v != loss || m != 1 || e != -1 {
+		t.Errorf(`vmeNormalize(100000000000000005e-18) should be (%x,1,-1), got (%x,%d,%d)`, uint64(loss), v, m, e)
+	}
+	if d := New(100000000000000005, -18); d.String() != "~0.1" || d.Abs()&^loss != New(1, -1) {
+		t.Errorf(`New(100000000000000005, -18) should be ~0.1 with the same digits as 0.1 and not %v (%x)`, d, uint64(d))
+	}
+}
+
 func TestVmeFromBytesEdgeCases(t *testing.T) {
 	// a lone "-" must error — covers the i > j branch after parsing the sign
 	if _, _, _, err := vmeFromBytes([]byte("-"), nil); err == nil {
@@ -0,0 +1,44 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestRoundToCurrency(t *testing.T) {
+	d := New(12345675, -4) // 1234.5675
+
+	cases := []struct {
+		code string
+		want Decimal
+	}{
+		{"USD", New(123457, -2)},
+		{"EUR", New(123457, -2)},
+		{"eur", New(123457, -2)},
+		{"JPY", 1235},
+		{"BHD", New(1234568, -3)},
+		{"CLF", New(12345675, -4)},
+	}
+
+	for _, c := range cases {
+		if r, err := d.RoundToCurrency(c.code); err != nil || r != c.want {
+			t.Errorf(`%v.RoundToCurrency(%q) should be %v and not %v (err = %v)`, d, c.code, c.want, r, err)
+		}
+	}
+
+	// banker's rounding: ties go to the even digit
+	if r, _ := New(1225, -3).RoundToCurrency("USD"); r != New(122, -2) {
+		t.Errorf(`1.225.RoundToCurrency("USD") should be 1.22 and not %v`, r)
+	}
+	if r, _ := New(1235, -3).RoundToCurrency("USD"); r != New(124, -2) {
+		t.Errorf(`1.235.RoundToCurrency("USD") should be 1.24 and not %v`, r)
+	}
+	if r, _ := New(25, -1).RoundToCurrency("JPY"); r != 2 {
+		t.Errorf(`2.5.RoundToCurrency("JPY") should be 2 and not %v`, r)
+	}
+
+	for _, code := range []string{"XYZ", "", "US", "USDD"} {
+		if r, err := d.RoundToCurrency(code); err != ErrCurrency || r != d {
+			t.Errorf(`%v.RoundToCurrency(%q) should return %v and ErrCurrency and not %v (err = %v)`, d, code, d, r, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package decimal
+
+// DataSize represents a fixed-point decimal hold as a 64 bits integer including a data size unit, in bytes.
+// integer value between -9007199254740991 and 9007199254740991 (or DataSizeMaxInt) can safely be used as DataSize using 'B' unit, example :
+//
+//	var a DataSize = 4096 // a is a DataSize of value 4096B
+//
+// Note 0 is unitialized DataSize and its value for calculation is 0.
+// Note you need to use DataSize method for calculation, you cannot use + - * / or any other operators unless DataSize is a real non-zero integer value with 'B' unit.
+//
+// DataSize has the same 64 bits representation as Weight and Length: 4 bits are used to encode the unit
+// and its mantissa has 53 bits instead of Decimal mantissa of 57 bits.
+// Both the decimal (kB, MB, GB, ...) and the binary (KiB, MiB, GiB, ...) prefixes are supported,
+// as units are case-insensitive "KB" is the same unit as "kB", that is 1000 bytes.
+type DataSize int64
+
+const (
+	// DataSizeMaxInt constant is the maximal int64 value that can be safely saved as DataSize with exponent still 0.
+	// DataSizeMaxInt is as well the maximum value of mantissa of DataSize and the bitmask to extract mantissa value of a DataSize.
+	DataSizeMaxInt = quantityMaxInt
+)
+
+var (
+	dataSizeUnits = [...]unit{
+		// decimal prefixes where 'B' (byte) is the base unit
+		{u: "B", c: 0, v: 0},
+		{u: "kB", c: 3, v: 1 << quantityBitT},
+		{u: "MB", c: 6, v: 2 << quantityBitT},
+		{u: "GB", c: 9, v: 3 << quantityBitT},
+		{u: "TB", c: 12, v: 4 << quantityBitT},
+		{u: "PB", c: 15, v: 5 << quantityBitT},
+
+		{}, //  6 is reserved for future use
+		{}, //  7 is reserved for future use
+
+		// binary prefixes (IEC 80000-13), the factor is not a power of ten so it is stored with a
+		// non-zero exponent; EiB is missing as 2^60 cannot be stored this way in a 57 bits mantissa
+		{u: "KiB", c: 10240 + 31<<decimalBitE /* 1024 B */, v: 8 << quantityBitT},
+		{u: "MiB", c: 10485760 + 31<<decimalBitE /* 1048576 B */, v: 9 << quantityBitT},
+		{u: "GiB", c: 10737418240 + 31<<decimalBitE /* 1073741824 B */, v: 10 << quantityBitT},
+		{u: "TiB", c: 10995116277760 + 31<<decimalBitE /* 1099511627776 B */, v: 11 << quantityBitT},
+		{u: "PiB", c: 11258999068426240 + 31<<decimalBitE /* 1125899906842624 B */, v: 12 << quantityBitT},
+
+		{}, // 13 is reserved for future use
+		{}, // 14 is reserved for future use
+		{}, // 15 is reserved for future use
+
+		// aliases
+		{u: "bytes", c: 0, v: 0},
+	}
+)
+
+// dataSizeTable is the unit table of data sizes, an overflowing data size keeps its unit
+var dataSizeTable = &UnitTable{units: dataSizeUnits[:], fixed: true}
+
+func init() {
+	hashUnits(dataSizeUnits[:])
+}
+
+// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func (s DataSize) vmet() (v, m uint64, e int64, t *unit) {
+	return dataSizeTable.vmet(int64(s))
+}
+
+// NewDataSize returns a new fixed-point decimal data size, value * 10 ^ exp using unit.
+func NewDataSize(value int64, exp int32, unit string) (DataSize, error) {
+	s, err := dataSizeTable.fromInt(value, exp, unit)
+
+	return DataSize(s), err
+}
+
+// NewDataSizeFromDecimal converts a Decimal to DataSize using unit.
+func NewDataSizeFromDecimal(value Decimal, unit string) (DataSize, error) {
+	s, err := dataSizeTable.fromDecimal(value, unit)
+
+	return DataSize(s), err
+}
+
+// NewDataSizeFromBytes returns a new DataSize from a slice of bytes representation.
+//
+// If no data size unit is given, 'B' is assumed.
+func NewDataSizeFromBytes(value []byte) (DataSize, error) {
+	s, err := dataSizeTable.fromBytes(value)
+
+	return DataSize(s), err
+}
+
+// NewDataSizeFromString returns a new DataSize from a string representation.
+//
+// If no data size unit is given, 'B' is assumed.
+//
+// Example:
+//
+//	s, err := NewDataSizeFromString("4096")
+//	s2, err := NewDataSizeFromString("1.5MiB")
+//	s3, err := NewDataSizeFromString("700 MB")
+func NewDataSizeFromString(value string) (DataSize, error) {
+	return NewDataSizeFromBytes([]byte(value))
+}
+
+// Unit returns unit string of s.
+func (s DataSize) Unit() string {
+	_, _, _, t := s.vmet()
+
+	return t.u
+}
+
+// Bytes returns the number of bytes of s as a Decimal, 1.5KiB giving 1536.
+func (s DataSize) Bytes() Decimal {
+	b, _ := s.In("B")
+
+	return b.value()
+}
+
+// value returns the scalar value of s in its own unit
+func (s DataSize) value() Decimal {
+	return quantityValue(int64(s))
+}
+
+// In returns s converted to unit, it returns ErrUnitSyntax for an unknown unit.
+//
+// Example:
+//
+//	s, err := NewDataSizeFromString("1MiB")
+//	println(s.In("KiB"))
+//	println(s.In("kB"))
+//
+// Output:
+//
+//	1024KiB
+//	1048.576kB
+func (s DataSize) In(unit string) (DataSize, error) {
+	r, err := dataSizeTable.in(int64(s), unit)
+
+	return DataSize(r), err
+}
+
+// To returns s converted to unit like In does.
+func (s DataSize) To(unit string) (DataSize, error) {
+	return s.In(unit)
+}
+
+// Add returns s1 + s2 using s1 unit.
+func (s1 DataSize) Add(s2 DataSize) DataSize {
+	return DataSize(dataSizeTable.add(int64(s1), int64(s2)))
+}
+
+// Sub returns s1 - s2 using s1 unit.
+func (s1 DataSize) Sub(s2 DataSize) DataSize {
+	return s1.Add(-s2)
+}
+
+// Mul returns s * d using s unit.
+func (s DataSize) Mul(d Decimal) DataSize {
+	return DataSize(dataSizeTable.mul(int64(s), d))
+}
+
+// String returns the string representation of the data size with the fixed point and unit.
+func (s DataSize) String() string {
+	return string(s.BytesTo(nil))
+}
+
+// BytesTo appends the string representation of the data size to a slice of byte.
+func (s DataSize) BytesTo(b []byte) []byte {
+	return dataSizeTable.bytesTo(b, int64(s), true)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s DataSize) MarshalJSON() ([]byte, error) {
+	return dataSizeTable.bytesTo(nil, int64(s), false), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DataSize) UnmarshalJSON(b []byte) error {
+	if _s, err := dataSizeTable.fromBytes(b); err == nil {
+		*s = DataSize(_s)
+
+		return nil
+	} else {
+		return err
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for XML deserialization.
+func (s *DataSize) UnmarshalText(text []byte) error {
+	if _s, err := NewDataSizeFromBytes(text); err != nil {
+		return err
+	} else {
+		*s = _s
+
+		return nil
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for XML serialization.
+func (s DataSize) MarshalText() (text []byte, err error) {
+	return s.BytesTo(nil), nil
+}
+
+// IsZero return
+//
+//	true if s == Null or s == Zero
+//	true if s == ~0 or s == -~0 or s == +~0
+//	false if s < 0
+//	false if s > 0
+func (s DataSize) IsZero() bool {
+	return quantityIsZero(int64(s))
+}
+
+// IsPositive return
+//
+//	true if s > 0 or s == ~+0
+//	false if s == Null or s == Zero or s == ~0
+//	false if s < 0 or s == ~-0
+//	false if s is NaN
+func (s DataSize) IsPositive() bool {
+	return s > 0 && !s.IsNaN()
+}
+
+// IsNaN return
+//
+//	true if s is not a number (NaN)
+//	false in any other case
+func (s DataSize) IsNaN() bool {
+	return quantityIsNaN(int64(s))
+}
+
+// Compare compares the sizes represented by s1 and s2 whatever their units without taking into account lost precision and returns:
+//
+//	-1 if s1 <  s2
+//	 0 if s1 == s2
+//	+1 if s1 >  s2
+func (s1 DataSize) Compare(s2 DataSize) int {
+	return dataSizeTable.compare(int64(s1), int64(s2))
+}
@@ -0,0 +1,130 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestDataSizeConversions(t *testing.T) {
+	cases := []struct {
+		s     string
+		str   string
+		bytes Decimal
+	}{
+		{"1MiB", "1MiB", 1048576},
+		{"1.5MiB", "1.5MiB", 1572864},
+		{"1KiB", "1KiB", 1024},
+		{"2GiB", "2GiB", 2147483648},
+		{"1TiB", "1TiB", 1099511627776},
+		{"1PiB", "1PiB", 1125899906842624},
+		{"1kB", "1kB", 1000},
+		{"1KB", "1kB", 1000},
+		{"700 MB", "700MB", 700000000},
+		{"1.44MB", "1.44MB", 1440000},
+		{"4096", "4096B", 4096},
+		{"12 bytes", "12B", 12},
+		{"-3KiB", "-3KiB", -3072},
+		{"0MiB", "0MiB", Zero},
+	}
+
+	for _, c := range cases {
+		s, err := NewDataSizeFromString(c.s)
+		if err != nil {
+			t.Errorf(`NewDataSizeFromString(%q) has result = %v and error = %v`, c.s, s, err)
+			continue
+		}
+
+		if s.String() != c.str {
+			t.Errorf(`NewDataSizeFromString(%q) should be %s and not %v`, c.s, c.str, s)
+		}
+		if b := s.Bytes(); !b.Equal(c.bytes) {
+			t.Errorf(`%v.Bytes() should be %v and not %v`, s, c.bytes, b)
+		}
+
+		// String round-trips
+		if r, err := NewDataSizeFromString(s.String()); err != nil || r != s {
+			t.Errorf(`NewDataSizeFromString(%q) should be %v and not %v (err = %v)`, s.String(), s, r, err)
+		}
+	}
+
+	for _, s := range []string{"1EiB", "1Mb/s", "1.5 MiBs", "1m"} {
+		if d, err := NewDataSizeFromString(s); err == nil {
+			t.Errorf(`NewDataSizeFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+}
+
+func TestDataSizeTo(t *testing.T) {
+	cases := []struct {
+		s, unit, want string
+	}{
+		{"1MiB", "KiB", "1024KiB"},
+		{"1MiB", "kB", "1048.576kB"},
+		{"1MiB", "B", "1048576B"},
+		{"1.5MiB", "KiB", "1536KiB"},
+		{"1GiB", "MiB", "1024MiB"},
+		{"1GB", "MB", "1000MB"},
+		{"1MB", "KiB", "976.5625KiB"},
+		{"1536", "KiB", "1.5KiB"},
+		{"1000KiB", "kB", "1024kB"},
+		{"1B", "KiB", "0.0009765625KiB"},
+		{"1GB", "MiB", "953.67431640625MiB"},
+		{"1TB", "GiB", "~931.322574615479GiB"},
+		{"1kB", "GiB", "~0.0000009313225746GiB"},
+	}
+
+	for _, c := range cases {
+		s, _ := NewDataSizeFromString(c.s)
+
+		if r, err := s.In(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.In(%q) should be %s and not %v (err = %v)`, s, c.unit, c.want, r, err)
+		} else if r.Compare(s) != 0 {
+			t.Errorf(`%v.Compare(%v) should be 0 and not %d`, r, s, r.Compare(s))
+		}
+		if r, err := s.To(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.To(%q) should be %s and not %v (err = %v)`, s, c.unit, c.want, r, err)
+		}
+	}
+
+	s, _ := NewDataSizeFromString("1MiB")
+	if _, err := s.To("MiBs"); err != ErrUnitSyntax {
+		t.Errorf(`%v.To("MiBs") should return ErrUnitSyntax and not %v`, s, err)
+	}
+
+	// 1MiB is more than 1MB, binary and decimal units are compared by their byte count
+	mb, _ := NewDataSizeFromString("1MB")
+	if s.Compare(mb) != 1 || mb.Compare(s) != -1 {
+		t.Errorf(`1MiB should be greater than 1MB`)
+	}
+	if kib, _ := NewDataSizeFromString("1024KiB"); s.Compare(kib) != 0 {
+		t.Errorf(`1MiB should be equal to 1024KiB`)
+	}
+
+	// arithmetic keeps the unit of the first operand
+	kb, _ := NewDataSizeFromString("24KiB")
+	if r := s.Sub(kb); r.String() != "0.9765625MiB" {
+		t.Errorf(`1MiB - 24KiB should be 0.9765625MiB and not %v`, r)
+	}
+	if r := kb.Add(s).Mul(2); r.String() != "2096KiB" {
+		t.Errorf(`(24KiB + 1MiB) * 2 should be 2096KiB and not %v`, r)
+	}
+}
+
+func TestDataSizeMarshal(t *testing.T) {
+	for _, str := range []string{"1.5MiB", "-3KiB", "700MB", "4096B", "0GiB"} {
+		s, _ := NewDataSizeFromString(str)
+
+		var r DataSize
+		if b, err := s.MarshalText(); err != nil || string(b) != str {
+			t.Errorf(`%v.MarshalText() should be %s and not %s (err = %v)`, s, str, b, err)
+		} else if err := r.UnmarshalText(b); err != nil || r != s {
+			t.Errorf(`UnmarshalText(%s) should be %v and not %v (err = %v)`, b, s, r, err)
+		}
+
+		r = 0
+		if b, err := s.MarshalJSON(); err != nil {
+			t.Errorf(`%v.MarshalJSON() should be ok, error = %v`, s, err)
+		} else if err := r.UnmarshalJSON(b); err != nil || r != s {
+			t.Errorf(`UnmarshalJSON(%s) should be %v and not %v (err = %v)`, b, s, r, err)
+		}
+	}
+}
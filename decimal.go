@@ -6,11 +6,20 @@ package decimal
 import (
 	"errors"
 
+	"bytes"
 	"database/sql/driver"
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
 	"math"
 	"math/bits"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Decimal represents a fixed-point decimal hold as a 64 bits integer
@@ -86,9 +95,43 @@ var (
 	// ErrFormatcan occurs when decoding a binary to a decimal.
 	ErrFormat = errors.New("invalid format")
 
+	// ErrDivisionByZero occurs when a helper returning an error is asked to divide by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+
+	// ErrInexact occurs when a checked operation on exact decimals has to round its result, see AddChecked.
+	ErrInexact = errors.New("inexact result")
+
+	// ErrTooLong occurs when a string to convert to a decimal is longer than MaxParseLength.
+	ErrTooLong = errors.New("input too long")
+
+	// ErrLengthMismatch occurs when a helper working on two slices is given slices of different lengths.
+	ErrLengthMismatch = errors.New("length mismatch")
+
+	// DenormalizeToZero makes a result whose magnitude is smaller than SmallestPositive() an exact Zero
+	// instead of NearPositiveZero or NearNegativeZero, a float64 -0 included. Either way IsZero is true, but the sentinels keep
+	// the sign of the value for Sign, IsPositive and IsNegative and are not exact, while Zero has a Sign of 0 and is exact.
+	// Like the other package variables, it is meant to be set once at start-up, not while decimals are computed.
+	DenormalizeToZero = false
+
 	// DivisionPrecision has the number of decimal places in the result when it doesn't divide exactly.
+	// It is shared by all the goroutines, concurrent code needing another precision should use a Context instead of changing it.
 	DivisionPrecision = 16
 
+	// MaxParseLength is the maximal length in bytes of a string converted to a decimal, weight or length,
+	// longer inputs are rejected with ErrTooLong before being parsed. Zero or a negative value disables the check.
+	MaxParseLength = 512
+
+	// MarshalJSONAsString makes MarshalJSON quote its output, "123.456" instead of 123.456.
+	// A bare JSON number is read as a float64 by most JSON decoders (JavaScript's JSON.parse among them)
+	// which silently drops digits past 15 or 16 significant ones, while a string keeps all 17 digits
+	// but requires the client to parse it explicitly. UnmarshalJSON accepts both forms.
+	MarshalJSONAsString = false
+
+	// DecimalSeparator is the decimal separator accepted by the parsers besides '.' and output by String and the %v, %s and %q verbs, '.' by default.
+	// Setting it to ',' is a deliberate global opt-in for applications where "1,5" is always 1.5, it is not meant to be changed
+	// while decimals are parsed or formatted. JSON, MarshalText and the other formatting methods keep '.' so that their output can be read back anywhere.
+	DecimalSeparator = '.'
+
 	// PowPrecisionNegativeExponent has the maximum precision (digits after the decimal point) of the result of PowInt32 when the exponent is negative.
 	PowPrecisionNegativeExponent = 16
 )
@@ -107,19 +150,7 @@ func (d Decimal) Mantissa() int64 {
 // or its exponent would be out of range, so 100 is kept as mantissa=100 (NumDigits=3) while 10^18 is normalized to mantissa=1, exp=18 (NumDigits=1).
 // Null, Zero, NearZero, NearPositiveZero, NearNegativeZero, +Inf, -Inf and NaN return 1.
 func (d Decimal) NumDigits() int {
-	m := uint64(d.Mantissa())
-	if m == 0 {
-		return 1
-	}
-
-	// mantissa is always <= MaxInt (~1.44e17 < 10^18), so iterating from 19 down to 1 always finds the first tenPow[i] <= m or falls through to a single-digit mantissa
-	for i := len(tenPow) - 1; i > 0; i-- {
-		if m >= tenPow[i] {
-			return i + 1
-		}
-	}
-
-	return 1
+	return mDigits(uint64(d.Mantissa()))
 }
 
 // Exponent returns the exponent, or scale component of the decimal.
@@ -185,6 +216,10 @@ func vmeAsDecimal(v, m uint64, e int64) Decimal {
 	} else {
 		v, m, e = vmeNormalize(v, m, e, MaxInt, decimalMinE, decimalMaxE)
 
+		if m == 0 && e == decimalMinE && DenormalizeToZero {
+			return Zero
+		}
+
 		// out-of-range cannot occurs as normalization has been done
 		v |= m | uint64(e<<decimalBitE)&decimalEBitmask
 
@@ -196,6 +231,12 @@ func vmeAsDecimal(v, m uint64, e int64) Decimal {
 	}
 }
 
+// SmallestPositive returns the smallest positive decimal, 1e-16: a result of smaller magnitude is NearPositiveZero or NearNegativeZero,
+// or Zero if DenormalizeToZero is set.
+func SmallestPositive() Decimal {
+	return New(1, decimalMinE)
+}
+
 // Abs returns the absolute value of the decimal.
 func (d Decimal) Abs() Decimal {
 	if d < 0 {
@@ -271,23 +312,69 @@ func (d1 Decimal) Mul(d2 Decimal) Decimal {
 	return vmeAsDecimal(vmeMul(v1, m1, e1, v2, m2, e2))
 }
 
-// Div returns d1 / d2. If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
+// Div returns d1 / d2. If it doesn't divide exactly, the result is rounded to the nearest (half away from zero) with at most DivisionPrecision digits
+// after the decimal point, or as many as the mantissa can hold, and loss bit will be set.
 func (d1 Decimal) Div(d2 Decimal) Decimal {
 	v1, m1, e1 := d1.vme()
 	v2, m2, e2 := d2.vme()
 
-	v, m, e, rem, _ := vmeDivRem(v1, m1, e1, v2, m2, e2, int32(DivisionPrecision))
-
-	if rem != 0 {
-		v |= loss
+	// fast path: dividing by a power of ten only lowers the exponent, as long as no digit goes beyond DivisionPrecision
+	if m1 != 0 && m2 != 0 {
+		p, e := m2, e1-e2
+		for p%10 == 0 {
+			p /= 10
+			e--
+		}
 
-		// fix m so that the result is the nearest, like in shopspring/decimal
-		if (rem << 1) >= m2 {
-			m++
+		if p == 1 && e >= -int64(DivisionPrecision) {
+			return vmeAsDecimal(v1&^uint64(sign|loss)|(v1|v2)&loss|(v1^v2)&sign, m1, e)
 		}
 	}
 
-	return vmeAsDecimal(v, m, e)
+	return vmeAsDecimal(vmeDiv(v1, m1, e1, v2, m2, e2, int32(DivisionPrecision), MaxInt, decimalMinE))
+}
+
+// AddChecked returns d1 + d2 like Add, with an error telling what went wrong when the result is not the exact sum:
+// ErrOutOfRange if it saturated to +Inf, -Inf, +~0 or -~0 (or became NaN) while neither d1 nor d2 was,
+// ErrInexact if the loss bit got set while both d1 and d2 were exact. The result is returned in any case.
+func (d1 Decimal) AddChecked(d2 Decimal) (Decimal, error) {
+	return checked(d1, d2, d1.Add(d2))
+}
+
+// SubChecked returns d1 - d2 like Sub, with the errors of AddChecked.
+func (d1 Decimal) SubChecked(d2 Decimal) (Decimal, error) {
+	return checked(d1, d2, d1.Sub(d2))
+}
+
+// MulChecked returns d1 * d2 like Mul, with the errors of AddChecked.
+func (d1 Decimal) MulChecked(d2 Decimal) (Decimal, error) {
+	return checked(d1, d2, d1.Mul(d2))
+}
+
+// DivChecked returns d1 / d2 like Div, with the errors of AddChecked, ErrInexact meaning the quotient was rounded to DivisionPrecision digits.
+// A division by zero returns NaN and ErrDivisionByZero.
+func (d1 Decimal) DivChecked(d2 Decimal) (Decimal, error) {
+	if d2.IsExactlyZero() {
+		return NaN, ErrDivisionByZero
+	}
+
+	return checked(d1, d2, d1.Div(d2))
+}
+
+// checked returns d, the result of an operation on d1 and d2, with the error of AddChecked
+func checked(d1, d2, d Decimal) (Decimal, error) {
+	saturated := func(d Decimal) bool {
+		return d.IsInfinite() || d == NearPositiveZero || d == NearNegativeZero || d.IsNaN()
+	}
+
+	if saturated(d) && !saturated(d1) && !saturated(d2) {
+		return d, ErrOutOfRange
+	}
+	if !d.IsExact() && d1.IsExact() && d2.IsExact() {
+		return d, ErrInexact
+	}
+
+	return d, nil
 }
 
 // QuoRem does division with remainder
@@ -297,14 +384,15 @@ func (d1 Decimal) Div(d2 Decimal) Decimal {
 //	0 <= r < abs(d2) * 10 ^(-precision) if d1 >= 0
 //	0 >= r > -abs(d2) * 10 ^(-precision) if d1 < 0
 //
-// Note that precision<0 is allowed as input.
+// Note that precision<0 is allowed as input. The equality is exact unless q has more digits than a decimal can hold,
+// q being then rounded with loss bit set.
 func (d1 Decimal) QuoRem(d2 Decimal, precision int32) (Decimal, Decimal) {
 	v1, m1, e1 := d1.vme()
 	v2, m2, e2 := d2.vme()
 
-	v, m, e, rem, reme := vmeDivRem(v1, m1, e1, v2, m2, e2, precision)
+	v, m, e, rv, rm, re := vmeQuoRem(v1, m1, e1, v2, m2, e2, precision)
 
-	return vmeAsDecimal(v, m, e), vmeAsDecimal(v, rem, reme)
+	return vmeAsDecimal(v, m, e), vmeAsDecimal(rv, rm, re)
 }
 
 // Mod returns d1 % d2.
@@ -362,6 +450,24 @@ func (d1 Decimal) Equal(d2 Decimal) bool {
 	return d.IsZero()
 }
 
+// EqualAtScale returns whether d1 and d2 agree once both are rounded to places decimal places, i.e. d1.Round(places).Equal(d2.Round(places)),
+// which is the "do these match to the cent" check of a reconciliation between systems rounding differently.
+// NaN is never equal to anything, infinities are equal when they have the same sign.
+//
+// Example:
+//
+//	NewFromFloat(10.004).EqualAtScale(NewFromFloat(9.996), 2) // true, both are 10.00
+//	NewFromFloat(10.01).EqualAtScale(NewFromFloat(10.02), 2)  // false
+func (d1 Decimal) EqualAtScale(d2 Decimal, places int32) bool {
+	if d1.IsNaN() || d2.IsNaN() {
+		return false
+	} else if d1.IsInfinite() || d2.IsInfinite() {
+		return d1 == d2
+	}
+
+	return d1.Round(places).Equal(d2.Round(places))
+}
+
 // Compare compares the numbers represented by d1 and d2 without taking into account lost precision and returns:
 //
 //	-1 if d1 <  d2
@@ -384,6 +490,79 @@ func (d1 Decimal) Cmp(d2 Decimal) int {
 	return d1.Compare(d2)
 }
 
+// Diff returns a human readable description of how b differs from a, or an empty string if a and b are identical
+// (same value, same exactness), intended for test failure messages and debugging.
+// The distance is given in ULP (unit in the last place) of the finer of a and b when it is small.
+//
+// Example:
+//
+//	Diff(2, RequireFromString("~1.9999999999999998")) // "a is 2 (exact), b is ~1.9999999999999998 (inexact, 2 ULP below a)"
+//	Diff(2, 2)                                         // ""
+func Diff(a, b Decimal) string {
+	if a == b || a.IsNaN() && b.IsNaN() && a.IsExact() == b.IsExact() {
+		return ""
+	}
+
+	s := "a is " + a.String() + " (" + diffExactness(a) + "), b is " + b.String() + " (" + diffExactness(b) + ", "
+
+	switch {
+	case a.IsNaN() || b.IsNaN():
+		return s + "not comparable to a)"
+	case a.IsInfinite() || b.IsInfinite():
+		if b.IsInfinite() && a.IsInfinite() && b.Sign() == a.Sign() {
+			return s + "same value as a)"
+		} else if b.IsInfinite() && b.Sign() > 0 || a.IsInfinite() && a.Sign() < 0 {
+			return s + "above a)"
+		} else {
+			return s + "below a)"
+		}
+	}
+
+	c := b.Compare(a)
+	if c == 0 {
+		return s + "same value as a)"
+	}
+
+	where := " above a"
+	if c < 0 {
+		where = " below a"
+	}
+	if a.Sign()*b.Sign() < 0 {
+		where += ", opposite sign"
+	}
+
+	// the distance is computed between the stored values, loss bits cleared, so that it is exact if possible
+	// and the ULP is the one of the finer of a and b, only non zero values having a meaningful exponent
+	var e int64 = math.MaxInt64
+	x := [...]Decimal{a, b}
+	for i := range x {
+		if v, m, _e := x[i].vme(); m != 0 {
+			x[i] = vmeAsDecimal(v&^loss, m, _e)
+			if _e < e {
+				e = _e
+			}
+		}
+	}
+	d := x[1].Sub(x[0]).Abs()
+
+	if e != math.MaxInt64 {
+		if ulps := d.Shift(-int32(e)); ulps.LessThanOrEqual(1000000) {
+			return s + ulps.String() + " ULP" + where + ")"
+		}
+	}
+
+	return s + d.String() + where + ")"
+}
+
+// diffExactness returns "exact" or "inexact" according to the loss bit of d.
+func diffExactness(d Decimal) string {
+	if d.IsExact() {
+		return "exact"
+	}
+
+	return "inexact"
+}
+
 // GreaterThan returns true when d1 is greater than d2 (d1 > d2).
 func (d1 Decimal) GreaterThan(d2 Decimal) bool {
 	d := d1.Sub(d2)
@@ -412,6 +591,11 @@ func (d1 Decimal) LessThanOrEqual(d2 Decimal) bool {
 func (d Decimal) Round(places int32) Decimal {
 	v, m, e := d.vme()
 
+	// an exact decimal with no more than places digits after the decimal point is already rounded
+	if v&loss == 0 && m != 0 && e+int64(places) >= 0 {
+		return d
+	}
+
 	return vmeAsDecimal(vmeRound(v, m, e, places))
 }
 
@@ -424,6 +608,11 @@ func (d Decimal) Ceil() Decimal {
 func (d Decimal) RoundCeil(places int32) Decimal {
 	v, m, e := d.vme()
 
+	// an exact decimal with no more than places digits after the decimal point is already rounded
+	if v&loss == 0 && m != 0 && e+int64(places) >= 0 {
+		return d
+	}
+
 	return vmeAsDecimal(vmeRoundCeil(v, m, e, places))
 }
 
@@ -443,6 +632,11 @@ func (d Decimal) Floor() Decimal {
 func (d Decimal) RoundFloor(places int32) Decimal {
 	v, m, e := d.vme()
 
+	// an exact decimal with no more than places digits after the decimal point is already rounded
+	if v&loss == 0 && m != 0 && e+int64(places) >= 0 {
+		return d
+	}
+
 	return vmeAsDecimal(vmeRoundFloor(v, m, e, places))
 }
 
@@ -513,6 +707,30 @@ func (d Decimal) Shift(shift int32) Decimal {
 	return vmeAsDecimal(v, m, e+int64(shift))
 }
 
+// Percent returns the decimal expressed as a percentage, d * 100, for display (0.5 gives 50).
+// It is the inverse of parsing a percent suffix, NewFromString("50%") being 0.5.
+func (d Decimal) Percent() Decimal {
+	return d.Mul(100)
+}
+
+// PercentOf returns how many percent of whole d is, d / whole * 100 (15 of 60 gives 25), rounded once like Div.
+// A whole of zero gives NaN like Div.
+func (d Decimal) PercentOf(whole Decimal) Decimal {
+	return d.Shift(2).Div(whole)
+}
+
+// IncreaseByPercent returns d increased by p percent, d * (1 + p / 100), rounded at most once:
+// New(25, 0).IncreaseByPercent(New(20, 0)) is exactly 30.
+func (d Decimal) IncreaseByPercent(p Decimal) Decimal {
+	return d.Mul(p.Add(100)).Shift(-2)
+}
+
+// DecreaseByPercent returns d decreased by p percent, d * (1 - p / 100), rounded at most once:
+// New(30, 0).DecreaseByPercent(New(20, 0)) is exactly 24.
+func (d Decimal) DecreaseByPercent(p Decimal) Decimal {
+	return d.Mul(Decimal(100).Sub(p)).Shift(-2)
+}
+
 // RoundBank rounds the decimal to places decimal places.
 // If the final digit to round is equidistant from the nearest two integers the
 // rounded value is taken as the even number
@@ -530,9 +748,38 @@ func (d Decimal) Shift(shift int32) Decimal {
 func (d Decimal) RoundBank(places int32) Decimal {
 	v, m, e := d.vme()
 
+	// an exact decimal with no more than places digits after the decimal point is already rounded
+	if v&loss == 0 && m != 0 && e+int64(places) >= 0 {
+		return d
+	}
+
 	return vmeAsDecimal(vmeRoundBank(v, m, e, places))
 }
 
+// RoundSQL rounds the decimal to places decimal places the way a database ROUND function does,
+// to reproduce database side rounding in consistency checks.
+//
+// With halfEven false, ties are rounded away from zero like ROUND of SQL Server, MySQL (exact values) and PostgreSQL (numeric),
+// so it differs from Round for negative ties only. With halfEven true, ties are rounded to the even digit like RoundBank,
+// which matches PostgreSQL round on double precision and Oracle ROUND on BINARY_DOUBLE.
+//
+// Examples:
+//
+//	New(-25, -1).RoundSQL(0, false).String() // output: "-3"
+//	New(-25, -1).Round(0).String()           // output: "-2"
+//	New(25, -1).RoundSQL(0, true).String()   // output: "2"
+func (d Decimal) RoundSQL(places int32, halfEven bool) Decimal {
+	if halfEven {
+		return d.RoundBank(places)
+	}
+
+	if d.IsNegative() {
+		return d.Neg().Round(places).Neg()
+	}
+
+	return d.Round(places)
+}
+
 // RoundCash rounds the decimal to the nearest multiple of the given Cash interval (in units of 10^(-2), or hundredths).
 // Valid intervals are 5, 10, 25, 50 and 100 (Swedish/cash rounding). Panics for any other interval.
 //
@@ -564,6 +811,71 @@ func (d Decimal) RoundCash(interval uint8) Decimal {
 	return d.Mul(factor).Round(0).Div(factor)
 }
 
+// ToCents returns d as a number of cents (hundredths), rounded with banker's rounding like d.Mul(100).RoundBank(0).IntPartErr().
+// ErrOutOfRange is returned for infinities, not-a-number or an amount which does not fit in an int64.
+//
+// Example:
+//
+//	New(12345, -3).ToCents() // 1234, nil
+func (d Decimal) ToCents() (int64, error) {
+	return d.Mul(100).RoundBank(0).IntPartErr()
+}
+
+// FromCents returns the decimal amount of c cents (hundredths), FromCents(1234) being 12.34.
+func FromCents(c int64) Decimal {
+	return New(c, -2)
+}
+
+// Allocate splits d into n parts summing exactly to d, as when splitting a bill: the parts are multiples of a cent,
+// or of the last decimal place of d if it has more than two, and differ by at most one such minor unit,
+// the first parts getting the larger share of the remainder so that the split is deterministic. It returns nil if n <= 0.
+// The sum is exact as long as a part fits in the 17 significant digits of a decimal, infinities and not-a-number are split as d.Div(n).
+//
+// Example:
+//
+//	New(100, 0).Allocate(3) // [33.34 33.33 33.33]
+//	New(-1, -2).Allocate(3) // [-0.01 0 0]
+func (d Decimal) Allocate(n int) []Decimal {
+	if n <= 0 {
+		return nil
+	}
+
+	parts := make([]Decimal, n)
+
+	_, m, e := d.vme()
+	if m == 0 {
+		for i := range parts {
+			parts[i] = d.Div(Decimal(n))
+		}
+
+		return parts
+	}
+
+	// q is d / n truncated to the minor unit, the remainder r holds k < n minor units which go one by one to the first parts
+	places := int32(2)
+	if e < -2 {
+		places = int32(-e)
+	}
+
+	q, r := d.QuoRem(NewFromInt(int64(n)), places)
+	k := r.Abs().Shift(places).IntPart()
+
+	unit := New(1, -places)
+	if d.IsNegative() {
+		unit = unit.Neg()
+	}
+
+	for i := range parts {
+		if int64(i) < k {
+			parts[i] = q.Add(unit)
+		} else {
+			parts[i] = q
+		}
+	}
+
+	return parts
+}
+
 // IsNull return
 //
 //	true if d == Null
@@ -672,7 +984,7 @@ func (d Decimal) IsNaN() bool {
 		// nan numbers (nan boxing) :
 		//   0x42 to 0x5c : exponant 1 to 14
 		//   0x62 to 0x7e : exponant -15 to -1
-		return u >= 0x42 && u < 0x5c || u >= 0x62 && u <= 0x7e
+		return u >= 0x42 && u <= 0x5c || u >= 0x62 && u <= 0x7e
 	}
 
 	return false
@@ -757,6 +1069,85 @@ func (d Decimal) IntPartErr() (int64, error) {
 	}
 }
 
+// ToQ returns d as a signed Q-format fixed-point integer with fractionalBits bits after the binary point, as used by DSP buffers:
+// d * 2^fractionalBits rounded to the nearest integer, half away from zero. Q15 and Q31 values use 15 and 31 fractional bits.
+// ErrOutOfRange is returned when fractionalBits is more than 63, when d is infinite or not-a-number, or when the result overflows an int64.
+//
+// Example:
+//
+//	q, err := New(5, -1).ToQ(15)  // q = 16384
+//	q2, err := New(-25, -2).ToQ(31) // q2 = -536870912
+func (d Decimal) ToQ(fractionalBits uint) (int64, error) {
+	v, m, e := d.vme()
+
+	if fractionalBits > 63 {
+		return 0, ErrOutOfRange
+	}
+	if m == 0 {
+		if v&loss != 0 && e != 0 && e != math.MinInt64 {
+			return 0, ErrOutOfRange
+		}
+
+		return 0, nil
+	}
+
+	var q uint64
+	if e >= 0 {
+		hi, lo := bits.Mul64(m, tenPow[e])
+		if hi != 0 || lo>>(64-fractionalBits) != 0 {
+			return 0, ErrOutOfRange
+		}
+		q = lo << fractionalBits
+	} else {
+		// m has at most 57 bits so that m << fractionalBits fits in 128 bits
+		hi, lo := m>>(64-fractionalBits), m<<fractionalBits
+
+		p := tenPow[-e]
+		if hi >= p {
+			return 0, ErrOutOfRange
+		}
+
+		var r uint64
+		q, r = bits.Div64(hi, lo, p)
+		if r >= p-r {
+			if q++; q == 0 {
+				return 0, ErrOutOfRange
+			}
+		}
+	}
+
+	if v&sign != 0 {
+		if q > 1<<63 {
+			return 0, ErrOutOfRange
+		}
+
+		return int64(-q), nil
+	}
+
+	if q > math.MaxInt64 {
+		return 0, ErrOutOfRange
+	}
+
+	return int64(q), nil
+}
+
+// FromQ returns the decimal value of the signed Q-format fixed-point integer q with fractionalBits bits after the binary point,
+// q / 2^fractionalBits. The result is exact when it has no more than DivisionPrecision digits after the decimal point.
+//
+// Example:
+//
+//	d := FromQ(16384, 15) // d = 0.5
+func FromQ(q int64, fractionalBits uint) Decimal {
+	d := NewFromInt(q)
+
+	// a power of two divisor is exact as a decimal up to 2^56
+	for ; fractionalBits > 32; fractionalBits -= 32 {
+		d = d.Div(1 << 32)
+	}
+
+	return d.Div(Decimal(1) << fractionalBits)
+}
+
 // Float64 returns the nearest float64 value for d and a bool indicating whether f may represents d exactly.
 func (d Decimal) Float64() (f float64, exact bool) {
 	v, m, e := d.vme()
@@ -839,7 +1230,8 @@ func (d Decimal) Ln(precision int32) Decimal {
 	return NewFromFloat64Exact(math.Log(f), x).Round(precision)
 }
 
-// Sqrt computes the (possibly rounded) square root of a decimal.
+// Sqrt computes the square root of a decimal rounded to the nearest decimal, the loss bit being set if it is not exact.
+// It is computed with a Newton iteration on the mantissa (seeded by the float64 square root) so that all the digits of the result are correct.
 //
 // Special cases are:
 //
@@ -848,9 +1240,73 @@ func (d Decimal) Ln(precision int32) Decimal {
 //	Sqrt(x < 0) = NaN
 //	Sqrt(NaN) = NaN
 func (d Decimal) Sqrt() Decimal {
-	f, x := d.Float64()
+	v, m, e := d.vme()
+
+	if m == 0 {
+		if v&loss != 0 && v&sign != 0 && e == math.MaxInt64 {
+			return NaN
+		}
+
+		// zeros, near zeros, +Inf and NaN are their own square root
+		return d
+	} else if v&sign != 0 {
+		return NaN
+	}
+
+	// scale m to n = m * 10^k of 37 or 38 digits with an even exponent e - k, so that its square root has 19 digits
+	k := 38 - int64(mDigits(m))
+	if (e-k)&1 != 0 {
+		k--
+	}
+	var hi, lo uint64
+	if k > 19 {
+		var c uint64
+
+		hi, lo = bits.Mul64(m, tenPow[19])
+		c, lo = bits.Mul64(lo, tenPow[k-19])
+		hi = hi*tenPow[k-19] + c
+	} else {
+		hi, lo = bits.Mul64(m, tenPow[k])
+	}
 
-	return NewFromFloat64Exact(math.Sqrt(f), x)
+	// Newton iteration from above the square root: x = (x + n/x) / 2 decreases until x = floor(sqrt(n))
+	s := uint64(math.Sqrt(float64(hi)*(1<<64) + float64(lo)))
+	x := s + s>>40 + 1
+	for {
+		q, _ := bits.Div64(hi, lo, x)
+		if q >= x {
+			break
+		}
+		x = q + (x-q)/2
+	}
+
+	xh, xl := bits.Mul64(x, x)
+	exact := xh == hi && xl == lo
+
+	// round once the 19 digits square root to the nearest mantissa with an exponent in range
+	e = (e - k) / 2
+	v = 0
+	if !exact {
+		v = loss
+	}
+	if n := 0; x > MaxInt || e < decimalMinE {
+		for x/tenPow[n] > MaxInt || e+int64(n) < decimalMinE {
+			n++
+		}
+
+		p := tenPow[n]
+		q, r := x/p, x%p
+		if r > p/2 || r == p/2 && (!exact || q&1 == 1) {
+			q++
+		}
+		if r != 0 {
+			v = loss
+		}
+
+		x, e = q, e+int64(n)
+	}
+
+	return vmeAsDecimal(v, x, e)
 }
 
 // Pow returns d1**d2, the base-d1 exponential of d2.
@@ -987,6 +1443,23 @@ func NewFromUint64(value uint64) Decimal {
 	}
 }
 
+// NewFromUint converts a uint to Decimal.
+//
+// On 64-bit platforms a uint may exceed MaxInt, it is then normalized and loss bit is set if some digits are dropped.
+func NewFromUint(value uint) Decimal {
+	return NewFromUint64(uint64(value))
+}
+
+// NewFromUint32 converts a uint32 to Decimal.
+func NewFromUint32(value uint32) Decimal {
+	if value == 0 {
+		return Zero
+	} else {
+		// uint32 value fit in mantissa directly without other conversion
+		return Decimal(value)
+	}
+}
+
 // NewFromInt32 converts a int32 to Decimal.
 func NewFromInt32(value int32) Decimal {
 	if value == 0 {
@@ -997,6 +1470,18 @@ func NewFromInt32(value int32) Decimal {
 	}
 }
 
+// NewFromFraction returns numerator / denominator as a Decimal, it is a shortcut for NewFromInt(numerator).Div(NewFromInt(denominator)).
+// If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
+// Like Div, a zero denominator returns NaN.
+//
+// Example:
+//
+//	NewFromFraction(1, 4) // 0.25
+//	NewFromFraction(1, 3) // ~0.3333333333333333
+func NewFromFraction(numerator, denominator int64) Decimal {
+	return NewFromInt(numerator).Div(NewFromInt(denominator))
+}
+
 // NewFromFloat converts a float64 to Decimal.
 func NewFromFloat(value float64) Decimal {
 	return NewFromFloat64Exact(value, true)
@@ -1112,41 +1597,512 @@ func Max(first Decimal, rest ...Decimal) Decimal {
 	return max
 }
 
-// NewFromBytes returns a new Decimal from a slice of bytes representation.
-func NewFromBytes(value []byte) (Decimal, error) {
-	if v, m, e, err := vmeFromBytes(value, nil); err == nil {
-		return vmeAsDecimal(v, m, e), nil
-	} else {
-		return 0, err
+// Closest returns the candidate nearest to target, by absolute difference, and its index in candidates,
+// a tie being broken toward the smaller candidate (the first one when they are equal).
+// NaN candidates are skipped: if candidates is empty or has only NaN, or if target is NaN, it returns NaN and -1.
+//
+// Example:
+//
+//	sizes := []Decimal{1, 5, 10, 25}
+//	v, i := Closest(New(74, -1), sizes) // v = 5, i = 1
+//	v, i = Closest(New(75, -1), sizes)  // v = 5, i = 1 as 7.5 is as close to 5 as to 10
+func Closest(target Decimal, candidates []Decimal) (value Decimal, index int) {
+	value, index = NaN, -1
+	if target.IsNaN() {
+		return
+	}
+
+	var min Decimal
+	for i, c := range candidates {
+		if c.IsNaN() {
+			continue
+		}
+
+		dist := c.Sub(target).Abs()
+		if c == target {
+			dist = Zero
+		}
+
+		if index < 0 || dist.LessThan(min) || dist.Equal(min) && c.LessThan(value) {
+			value, index, min = c, i, dist
+		}
 	}
+
+	return
 }
 
-// NewFromString returns a new Decimal from a string representation.
+// Dot returns the dot product of a and b, the sum of the element-wise products a[i] * b[i], accumulated
+// with the compensated algorithm of Sum so that large terms cancelling each other do not swallow the small ones.
+// It returns ErrLengthMismatch when a and b have different lengths, the dot product of empty slices is Zero.
 //
 // Example:
 //
-//	d, err := NewFromString("-123.45")
-//	d2, err := NewFromString(".0001")
-//	d3, err := NewFromString("1.47000")
-//	d4, err := NewFromString("3.14e15")
-func NewFromString(value string) (Decimal, error) {
-	return NewFromBytes([]byte(value))
+//	prices := []Decimal{New(1999, -2), New(5, -1)}
+//	quantities := []Decimal{3, 4}
+//	total, _ := Dot(prices, quantities) // 61.97
+func Dot(a, b []Decimal) (Decimal, error) {
+	if len(a) != len(b) {
+		return NaN, ErrLengthMismatch
+	}
+	if len(a) == 0 {
+		return Zero, nil
+	}
+
+	products := make([]Decimal, len(a))
+	for i := range a {
+		products[i] = a[i].Mul(b[i])
+	}
+
+	return Sum(products[0], products[1:]...), nil
 }
 
-// NewFromFormattedString returns a new Decimal from a formatted string representation.
-// Characters matching replRegexp are stripped from value before parsing.
+// RoundPreservingSum rounds every value to places decimal places so that the rounded values sum exactly
+// to the rounded total Sum(values...).Round(places), as needed by a breakdown displayed along with its total.
 //
-// Useful to consume locale-formatted numbers, e.g. "$1,234.56" or "  9 876.54 €":
+// It uses the largest remainder method: every value is first rounded toward -Inf, then the values with the
+// largest remainders (the first ones on ties) are rounded up by one unit of the last place until the total is reached.
+// If a value is not finite, every value is simply rounded with Round.
 //
-//	r := regexp.MustCompile(`[$,€\s]`)
-//	d, err := NewFromFormattedString("$1,234.56", r) // d = 1234.56
+// Example:
+//
+//	third := NewFromInt(1).Div(3)
+//	r := RoundPreservingSum([]Decimal{third, third, third}, 2) // [0.34 0.33 0.33] while Round gives [0.33 0.33 0.33]
+func RoundPreservingSum(values []Decimal, places int32) []Decimal {
+	rounded := make([]Decimal, len(values))
+	if len(values) == 0 {
+		return rounded
+	}
+
+	for i, d := range values {
+		if d.IsNaN() || d.IsInfinite() {
+			for i, d := range values {
+				rounded[i] = d.Round(places)
+			}
+
+			return rounded
+		}
+
+		rounded[i] = d.RoundFloor(places)
+	}
+
+	// number of values to round up, in [0, len(values)] as the floors are at most one unit below each value
+	total := Sum(values[0], values[1:]...).Round(places)
+	k := total.Sub(Sum(rounded[0], rounded[1:]...)).Shift(places).IntPart()
+	if k <= 0 {
+		return rounded
+	} else if k > int64(len(values)) {
+		k = int64(len(values))
+	}
+
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]].Sub(rounded[order[i]]).GreaterThan(values[order[j]].Sub(rounded[order[j]]))
+	})
+
+	ulp := New(1, -places)
+	for _, i := range order[:k] {
+		rounded[i] = rounded[i].Add(ulp)
+	}
+
+	return rounded
+}
+
+// SumJSON returns the Sum of the numbers of a JSON array such as `[1.1, "2.2", 3e-2]`.
+//
+// The array is decoded element by element without going through float64, so every element keeps its
+// decimal digits. Elements may be JSON numbers or strings holding a number, any other element (null,
+// boolean, object or array) returns ErrSyntax. An empty array sums to Zero.
+func SumJSON(data []byte) (Decimal, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil {
+		return Null, err
+	} else if tok != json.Delim('[') {
+		return Null, ErrSyntax
+	}
+
+	var values []Decimal
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Null, err
+		}
+
+		var d Decimal
+		switch x := tok.(type) {
+		case json.Number:
+			d, err = NewFromString(string(x))
+		case string:
+			d, err = NewFromString(x)
+		default:
+			err = ErrSyntax
+		}
+		if err != nil {
+			return Null, err
+		}
+
+		values = append(values, d)
+	}
+
+	// closing bracket, then nothing else than white spaces
+	if _, err := dec.Token(); err != nil {
+		return Null, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return Null, ErrSyntax
+	}
+
+	if len(values) == 0 {
+		return Zero, nil
+	}
+
+	return Sum(values[0], values[1:]...), nil
+}
+
+// Outliers returns the indices of values whose absolute z-score |x - mean| / stddev is strictly greater than zThreshold,
+// where mean is Avg and stddev the population standard deviation of values.
+//
+// NaN and infinite values are skipped: they neither take part in the mean and standard deviation nor are reported.
+// When the standard deviation is zero (constant values, or less than two numbers), there is no outlier and nil is returned.
+//
+// Example:
+//
+//	Outliers([]Decimal{10, 11, 9, 10, 50}, 1) // [4]
+func Outliers(values []Decimal, zThreshold Decimal) []int {
+	finite := make([]Decimal, 0, len(values))
+	for _, x := range values {
+		if !x.IsNaN() && !x.IsInfinite() {
+			finite = append(finite, x)
+		}
+	}
+	if len(finite) < 2 {
+		return nil
+	}
+
+	mean := Avg(finite[0], finite[1:]...)
+
+	deviations := make([]Decimal, len(finite))
+	for i, x := range finite {
+		dx := x.Sub(mean)
+		deviations[i] = dx.Mul(dx)
+	}
+	stddev := Avg(deviations[0], deviations[1:]...).Sqrt()
+	if stddev.IsZero() {
+		return nil
+	}
+
+	// |x - mean| > zThreshold * stddev avoids a division for each value
+	limit := zThreshold.Mul(stddev)
+
+	var indices []int
+	for i, x := range values {
+		if !x.IsNaN() && !x.IsInfinite() && x.Sub(mean).Abs().GreaterThan(limit) {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// NewFromBytes returns a new Decimal from a slice of bytes representation.
+func NewFromBytes(value []byte) (Decimal, error) {
+	if v, m, e, err := vmeFromBytes(value, nil); err == nil {
+		return vmeAsDecimal(v, m, e), nil
+	} else {
+		return 0, err
+	}
+}
+
+// NewFromString returns a new Decimal from a string representation.
+//
+// The fractional or the integer part may be omitted, so "5.", ".5" and "5.5" are all valid,
+// but a dot needs at least one digit next to it: "." alone (or "-.") is a syntax error.
+// Digits may be grouped with an underscore or a single space between two digits, "1_000_000" or "1 000 000".
+// A trailing percent sign divides the value by 100, "50%" is 0.5, a per mille sign by 1000, "5‰" is 0.005,
+// and a basis point suffix ("bp" or the per ten thousand sign "‱") by 10000, "25bp" is 0.0025.
+// A number fully wrapped in parentheses is negative (accounting style), "(123.45)" is -123.45,
+// a sign inside the parentheses or an unbalanced parenthesis is a syntax error.
+// A string longer than MaxParseLength bytes is rejected with ErrTooLong.
+// Leading zeros never select another base, "010" is 10 and not 8 as in C: base prefixes are only
+// understood by NewFromIntLiteral, "0x1F" being a syntax error for NewFromString.
+//
+// Example:
+//
+//	d, err := NewFromString("-123.45")
+//	d2, err := NewFromString(".0001")
+//	d3, err := NewFromString("1.47000")
+//	d4, err := NewFromString("3.14e15")
+func NewFromString(value string) (Decimal, error) {
+	return NewFromBytes([]byte(value))
+}
+
+// NewFromStringNumeric returns a new Decimal from a string representation like NewFromString, but accepting only genuine numeric forms:
+// digits with an optional sign or ~ prefix, a decimal point (or DecimalSeparator) and an exponent, or the "Inf", "Infinity" and "NaN" literals.
+// The words NewFromString understands ("yes", "no", "on", "off", "nil", "null", "zero", "dozen"...), the percent and basis point suffixes,
+// the parentheses of negative amounts, digit grouping and an empty string are rejected with ErrSyntax, so that a product code such as "no" is never read as 0.
+//
+// Example:
+//
+//	d, err := NewFromStringNumeric("-1.5e3") // d = -1500
+//	_, err = NewFromStringNumeric("off")     // err = ErrSyntax
+func NewFromStringNumeric(value string) (Decimal, error) {
+	if MaxParseLength > 0 && len(value) > MaxParseLength {
+		return 0, ErrTooLong
+	}
+
+	s := strings.TrimSpace(value)
+
+	switch strings.ToLower(strings.TrimLeft(s, "+-~")) {
+	case "":
+		// NewFromString gives Null for an empty string
+		return 0, ErrSyntax
+	case "inf", "infinity", "nan":
+		return NewFromString(s)
+	}
+
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '+' && r != '-' && r != '~' && r != '.' && r != 'e' && r != 'E' && r != DecimalSeparator {
+			return 0, ErrSyntax
+		}
+	}
+
+	return NewFromString(s)
+}
+
+// NewFromStringFraction returns a new Decimal from a string representation that may be a fraction of two integers,
+// like "22/7" or "-3/4", computed with Div (DivisionPrecision digits, loss bit set when inexact).
+// A string without slash is parsed like NewFromString.
+//
+// ErrSyntax is returned for more than one slash, an empty side or a side that is not an integer (no dot nor exponent),
+// and ErrDivisionByZero for a zero denominator.
+//
+// Example:
+//
+//	d, err := NewFromStringFraction("22/7") // d = ~3.1428571428571429
+//	d2, err := NewFromStringFraction("3/4") // d2 = 0.75
+func NewFromStringFraction(value string) (Decimal, error) {
+	i := strings.IndexByte(value, '/')
+	if i < 0 {
+		return NewFromString(value)
+	}
+
+	num, err := newIntegerFromString(value[:i])
+	if err != nil {
+		return 0, err
+	}
+	den, err := newIntegerFromString(value[i+1:])
+	if err != nil {
+		return 0, err
+	}
+	if den.IsZero() {
+		return NaN, ErrDivisionByZero
+	}
+
+	return num.Div(den), nil
+}
+
+// NewFromIntLiteral returns a new Decimal from a Go style integer literal with a base prefix,
+// "0x1F" (hexadecimal), "0b1010" (binary) or "0o17" (octal), with an optional sign and underscores between digits.
+// A string without base prefix is parsed like NewFromString, so "017" is 17 and "1.5" is 1.5.
+//
+// ErrSyntax is returned for an invalid prefixed literal and ErrOutOfRange when it does not fit in an int64.
+//
+// Example:
+//
+//	d, err := NewFromIntLiteral("0x1F")    // d = 31
+//	d2, err := NewFromIntLiteral("-0b101") // d2 = -5
+func NewFromIntLiteral(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+
+	p := s
+	if p != "" && (p[0] == '-' || p[0] == '+') {
+		p = p[1:]
+	}
+	if len(p) < 2 || p[0] != '0' || (p[1]|0x20) != 'x' && (p[1]|0x20) != 'b' && (p[1]|0x20) != 'o' {
+		return NewFromString(value)
+	}
+
+	if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return NewFromInt(i), nil
+	} else if err.(*strconv.NumError).Err == strconv.ErrRange {
+		return 0, ErrOutOfRange
+	} else {
+		return 0, ErrSyntax
+	}
+}
+
+// newIntegerFromString parses an integer literal, an optional sign followed by digits possibly grouped like NewFromString.
+func newIntegerFromString(value string) (Decimal, error) {
+	s := strings.TrimSpace(value)
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, ErrSyntax
+	}
+	for k := 0; k < len(s); k++ {
+		if (s[k] < '0' || s[k] > '9') && s[k] != '_' && s[k] != ' ' {
+			return 0, ErrSyntax
+		}
+	}
+
+	return NewFromString(value)
+}
+
+// NewFromFormattedString returns a new Decimal from a formatted string representation.
+// Characters matching replRegexp are stripped from value before parsing.
+//
+// Useful to consume locale-formatted numbers, e.g. "$1,234.56" or "  9 876.54 €":
+//
+//	r := regexp.MustCompile(`[$,€\s]`)
+//	d, err := NewFromFormattedString("$1,234.56", r) // d = 1234.56
 //
 // Note: the function only removes characters; if you need to swap a comma decimal separator for a dot,
-// preprocess value yourself before calling.
+// use NewFromStringLocale.
 func NewFromFormattedString(value string, replRegexp *regexp.Regexp) (Decimal, error) {
+	if MaxParseLength > 0 && len(value) > MaxParseLength {
+		return 0, ErrTooLong
+	}
+
 	return NewFromString(replRegexp.ReplaceAllString(value, ""))
 }
 
+// NewFromStringLocale returns a new Decimal from a localized string representation using decimalSep as the decimal point
+// and groupSep as the digit group separator of the integer part, e.g. "1.234,56" with ',' and '.' or "1 234,56" with ',' and ' '.
+//
+// Group separators are only allowed between groups of 3 digits (the first group having 1 to 3 digits) and before the decimal point.
+// When groupSep is a space, any of the space (U+0020), no-break space (U+00A0), thin space (U+2009) or narrow no-break space (U+202F)
+// is a group separator, as browsers and locale formatters use them interchangeably.
+// ErrSyntax is returned when separators are ambiguous: the same rune used for both, misplaced group separators
+// or a '.', a space or a '_' that is not groupSep.
+//
+// Example:
+//
+//	d, err := NewFromStringLocale("1.234,56", ',', '.') // d = 1234.56
+//	d2, err := NewFromStringLocale("-1 234 567", ',', ' ') // d2 = -1234567
+func NewFromStringLocale(value string, decimalSep, groupSep rune) (Decimal, error) {
+	if decimalSep == groupSep {
+		return 0, ErrSyntax
+	}
+	if MaxParseLength > 0 && len(value) > MaxParseLength {
+		return 0, ErrTooLong
+	}
+
+	var buf [64]byte
+
+	b := buf[:0]
+	digits := 0      // number of digits of the current group of the integer part
+	grouped := false // a group separator has been found
+	integer := true  // still in the integer part
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			if integer {
+				digits++
+			}
+			b = append(b, byte(r))
+
+		case r == groupSep || isSpaceGroupSep(groupSep) && isSpaceGroupSep(r):
+			if !integer || digits == 0 || digits > 3 || grouped && digits != 3 {
+				return 0, ErrSyntax
+			}
+			grouped, digits = true, 0
+
+		case r == '.' && decimalSep != '.', r == '_' || isSpaceGroupSep(r):
+			// a space or '_' which is not groupSep would otherwise be skipped as a digit separator by the parser
+			return 0, ErrSyntax
+
+		default:
+			// after its first digit, the integer part ends here (decimal point, exponent or anything else) and its last group must be complete
+			if integer && (digits > 0 || grouped || r == decimalSep) {
+				if grouped && digits != 3 {
+					return 0, ErrSyntax
+				}
+				integer = false
+			}
+
+			if r == decimalSep {
+				r = '.'
+			}
+			if r < utf8.RuneSelf {
+				b = append(b, byte(r))
+			} else {
+				var rb [utf8.UTFMax]byte
+
+				b = append(b, rb[:utf8.EncodeRune(rb[:], r)]...)
+			}
+		}
+	}
+	if integer && grouped && digits != 3 {
+		return 0, ErrSyntax
+	}
+
+	return NewFromBytes(b)
+}
+
+// isSpaceGroupSep returns true if r is one of the spaces used as digit group separator.
+func isSpaceGroupSep(r rune) bool {
+	return r == ' ' || r == '\u00a0' || r == '\u2009' || r == '\u202f'
+}
+
+// NewFromScientificUnicode returns a new Decimal from a scientific notation as typeset in documents, a multiplication sign
+// followed by 10 and a superscript exponent with an optional superscript sign, e.g. "1.23×10³" or "4.5 × 10⁻²".
+// It is converted to the e notation before being parsed like NewFromString, a string without "×" being parsed as is.
+// ErrSyntax is returned when the exponent is missing or is not only made of superscript digits.
+//
+// Example:
+//
+//	d, err := NewFromScientificUnicode("1.23×10³")  // d = 1230
+//	d2, err := NewFromScientificUnicode("4.5×10⁻²") // d2 = 0.045
+func NewFromScientificUnicode(value string) (Decimal, error) {
+	if MaxParseLength > 0 && len(value) > MaxParseLength {
+		return 0, ErrTooLong
+	}
+
+	i := strings.IndexRune(value, '×')
+	if i < 0 {
+		return NewFromString(value)
+	}
+
+	exp := strings.TrimLeft(value[i+len("×"):], " ")
+	if !strings.HasPrefix(exp, "10") {
+		return 0, ErrSyntax
+	}
+
+	var buf [64]byte
+
+	b := append(append(buf[:0], strings.TrimRight(value[:i], " ")...), 'e')
+	digits := 0
+	for k, r := range exp[len("10"):] {
+		switch {
+		case r >= '⁴' && r <= '⁹' || r == '⁰':
+			b = append(b, byte(r-'⁰')+'0')
+		case r == '¹':
+			b = append(b, '1')
+		case r == '²' || r == '³':
+			b = append(b, byte(r-'²')+'2')
+		case r == '⁻' && k == 0:
+			b = append(b, '-')
+
+			continue
+		case r == '⁺' && k == 0:
+			continue
+		default:
+			return 0, ErrSyntax
+		}
+		digits++
+	}
+	if digits == 0 {
+		return 0, ErrSyntax
+	}
+
+	return NewFromBytes(b)
+}
+
 // RequireFromString returns a new Decimal from a string representation
 // or panics if NewFromString would have returned an error.
 //
@@ -1162,6 +2118,46 @@ func RequireFromString(value string) Decimal {
 	}
 }
 
+// NewFromStrings returns the Decimals of a batch of string representations parsed like NewFromString,
+// it stops at the first invalid string and returns its error, see NewFromStringsCollect to get every error.
+func NewFromStrings(values []string) ([]Decimal, error) {
+	decimals := make([]Decimal, len(values))
+
+	for i, value := range values {
+		d, err := NewFromString(value)
+		if err != nil {
+			return nil, err
+		}
+		decimals[i] = d
+	}
+
+	return decimals, nil
+}
+
+// NewFromStringsCollect parses a batch of string representations like NewFromStrings but does not stop at the first
+// invalid string, so that every bad field of a form can be reported at once. errs is nil when every string is valid,
+// otherwise errs[i] is the error of values[i] (nil for a valid string) and decimals[i] is 0 for an invalid string.
+//
+// Example:
+//
+//	decimals, errs := NewFromStringsCollect([]string{"1.5", "x", "2", "1..2"}) // errs[1] is ErrUnitSyntax, errs[3] is ErrSyntax
+func NewFromStringsCollect(values []string) (decimals []Decimal, errs []error) {
+	decimals = make([]Decimal, len(values))
+
+	for i, value := range values {
+		d, err := NewFromString(value)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(values))
+			}
+			errs[i] = err
+		}
+		decimals[i] = d
+	}
+
+	return
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (d *Decimal) UnmarshalJSON(b []byte) error {
 	if v, m, e, err := vmeFromBytes(b, nil); err == nil {
@@ -1173,6 +2169,28 @@ func (d *Decimal) UnmarshalJSON(b []byte) error {
 	}
 }
 
+// UnmarshalJSONLenient decodes a JSON value like UnmarshalJSON but also accepts the bare NaN, Infinity, +Infinity
+// and -Infinity tokens of JSON5, that are not valid JSON so that encoding/json never hands them to UnmarshalJSON.
+//
+// Example:
+//
+//	var d Decimal
+//	err := UnmarshalJSONLenient([]byte("-Infinity"), &d) // d = NegativeInfinity
+func UnmarshalJSONLenient(data []byte, d *Decimal) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "NaN":
+		*d = NaN
+	case "Infinity", "+Infinity":
+		*d = PositiveInfinity
+	case "-Infinity":
+		*d = NegativeInfinity
+	default:
+		return d.UnmarshalJSON(data)
+	}
+
+	return nil
+}
+
 // String returns the string representation of the decimal with the fixed point.
 //
 // Example:
@@ -1184,11 +2202,60 @@ func (d *Decimal) UnmarshalJSON(b []byte) error {
 //
 //	-12.345
 func (d Decimal) String() string {
-	if d == Null {
-		return "0"
-	} else {
-		return string(d.BytesTo(nil))
+	// the maximal length is 20 bytes plus the extra bytes of a multi-byte DecimalSeparator
+	var buf [24]byte
+
+	return string(d.AppendString(buf[:0]))
+}
+
+// AppendString appends the String output of the decimal to b and returns the extended buffer, like strconv.AppendInt does.
+// It does not allocate when b has enough capacity, reusing a buffer such as:
+//
+//	buf = d.AppendString(buf[:0])
+func (d Decimal) AppendString(b []byte) []byte {
+	n := len(b)
+
+	return withDecimalSeparator(d.BytesTo(b), n)
+}
+
+// withDecimalSeparator replaces the decimal point of b[start:] by DecimalSeparator
+func withDecimalSeparator(b []byte, start int) []byte {
+	if DecimalSeparator != '.' {
+		if n := bytes.IndexByte(b[start:], '.'); n >= 0 {
+			n += start
+
+			if DecimalSeparator < utf8.RuneSelf {
+				b[n] = byte(DecimalSeparator)
+			} else {
+				b = append(b[:n], append([]byte(string(DecimalSeparator)), b[n+1:]...)...)
+			}
+		}
+	}
+
+	return b
+}
+
+// GoString implements the fmt.GoStringer interface (%#v), it shows both the value and its internal layout:
+// the raw bits, the signed mantissa, the exponent bits and the loss bit.
+//
+// Example:
+//
+//	fmt.Sprintf("%#v", New(-101, -2)) // output: "decimal.Decimal(0xc3ffffffffffff9b=-1.01, m=-101, e=-2, loss=false)"
+func (d Decimal) GoString() string {
+	u := uint64(d)
+	if d < 0 {
+		u = uint64(-d)
+	}
+
+	m := int64(u & MaxInt)
+	if d < 0 {
+		m = -m
 	}
+
+	// raw exponent bits, not the magic exponents returned by Exponent
+	e := int64((u&decimalEBitmask)<<2) >> (2 + decimalBitE)
+
+	return fmt.Sprintf("decimal.Decimal(%#x=%s, m=%d, e=%d, loss=%t)", uint64(d), d.String(), m, e, u&loss != 0)
 }
 
 // BytesTo appends the string representation of the decimal to a slice of byte, if the decimal is Null it appends 0.
@@ -1219,6 +2286,8 @@ func (d Decimal) StringFixed(places int32) string {
 	return string(d.IfNull(Zero).BytesToFixed(nil, places))
 }
 
+// BytesToFixed appends the rounded fixed-point string representation of the decimal with places digits after the decimal point to a slice of byte,
+// it is the alloc-free counterpart of StringFixed.
 func (d Decimal) BytesToFixed(b []byte, places int32) []byte {
 	v, m, e := d.vme()
 
@@ -1234,6 +2303,8 @@ func (d Decimal) BytesToFixed(b []byte, places int32) []byte {
 	return vmetBytesTo(b, v, m, e, places, nil, true, false)
 }
 
+// BytesToFixedBank appends the banker rounded fixed-point string representation of the decimal with places digits after the decimal point to a slice of byte,
+// it is the alloc-free counterpart of StringFixedBank.
 func (d Decimal) BytesToFixedBank(b []byte, places int32) []byte {
 	v, m, e := d.vme()
 
@@ -1243,10 +2314,32 @@ func (d Decimal) BytesToFixedBank(b []byte, places int32) []byte {
 	if b == nil {
 		b = make([]byte, 0, 20)
 	}
+	if places < 0 {
+		places = 0
+	}
 
 	return vmetBytesTo(b, v, m, e, places, nil, true, false)
 }
 
+// RoundString returns both d.Round(places) and its StringFixed(places) representation, formatting the rounded value only once.
+//
+// Example:
+//
+//	r, s := New(12345, -3).RoundString(2) // r = 12.35, s = "12.35"
+func (d Decimal) RoundString(places int32) (Decimal, string) {
+	r := d.Round(places)
+
+	// like StringFixed, Null is output as zero
+	v, m, e := r.IfNull(Zero).vme()
+
+	var buf [24]byte
+	if places < 0 {
+		return r, string(vmetBytesTo(buf[:0], v, m, e, 0, nil, true, false))
+	}
+
+	return r, string(vmetBytesTo(buf[:0], v, m, e, places, nil, true, false))
+}
+
 // StringFixedCash returns a Cash-rounded fixed-point string with 2 digits after the decimal point. See RoundCash for the interval semantics.
 //
 // Examples:
@@ -1269,23 +2362,290 @@ func (d Decimal) StringFixedCash(interval uint8) string {
 //	NewFromFloat(5.45).StringFixedBank(2) // output: "5.45"
 //	NewFromFloat(5.45).StringFixedBank(3) // output: "5.450"
 //	NewFromFloat(545).StringFixedBank(-1) // output: "540"
+//	New(5455, -3).StringFixedBank(2) // output: "5.46"
+//	New(5445, -3).StringFixedBank(2) // output: "5.44"
 func (d Decimal) StringFixedBank(places int32) string {
+	return string(d.BytesToFixedBank(nil, places))
+}
+
+// StringExp returns the scientific notation of the decimal with a single digit before the decimal point,
+// the mantissa being rounded to places digits after the decimal point (as many digits as needed if places is negative).
+// An inexact decimal keeps its ~ prefix and magic values are output like String does.
+//
+// Example:
+//
+//	New(123456, 0).StringExp(4) // output: "1.2346e+05"
+//	New(-15, -10).StringExp(-1) // output: "-1.5e-09"
+//	Decimal(2).Div(3).StringExp(2) // output: "~6.67e-01"
+func (d Decimal) StringExp(places int32) string {
+	return string(d.BytesToExp(nil, places))
+}
+
+// BytesToExp appends the scientific notation of the decimal to a slice of byte, it is the alloc-free counterpart of StringExp.
+func (d Decimal) BytesToExp(b []byte, places int32) []byte {
 	v, m, e := d.vme()
 
-	v, m, e = vmeRoundBank(v, m, e, places)
+	// the maximal length of scientific representation in bytes is 24 unless a large places is requested
+	if b == nil {
+		b = make([]byte, 0, 24)
+	}
 
-	if places < 0 {
-		return string(vmetBytesTo(make([]byte, 0, 20), v, m, e, 0, nil, true, false))
+	return vmeExpBytesTo(b, v, m, e, int(places), 'e', true)
+}
+
+// StringGrouped returns a rounded fixed-point string with places digits after the decimal point (see StringFixed),
+// with the digits of the integer part grouped by thousands with sep and decimalPoint as the decimal point.
+// A zero sep disables grouping. The sign stays before the first group, NaN and infinities are output without grouping.
+//
+// Example:
+//
+//	New(123456789, -2).StringGrouped(',', '.', 2) // output: "1,234,567.89"
+//	New(-123456789, -2).StringGrouped('.', ',', 1) // output: "-1.234.567,9"
+//	New(123456789, -2).StringGrouped(' ', ',', 0) // output: "1 234 568"
+func (d Decimal) StringGrouped(sep rune, decimalPoint rune, places int32) string {
+	var buf [48]byte
+
+	b := d.IfNull(Zero).BytesToFixed(buf[:0], places)
+
+	// skip the sign, NaN and infinities have no digit to group
+	i := 0
+	for i < len(b) && (b[i] < '0' || b[i] > '9') {
+		i++
+	}
+	if i == len(b) {
+		return string(b)
+	}
+
+	n := bytes.IndexByte(b, '.')
+	if n < 0 {
+		n = len(b)
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(b) + (n-i)/3*utf8.UTFMax + utf8.UTFMax)
+	sb.Write(b[:i])
+	for k := i; k < n; k++ {
+		if sep != 0 && k > i && (n-k)%3 == 0 {
+			sb.WriteRune(sep)
+		}
+		sb.WriteByte(b[k])
+	}
+	if n < len(b) {
+		sb.WriteRune(decimalPoint)
+		sb.Write(b[n+1:])
+	}
+
+	return sb.String()
+}
+
+// Format implements the fmt.Formatter interface so that a decimal can be printed with the fmt verbs like a float64:
+//
+//	%v %s  the same output as String, %q double-quoted and %#v the output of GoString
+//	%f %F  fixed point with precision digits after the decimal point, 6 by default
+//	%e %E  scientific notation with precision digits after the decimal point, 6 by default
+//	%g %G  %e for large exponents and %f otherwise, precision is the number of significant digits, as many as needed by default
+//	%d     integer part, truncated towards zero
+//
+// Width and the '+', '-', ' ' and '0' flags are honored by the numeric verbs, %v, %s and %q only honor width and '-'.
+// Numeric verbs round like Round and never output the ~ prefix of an inexact decimal, NaN and infinities are output as NaN, +Inf and -Inf.
+//
+// Example:
+//
+//	fmt.Sprintf("%8.2f", New(12345, -3)) // output: "   12.35"
+//	fmt.Sprintf("%+.3e", New(12345, 0)) // output: "+1.235e+04"
+func (d Decimal) Format(s fmt.State, verb rune) {
+	// the maximal length of a formatted decimal is about 20 bytes unless a large precision is requested
+	var buf [48]byte
+
+	switch verb {
+	case 'v', 's':
+		if verb == 'v' && s.Flag('#') {
+			formatPadTo(s, []byte(d.GoString()), false)
+		} else {
+			formatPadTo(s, withDecimalSeparator(d.BytesTo(buf[:0]), 0), false)
+		}
+		return
+	case 'q':
+		formatPadTo(s, append(withDecimalSeparator(d.BytesTo(append(buf[:0], '"')), 0), '"'), false)
+		return
+	case 'd', 'f', 'F', 'e', 'E', 'g', 'G':
+	default:
+		fmt.Fprintf(s, "%%!%c(decimal.Decimal=%s)", verb, d.String())
+		return
+	}
+
+	v, m, e := d.vme()
+
+	// b[0] is a placeholder for the sign, it is kept, replaced or dropped once the number is output
+	b := append(buf[:0], '+')
+
+	if m == 0 && v&loss != 0 && e != 0 && e != math.MinInt64 {
+		if e == math.MaxInt64 {
+			if v&sign != 0 {
+				b[0] = '-'
+			} else if s.Flag(' ') && !s.Flag('+') {
+				b[0] = ' '
+			}
+			b = append(b, 'I', 'n', 'f')
+		} else {
+			b = append(b, 'N', 'a', 'N')
+			if !s.Flag('+') {
+				if s.Flag(' ') {
+					b[0] = ' '
+				} else {
+					b = b[1:]
+				}
+			}
+		}
+		formatPadTo(s, b, false)
+		return
+	}
+
+	prec, hasPrec := s.Precision()
+
+	switch verb {
+	case 'd':
+		v, m, e = d.Truncate(0).vme()
+		b = vmetBytesTo(b, v, m, e, 0, nil, false, false)
+	case 'f', 'F':
+		if !hasPrec {
+			prec = 6
+		}
+		v, m, e = vmeRound(v, m, e, int32(prec))
+		b = vmetBytesTo(b, v, m, e, int32(prec), nil, false, false)
+	case 'e', 'E':
+		if !hasPrec {
+			prec = 6
+		}
+		b = vmeExpBytesTo(b, v, m, e, prec, byte(verb), false)
+	case 'g', 'G':
+		if !hasPrec {
+			prec = -1
+		} else if prec == 0 {
+			prec = 1
+		}
+		if m != 0 {
+			if n := mDigits(m); prec > 0 && n > prec {
+				v, m, e = vmeRound(v, m, e, int32(int64(prec-n)-e))
+			}
+			for m%10 == 0 {
+				m /= 10
+				e++
+			}
+		}
+
+		// like strconv, %e is used when the exponent is less than -4 or greater than or equal to the precision (6 if shortest)
+		eprec := prec
+		if eprec < 0 {
+			eprec = 6
+		}
+		if x := e + int64(mDigits(m)) - 1; m != 0 && (x < -4 || x >= int64(eprec)) {
+			b = vmeExpBytesTo(b, v, m, e, -1, byte(verb)-'g'+'e', false)
+		} else {
+			b = vmetBytesTo(b, v, m, e, 0, nil, false, false)
+		}
+	}
+
+	if b[1] == '-' {
+		b = b[1:]
+	} else if !s.Flag('+') {
+		if s.Flag(' ') {
+			b[0] = ' '
+		} else {
+			b = b[1:]
+		}
+	}
+
+	formatPadTo(s, b, true)
+}
+
+// formatPadTo writes b to s padded to the width of s, with spaces or with zeros after the sign if zero is allowed and the '0' flag is set.
+func formatPadTo(s fmt.State, b []byte, zero bool) {
+	width, ok := s.Width()
+	if !ok || width <= len(b) {
+		s.Write(b)
+		return
+	}
+
+	pad := [...]byte{' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '}
+	if s.Flag('-') {
+		s.Write(b)
+	} else if zero && s.Flag('0') {
+		pad = [...]byte{'0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '0'}
+		if b[0] == '+' || b[0] == '-' || b[0] == ' ' {
+			s.Write(b[:1])
+			b = b[1:]
+			width--
+		}
+	}
+
+	for n := width - len(b); n > 0; n -= len(pad) {
+		if n < len(pad) {
+			s.Write(pad[:n])
+		} else {
+			s.Write(pad[:])
+		}
+	}
+
+	if !s.Flag('-') {
+		s.Write(b)
+	}
+}
+
+// FmtScanner returns a fmt.Scanner reading a decimal into d with fmt.Sscanf, fmt.Fscanf or fmt.Sscan.
+//
+// Decimal cannot implement fmt.Scanner itself as its Scan method is the one of the sql.Scanner interface,
+// a method name cannot be overloaded with another signature. The scanner skips spaces, reads a token up to
+// the next space and parses it like NewFromBytes for the %v, %s, %d, %f, %F, %e, %E, %g and %G verbs.
+//
+// Example:
+//
+//	var d Decimal
+//	_, err := fmt.Sscanf("price=12.34", "price=%v", FmtScanner(&d)) // d = 12.34
+func FmtScanner(d *Decimal) fmt.Scanner {
+	return fmtScanner{d}
+}
+
+type fmtScanner struct {
+	d *Decimal
+}
+
+// Scan implements the fmt.Scanner interface.
+func (s fmtScanner) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'v', 's', 'd', 'f', 'F', 'e', 'E', 'g', 'G':
+	default:
+		return ErrFormat
+	}
+
+	state.SkipSpace()
+
+	tok, err := state.Token(false, func(r rune) bool { return !unicode.IsSpace(r) })
+	if err != nil {
+		return err
+	}
+	if len(tok) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	if d, err := NewFromBytes(tok); err != nil {
+		return err
 	} else {
-		return string(vmetBytesTo(make([]byte, 0, 20), v, m, e, places, nil, true, false))
+		*s.d = d
+
+		return nil
 	}
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+// The output is a JSON number, or a JSON string if MarshalJSONAsString is set.
+// As JSON has no number for them, infinities are output as the string "Infinity" or "-Infinity"
+// and not-a-number as the string "NaN", which UnmarshalJSON reads back.
 func (d Decimal) MarshalJSON() ([]byte, error) {
 	v, m, e := d.vme()
 
-	return vmetBytesTo(nil, v, m, e, 0, nil, false, false), nil
+	return vmetBytesTo(nil, v, m, e, 0, nil, false, MarshalJSONAsString), nil
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
@@ -1373,6 +2733,11 @@ func (d *Decimal) UnmarshalBinary(data []byte) error {
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (d Decimal) MarshalBinary() (data []byte, err error) {
+	return d.AppendBinary(make([]byte, 0, 10))
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending the MarshalBinary output to b.
+func (d Decimal) AppendBinary(b []byte) ([]byte, error) {
 	var u uint64
 	var x byte
 
@@ -1387,16 +2752,15 @@ func (d Decimal) MarshalBinary() (data []byte, err error) {
 
 	if u == 0 {
 		// bit 0 is already unset as u is zero
-		data = []byte{x}
+		return append(b, x), nil
 	} else {
 		// bit 0 is on to indicate a non-zero mantissa
 		buff := [10]byte{x | 1}
 
 		n := binary.PutUvarint(buff[1:], u)
-		data = buff[0 : n+1]
-	}
 
-	return
+		return append(b, buff[0:n+1]...), nil
+	}
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface for XML deserialization.
@@ -1412,7 +2776,12 @@ func (d *Decimal) UnmarshalText(text []byte) error {
 
 // MarshalText implements the encoding.TextMarshaler interface for XML serialization.
 func (d Decimal) MarshalText() (text []byte, err error) {
-	return d.BytesTo(nil), nil
+	return d.AppendText(nil)
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the MarshalText output to b.
+func (d Decimal) AppendText(b []byte) ([]byte, error) {
+	return d.BytesTo(b), nil
 }
 
 // GobEncode implements the gob.GobEncoder interface for gob serialization.
@@ -1425,44 +2794,159 @@ func (d *Decimal) GobDecode(data []byte) error {
 	return d.UnmarshalBinary(data)
 }
 
-// Scan implements the sql.Scanner interface for database deserialization.
+// ValueMode selects the type of the value returned by Value for database serialization, see SQLValueMode.
+type ValueMode int
+
+const (
+	// ValueString makes Value return the string representation of the decimal, suitable for NUMERIC or DECIMAL columns.
+	ValueString ValueMode = iota
+
+	// ValueFloat64 makes Value return the nearest float64 of the decimal, for REAL or DOUBLE PRECISION columns.
+	ValueFloat64
+
+	// ValueInteger makes Value return an int64 count of 10^SQLValueExp, e.g. cents with SQLValueExp = -2 for a BIGINT column.
+	ValueInteger
+)
+
+var (
+	// SQLValueMode is the type of the value returned by Value, ValueString by default.
+	// In ValueInteger mode Scan reads back an integer value as a count of 10^SQLValueExp, so that both directions are symmetric.
+	SQLValueMode = ValueString
+
+	// SQLValueExp is the exponent of the unit counted by an integer value in ValueInteger mode, -2 (cents) by default.
+	SQLValueExp int32 = -2
+)
+
+// Scan implements the sql.Scanner interface for database deserialization, see FmtScanner to use fmt.Sscanf.
+// In ValueInteger mode (see SQLValueMode) an int64 or uint64 value read is a count of 10^SQLValueExp,
+// a string, bytes or float value being a plain decimal as in the other modes.
 func (d *Decimal) Scan(value interface{}) (err error) {
 	// first try to see if the data is stored in database as a Numeric datatype
 	switch v := value.(type) {
 	case float32:
 		*d = NewFromFloat(float64(v))
-		return nil
 
 	case float64:
 		// numeric in sqlite3 sends us float64
 		*d = NewFromFloat(v)
-		return nil
 
 	case int64:
 		// at least in sqlite3 when the value is 0 in db, the data is sent
 		// to us as an int64 instead of a float64 ...
 		*d = New(v, 0)
-		return nil
+		if SQLValueMode == ValueInteger {
+			*d = d.Shift(SQLValueExp)
+		}
 
 	case uint64:
 		// while clickhouse may send 0 in db as uint64
 		*d = NewFromUint64(v)
-		return nil
+		if SQLValueMode == ValueInteger {
+			*d = d.Shift(SQLValueExp)
+		}
 
 	case string:
-		*d, err = NewFromString(v)
-		return err
+		if *d, err = NewFromString(v); err != nil {
+			return err
+		}
 
 	case []byte:
-		*d, err = NewFromBytes(v)
-		return err
+		if *d, err = NewFromBytes(v); err != nil {
+			return err
+		}
 
 	default:
 		return ErrFormat
 	}
+
+	return nil
 }
 
 // Value implements the driver.Valuer interface for database serialization.
+// The type of the value depends on SQLValueMode, a string by default.
+// In ValueInteger mode the decimal is rounded to -SQLValueExp places
+// and ErrOutOfRange is returned for infinities, not-a-number or a count which does not fit in an int64.
 func (d Decimal) Value() (driver.Value, error) {
-	return d.String(), nil
+	switch SQLValueMode {
+	case ValueFloat64:
+		return d.InexactFloat64(), nil
+
+	case ValueInteger:
+		if d.IsNaN() || d.IsInfinite() {
+			return nil, ErrOutOfRange
+		}
+
+		if i, err := d.Round(-SQLValueExp).Shift(-SQLValueExp).IntPartErr(); err != nil {
+			return nil, err
+		} else {
+			return i, nil
+		}
+
+	default:
+		// the machine form with a '.' decimal point whatever DecimalSeparator is, a database must read it back in any locale
+		return string(d.BytesTo(nil)), nil
+	}
+}
+
+// NullDecimal represents a nullable decimal with compatibility for scanning null values from the database,
+// Valid is false when the column is NULL while a stored 0 gives a valid Zero.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+// NewNullDecimal returns a valid NullDecimal holding d.
+func NewNullDecimal(d Decimal) NullDecimal {
+	return NullDecimal{Decimal: d, Valid: true}
+}
+
+// Scan implements the sql.Scanner interface for database deserialization, a NULL value gives Valid false.
+// Valid is only set once the value has been read without error, an invalid value giving Valid false too.
+func (d *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		d.Decimal, d.Valid = Null, false
+
+		return nil
+	}
+
+	if err := d.Decimal.Scan(value); err != nil {
+		d.Decimal, d.Valid = Null, false
+
+		return err
+	}
+
+	d.Valid = true
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization, NULL if Valid is false.
+func (d NullDecimal) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+
+	return d.Decimal.Value()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, a JSON null gives Valid false.
+func (d *NullDecimal) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		d.Decimal, d.Valid = Null, false
+
+		return nil
+	}
+
+	d.Valid = true
+
+	return d.Decimal.UnmarshalJSON(b)
+}
+
+// MarshalJSON implements the json.Marshaler interface, null if Valid is false.
+func (d NullDecimal) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+
+	return d.Decimal.MarshalJSON()
 }
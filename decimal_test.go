@@ -3,10 +3,14 @@ package decimal
 import (
 	"testing"
 
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"log"
 	"math"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 func TestDoc(t *testing.T) {
@@ -258,6 +262,25 @@ func TestIsNearZero(t *testing.T) {
 	}
 }
 
+func TestIsNaNPayload(t *testing.T) {
+	// a zero mantissa with the loss bit is NaN for every exponent but 0 (near zero), 15 (infinity) and -16 (near positive zero),
+	// the exponent being the NaN payload
+	for e := int64(decimalMinE); e <= decimalMaxE; e++ {
+		want := e != 0 && e != decimalMaxE && e != decimalMinE
+
+		d := Decimal(loss | uint64(e<<decimalBitE)&decimalEBitmask)
+		if d.IsNaN() != want || d.Neg().IsNaN() != want {
+			t.Errorf(`Decimal of exponent %d and zero mantissa with loss bit (0x%016x) IsNaN() should be %t`, e, uint64(d), want)
+		}
+	}
+
+	// exponent 14 is the last payload before infinity (0x5c in the last byte)
+	d := Decimal(loss | uint64(14<<decimalBitE))
+	if uint64(d)>>56 != 0x5c || !d.IsNaN() || d.IsInfinite() || d.String() != "NaN" {
+		t.Errorf(`Decimal 0x%016x should be NaN and not %v`, uint64(d), d)
+	}
+}
+
 func TestIsExact(t *testing.T) {
 	d := Zero
 
@@ -467,6 +490,534 @@ func TestNewFromStringZeros(t *testing.T) {
 	}
 }
 
+func TestNewFromStringDots(t *testing.T) {
+	valid := []struct {
+		s string
+		d Decimal
+	}{
+		{"123.", 123},
+		{"5.", 5},
+		{"-5.", -5},
+		{".5", New(5, -1)},
+		{"-.5", New(-5, -1)},
+		{"0.", Zero},
+		{"1.e2", 100},
+	}
+	for _, c := range valid {
+		if d, err := NewFromString(c.s); err != nil || d != c.d || !d.IsExact() {
+			t.Errorf(`NewFromString(%q) should be exactly %v and not %v (err = %v)`, c.s, c.d, d, err)
+		}
+	}
+
+	for _, s := range []string{".", "-.", "+.", "~.", ".e5", ".kg", "1.2."} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+}
+
+func TestNewFromStringGroupSeparators(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"1_000_000", 1000000},
+		{"1 000 000", 1000000},
+		{"-1_000", -1000},
+		{"~1 000", 1000},
+		{"1_000.000_5", New(10000005, -4)},
+		{"1_0_0", 100},
+		{"1 000 000e-3", 1000},
+		{`"1 234.5"`, New(12345, -1)},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || !d.Equal(c.want) {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"_1000", "1000_", "1__000", "1_ 000", "1_.5", "1._5", "1_e5", "1e_5", "1e5_0", "-_1", "_"} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+
+	// a space still separates the number from its unit
+	for _, s := range []string{"1 000 kg", "1_000kg", "1 000kg"} {
+		if w, err := NewWeightFromString(s); err != nil || w != 1000 {
+			t.Errorf(`NewWeightFromString(%q) should be 1000kg and not %v (err = %v)`, s, w, err)
+		}
+	}
+	if w, err := NewWeightFromString("1 000 g"); err != nil || w.String() != "1000g" {
+		t.Errorf(`NewWeightFromString("1 000 g") should be 1000g and not %v (err = %v)`, w, err)
+	}
+}
+
+func TestNewFromStringAccounting(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"(123.45)", New(-12345, -2)},
+		{" (123.45) ", New(-12345, -2)},
+		{"(1_234.56)", New(-123456, -2)},
+		{"(1e3)", -1000},
+		{"(50%)", New(-5, -1)},
+		{`"(7)"`, -7},
+		{"(0)", Zero},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	if d, err := NewFromStringLocale("(1,234.56)", '.', ','); err != nil || d != New(-123456, -2) {
+		t.Errorf(`NewFromStringLocale("(1,234.56)", '.', ',') should be -1234.56 and not %v (err = %v)`, d, err)
+	}
+
+	if d, err := NewFromString("(~1.5)"); err != nil || d.String() != "~-1.5" {
+		t.Errorf(`NewFromString("(~1.5)") should be ~-1.5 and not %v (err = %v)`, d, err)
+	}
+
+	if w, err := NewWeightFromString("(550g)"); err != nil || w.String() != "-550g" {
+		t.Errorf(`NewWeightFromString("(550g)") should be -550g and not %v (err = %v)`, w, err)
+	}
+
+	for _, s := range []string{"(", ")", "()", "(123.45", "123.45)", "(-123.45)", "(+1)", "-(1)", "((1))", "(nan)", "( )"} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+}
+
+func TestNewFromStringPercent(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"50%", New(5, -1)},
+		{"50 %", New(5, -1)},
+		{"-12.5%", New(-125, -3)},
+		{"100%", 1},
+		{"1e2%", 1},
+		{"0.01%", New(1, -4)},
+		{"1_000%", 10},
+		{"0%", Zero},
+		{`"7%"`, New(7, -2)},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+
+		var d Decimal
+		if err := d.UnmarshalJSON([]byte(c.s)); err != nil || d != c.want {
+			t.Errorf(`UnmarshalJSON(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"%", "-%", ".%", "5%%", "5%x", "%5"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+
+	// percent is not a weight unit
+	if w, err := NewWeightFromString("50%"); err != ErrUnitSyntax {
+		t.Errorf(`NewWeightFromString("50%%") should return ErrUnitSyntax and not %v (err = %v)`, w, err)
+	}
+
+	if p := New(5, -1).Percent(); p != 50 {
+		t.Errorf(`0.5.Percent() should be 50 and not %v`, p)
+	}
+	if p := New(-125, -3).Percent(); p != New(-125, -1) {
+		t.Errorf(`-0.125.Percent() should be -12.5 and not %v`, p)
+	}
+	if d, _ := NewFromString("33.3%"); d.Percent() != New(333, -1) {
+		t.Errorf(`NewFromString("33.3%%").Percent() should be 33.3 and not %v`, d.Percent())
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	cases := []struct {
+		d, p               Decimal
+		of, increase, decr string
+	}{
+		{25, 20, "125", "30", "20"},
+		{15, 60, "25", "24", "6"},
+		{New(1999, -2), New(55, -1), "~363.45454545454545", "21.08945", "18.89055"},
+		{100, 100, "100", "200", "0"},
+		{-40, 25, "-160", "-50", "-30"},
+		{80, -25, "-320", "60", "100"},
+		{1, 3, "~33.333333333333333", "1.03", "0.97"},
+		{0, 7, "0", "0", "0"},
+		{New(1, -16), New(5, -1), "0.00000000000002", "~0.0000000000000001", "~0.0000000000000001"},
+	}
+
+	for _, c := range cases {
+		if r := c.d.PercentOf(c.p); r.String() != c.of {
+			t.Errorf(`%v.PercentOf(%v) should be %s and not %v`, c.d, c.p, c.of, r)
+		}
+		if r := c.d.IncreaseByPercent(c.p); r.String() != c.increase {
+			t.Errorf(`%v.IncreaseByPercent(%v) should be %s and not %v`, c.d, c.p, c.increase, r)
+		}
+		if r := c.d.DecreaseByPercent(c.p); r.String() != c.decr {
+			t.Errorf(`%v.DecreaseByPercent(%v) should be %s and not %v`, c.d, c.p, c.decr, r)
+		}
+	}
+
+	if r := Decimal(25).IncreaseByPercent(20); r != 30 {
+		t.Errorf(`25.IncreaseByPercent(20) should be exactly 30 and not %v`, r)
+	}
+	if r := Decimal(5).PercentOf(0); !r.IsNaN() {
+		t.Errorf(`5.PercentOf(0) should be NaN and not %v`, r)
+	}
+	if r := Decimal(5).PercentOf(Zero); !r.IsNaN() {
+		t.Errorf(`5.PercentOf(Zero) should be NaN and not %v`, r)
+	}
+}
+
+func TestNewFromStringPerMilleBasisPoint(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"5‰", New(5, -3)},
+		{"5 ‰", New(5, -3)},
+		{"-2.5‰", New(-25, -4)},
+		{"1000‰", 1},
+		{"25bp", New(25, -4)},
+		{"25 bp", New(25, -4)},
+		{"-50bp", New(-5, -3)},
+		{"100bp", New(1, -2)},
+		{"10000bp", 1},
+		{"0.5bp", New(5, -5)},
+		{"25‱", New(25, -4)},
+		{"0bp", Zero},
+		{`"75bp"`, New(75, -4)},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	// 25bp, 0.25% and 2.5‰ are the same rate
+	bp, _ := NewFromString("25bp")
+	pc, _ := NewFromString("0.25%")
+	pm, _ := NewFromString("2.5‰")
+	if !bp.Equal(pc) || !bp.Equal(pm) {
+		t.Errorf(`25bp, 0.25%% and 2.5‰ should be equal and not %v, %v and %v`, bp, pc, pm)
+	}
+
+	for _, s := range []string{"‰", "bp", "5bpbp", "5‰%", "5bps", "5BP"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+
+	// per mille and basis points are not weight nor length units
+	for _, s := range []string{"5‰", "25bp", "25‱"} {
+		if w, err := NewWeightFromString(s); err != ErrUnitSyntax {
+			t.Errorf(`NewWeightFromString(%q) should return ErrUnitSyntax and not %v (err = %v)`, s, w, err)
+		}
+		if l, err := NewLengthFromString(s); err != ErrUnitSyntax {
+			t.Errorf(`NewLengthFromString(%q) should return ErrUnitSyntax and not %v (err = %v)`, s, l, err)
+		}
+	}
+}
+
+func TestNewFromStringLeadingZeros(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"007", 7},
+		{"010", 10},
+		{"-010", -10},
+		{"+0010", 10},
+		{"0777", 777},
+		{"08", 8},
+		{"09.5", New(95, -1)},
+		{"0.5", New(5, -1)},
+		{"00.50", New(5, -1)},
+		{"000", Zero},
+		{"00.00", Zero},
+		{"0_010", 10},
+		{"010e1", 100},
+		{"1e010", New(1, 10)},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+
+		// without a base prefix NewFromIntLiteral is NewFromString
+		if d, err := NewFromIntLiteral(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromIntLiteral(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	// base prefixes are only understood by NewFromIntLiteral
+	for _, s := range []string{"0x10", "0o10", "0b10"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+	if d, err := NewFromIntLiteral("0o10"); err != nil || d != 8 {
+		t.Errorf(`NewFromIntLiteral("0o10") should be 8 and not %v (err = %v)`, d, err)
+	}
+}
+
+func TestNewFromStrings(t *testing.T) {
+	if decimals, err := NewFromStrings([]string{"1.5", "-2", "~0", "1e3"}); err != nil || len(decimals) != 4 || decimals[0] != New(15, -1) || decimals[1] != -2 || decimals[2] != NearZero || decimals[3] != 1000 {
+		t.Errorf(`NewFromStrings should be [1.5 -2 ~0 1000] and not %v (err = %v)`, decimals, err)
+	}
+	if decimals, err := NewFromStrings([]string{"1.5", "1..2", "x"}); err != ErrSyntax || decimals != nil {
+		t.Errorf(`NewFromStrings with an invalid string should return ErrSyntax and not %v (err = %v)`, decimals, err)
+	}
+	if decimals, err := NewFromStrings(nil); err != nil || len(decimals) != 0 {
+		t.Errorf(`NewFromStrings(nil) should be empty and not %v (err = %v)`, decimals, err)
+	}
+}
+
+func TestNewFromStringsCollect(t *testing.T) {
+	values := []string{"1.5", "x", "2", "1..2", "3kg", "(4)", ""}
+	want := []Decimal{New(15, -1), 0, 2, 0, 0, -4, 0}
+	wantErrs := []error{nil, ErrUnitSyntax, nil, ErrSyntax, ErrUnitSyntax, nil, nil}
+
+	decimals, errs := NewFromStringsCollect(values)
+	if len(decimals) != len(values) || len(errs) != len(values) {
+		t.Fatalf(`NewFromStringsCollect should return %d decimals and errors and not %d and %d`, len(values), len(decimals), len(errs))
+	}
+	for i := range values {
+		if decimals[i] != want[i] || errs[i] != wantErrs[i] {
+			t.Errorf(`NewFromStringsCollect element %d (%q) should be %v with error %v and not %v with error %v`, i, values[i], want[i], wantErrs[i], decimals[i], errs[i])
+		}
+	}
+
+	// no error at all gives a nil error slice
+	if decimals, errs := NewFromStringsCollect([]string{"1", "2.5"}); errs != nil || len(decimals) != 2 || decimals[1] != New(25, -1) {
+		t.Errorf(`NewFromStringsCollect of valid strings should be [1 2.5] without errors and not %v (errs = %v)`, decimals, errs)
+	}
+}
+
+func TestNewFromIntLiteral(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{"0x1F", "31"},
+		{"0X1f", "31"},
+		{"0b1010", "10"},
+		{"0B1010", "10"},
+		{"0o17", "15"},
+		{"-0x1F", "-31"},
+		{"+0b11", "3"},
+		{" 0xff_ff ", "65535"},
+		{"0x0", "0"},
+		{"0x7fffffffffffffff", "~9223372036854775800"},
+		{"-0x8000000000000000", "~-9223372036854775800"},
+		{"42", "42"},
+		{"017", "17"},
+		{"-1.5", "-1.5"},
+		{"1e3", "1000"},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromIntLiteral(c.s); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromIntLiteral(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"0x", "0xg", "0b102", "0o8", "0x1.5", "0x_1_", "0b"} {
+		if d, err := NewFromIntLiteral(s); err != ErrSyntax {
+			t.Errorf(`NewFromIntLiteral(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+	for _, s := range []string{"0x8000000000000000", "-0x8000000000000001", "0b" + strings.Repeat("1", 64)} {
+		if d, err := NewFromIntLiteral(s); err != ErrOutOfRange {
+			t.Errorf(`NewFromIntLiteral(%q) should return ErrOutOfRange and not %v (err = %v)`, s, d, err)
+		}
+	}
+	for _, s := range []string{"abc", "--0x1", "1x1"} {
+		if d, err := NewFromIntLiteral(s); err == nil {
+			t.Errorf(`NewFromIntLiteral(%q) should return an error and not %v`, s, d)
+		}
+	}
+}
+
+func TestNewFromStringFraction(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{"22/7", "~3.1428571428571429"},
+		{"3/4", "0.75"},
+		{"-3/4", "-0.75"},
+		{"3/-4", "-0.75"},
+		{"-3/-4", "0.75"},
+		{" 1 / 3 ", "~0.3333333333333333"},
+		{"1_000/8", "125"},
+		{"0/5", "0"},
+		{"2/3", "~0.6666666666666667"},
+		{"12.5", "12.5"},
+		{"50%", "0.5"},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromStringFraction(c.s); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromStringFraction(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"1/2/3", "/2", "1/", "/", "1.5/2", "1/2.5", "1e3/2", "a/2", "1/~2", "--1/2", "+/2"} {
+		if d, err := NewFromStringFraction(s); err != ErrSyntax {
+			t.Errorf(`NewFromStringFraction(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+
+	if d, err := NewFromStringFraction("1/0"); err != ErrDivisionByZero || !d.IsNaN() {
+		t.Errorf(`NewFromStringFraction("1/0") should be NaN with ErrDivisionByZero and not %v (err = %v)`, d, err)
+	}
+
+	// the plain parser does not accept fractions
+	if d, err := NewFromString("22/7"); err == nil {
+		t.Errorf(`NewFromString("22/7") should return an error and not %v`, d)
+	}
+}
+
+func TestNewFromStringScientific(t *testing.T) {
+	for _, s := range []string{"1.5E+10", "1.5e+10", "1.5E10", "1.5e10", "15e9", "15E+9", "0.15E11", "+1.5e10", "150000000000e-1"} {
+		if d, err := NewFromString(s); err != nil || d != 15000000000 {
+			t.Errorf(`NewFromString(%q) should be 15000000000 and not %v (err = %v)`, s, d, err)
+		}
+	}
+	for _, s := range []string{"1.5E-10", "1.5e-10", "-1.5e-10", "-1.5E-10"} {
+		if d, err := NewFromString(s); err != nil || d.Abs() != New(15, -11) {
+			t.Errorf(`NewFromString(%q) should be +/-1.5e-10 and not %v (err = %v)`, s, d, err)
+		}
+	}
+
+	// huge exponents saturate instead of wrapping around
+	if d, err := NewFromString("1e99999999999999999999999"); err != nil || d != PositiveInfinity {
+		t.Errorf(`NewFromString("1e99999999999999999999999") should be +Inf and not %v (err = %v)`, d, err)
+	}
+	if d, err := NewFromString("-1e-99999999999999999999999"); err != nil || d != NearNegativeZero {
+		t.Errorf(`NewFromString("-1e-99999999999999999999999") should be -~0 and not %v (err = %v)`, d, err)
+	}
+
+	for _, s := range []string{"1.5E", "1.5e", "1e", "1.5e+", "1.5E-", "1.5e+x", "e5", "E5", "-e5", ".e5", "e"} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+	}
+
+	// a trailing e still errors with a unit-aware type
+	if w, err := NewWeightFromString("1.5e"); err != ErrSyntax {
+		t.Errorf(`NewWeightFromString("1.5e") should return ErrSyntax and not %v (err = %v)`, w, err)
+	}
+}
+
+func TestNewFromStringMaxParseLength(t *testing.T) {
+	huge := strings.Repeat("9", 10<<20)
+	if d, err := NewFromString(huge); err != ErrTooLong {
+		t.Errorf(`NewFromString(10MB of digits) should return ErrTooLong and not %v (err = %v)`, d, err)
+	}
+	if w, err := NewWeightFromString(huge + "kg"); err != ErrTooLong {
+		t.Errorf(`NewWeightFromString(10MB of digits) should return ErrTooLong and not %v (err = %v)`, w, err)
+	}
+	if l, err := NewLengthFromString(huge + "m"); err != ErrTooLong {
+		t.Errorf(`NewLengthFromString(10MB of digits) should return ErrTooLong and not %v (err = %v)`, l, err)
+	}
+	if d, err := NewFromStringLocale(huge, ',', '.'); err != ErrTooLong {
+		t.Errorf(`NewFromStringLocale(10MB of digits) should return ErrTooLong and not %v (err = %v)`, d, err)
+	}
+	var d Decimal
+	if err := d.UnmarshalJSON([]byte(huge)); err != ErrTooLong {
+		t.Errorf(`UnmarshalJSON(10MB of digits) should return ErrTooLong and not %v (err = %v)`, d, err)
+	}
+
+	// long but valid inputs still parse, up to MaxParseLength bytes
+	long := "0." + strings.Repeat("0", MaxParseLength-7) + "1e500"
+	if d, err := NewFromString(long); err != nil || d != New(1, -6) {
+		t.Errorf(`NewFromString(%d bytes) should be 0.000001 and not %v (err = %v)`, len(long), d, err)
+	}
+	longer := "0." + strings.Repeat("0", MaxParseLength-6) + "1e500"
+	if d, err := NewFromString(longer); err != ErrTooLong {
+		t.Errorf(`NewFromString(%d bytes) should return ErrTooLong and not %v (err = %v)`, len(longer), d, err)
+	}
+
+	// the check may be disabled
+	defer func(n int) { MaxParseLength = n }(MaxParseLength)
+	MaxParseLength = 0
+	if d, err := NewFromString(longer); err != nil || d != New(1, -7) {
+		t.Errorf(`NewFromString(%d bytes) should be 0.0000001 when MaxParseLength is 0 and not %v (err = %v)`, len(longer), d, err)
+	}
+}
+
+func TestNewFromStringOverlong(t *testing.T) {
+	// excess significant digits must be rounded to the nearest once, not truncated nor rounded digit by digit
+	for _, x := range []struct {
+		s, r string
+	}{
+		{"1.99999999999999999", "~2"},
+		{"-1.99999999999999999", "~-2"},
+		{"14411518807585587149", "~14411518807585587100"},
+		{"1234567890123456785000000000000", "~1234567890123456780000000000000"},
+		{"1234567890123456785000000000001", "~1234567890123456790000000000000"},
+		{"-1234567890123456785000000000001", "~-1234567890123456790000000000000"},
+		{"1.0000000000000000000001", "~1"},
+		{"0.12345678901234567850000001", "~0.1234567890123457"},
+	} {
+		d, err := NewFromString(x.s)
+		if err != nil {
+			t.Errorf(`NewFromString(%q) should not return error %v`, x.s, err)
+		}
+		if d.String() != x.r {
+			t.Errorf(`NewFromString(%q) should be %s and not %v`, x.s, x.r, d)
+		}
+		if d.IsExact() {
+			t.Errorf(`NewFromString(%q).IsExact() should be false`, x.s)
+		}
+	}
+}
+
+func TestNewFromStringTrailingExponent(t *testing.T) {
+	// an exponent marker at the very end of the input must not be read past the end of the slice
+	for _, s := range []string{"12e", "12e+", "12e-", "12E", "-12E+", "~12e-"} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+
+		var d Decimal
+		if err := d.UnmarshalJSON([]byte(s)); err != ErrSyntax {
+			t.Errorf(`UnmarshalJSON(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+		if err := d.UnmarshalText([]byte(s)); err != ErrSyntax {
+			t.Errorf(`UnmarshalText(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+		if w, err := NewWeightFromString(s); err != ErrSyntax {
+			t.Errorf(`NewWeightFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, w, err)
+		}
+		if l, err := NewLengthFromString(s); err != ErrSyntax {
+			t.Errorf(`NewLengthFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, l, err)
+		}
+
+		// a digit is hidden in the spare capacity after the input so that reading past its length cannot go unnoticed
+		b := []byte(s + "0")[:len(s)]
+		if d, err := NewFromBytes(b); err != ErrSyntax {
+			t.Errorf(`NewFromBytes(%q) should return ErrSyntax and not %v (err = %v)`, b, d, err)
+		}
+	}
+}
+
 func TestNewFromStringNans(t *testing.T) {
 	nans := [...]string{"nan", "naN", "nAn", "nAN", "Nan", "NaN", "NAn", "NAN"}
 	for _, s := range nans {
@@ -554,6 +1105,35 @@ func TestNewFromInt(t *testing.T) {
 	}
 }
 
+func TestNewFromFraction(t *testing.T) {
+	if d := NewFromFraction(1, 4); d != New(25, -2) || !d.IsExact() {
+		t.Errorf(`NewFromFraction(1, 4) should be exactly 0.25 and not %v`, d)
+	}
+	if d := NewFromFraction(-3, 4); d != New(-75, -2) || !d.IsExact() {
+		t.Errorf(`NewFromFraction(-3, 4) should be exactly -0.75 and not %v`, d)
+	}
+	if d := NewFromFraction(10, -5); d != -2 {
+		t.Errorf(`NewFromFraction(10, -5) should be -2 and not %v`, d)
+	}
+	if d := NewFromFraction(1, 3); d != New(1, 0).Div(3) || d.IsExact() {
+		t.Errorf(`NewFromFraction(1, 3) should be %v and not %v`, New(1, 0).Div(3), d)
+	}
+	if d := NewFromFraction(2, 3); d.String() != "~0.6666666666666667" {
+		t.Errorf(`NewFromFraction(2, 3) should be ~0.6666666666666667 and not %v`, d)
+	}
+	if d := NewFromFraction(0, 7); d != Zero {
+		t.Errorf(`NewFromFraction(0, 7) should be Zero and not %v`, d)
+	}
+	if d := NewFromFraction(math.MinInt64, 1); d != NewFromInt(math.MinInt64) {
+		t.Errorf(`NewFromFraction(math.MinInt64, 1) should be %v and not %v`, NewFromInt(math.MinInt64), d)
+	}
+	for _, n := range []int64{0, 1, -1} {
+		if d := NewFromFraction(n, 0); !d.IsNaN() {
+			t.Errorf(`NewFromFraction(%d, 0) should be NaN and not %v`, n, d)
+		}
+	}
+}
+
 func TestNewFromFloat(t *testing.T) {
 	if d := NewFromFloat(0); d != Zero {
 		t.Errorf(`NewFromFloat(0) should be Zero, d = %v`, d)
@@ -600,6 +1180,51 @@ func TestNewFromFloat(t *testing.T) {
 	}
 }
 
+func TestDenormalizeToZero(t *testing.T) {
+	tiny := SmallestPositive()
+	if tiny.String() != "0.0000000000000001" || tiny.Div(2).IsZero() || !tiny.Div(3).IsZero() {
+		t.Errorf(`SmallestPositive() should be the smallest positive decimal and not %v`, tiny)
+	}
+
+	cases := []struct {
+		d     func() Decimal
+		s     string
+		sign  int
+		exact bool
+	}{
+		{func() Decimal { return tiny.Div(3) }, "+~0", 1, false},
+		{func() Decimal { return tiny.Neg().Div(3) }, "-~0", -1, false},
+		{func() Decimal { return tiny.Mul(tiny) }, "+~0", 1, false},
+		{func() Decimal { return NewFromFloat(1.1e-70) }, "+~0", 1, false},
+		{func() Decimal { return NewFromFloat(math.Copysign(0, -1)) }, "-~0", -1, false},
+		{func() Decimal { return RequireFromString("-1e-40") }, "-~0", -1, false},
+	}
+
+	for _, c := range cases {
+		if d := c.d(); d.String() != c.s || d.Sign() != c.sign || d.IsExact() != c.exact || !d.IsZero() && c.sign == 0 {
+			t.Errorf(`%v should be %s of sign %d`, d, c.s, c.sign)
+		}
+	}
+
+	DenormalizeToZero = true
+	defer func() { DenormalizeToZero = false }()
+
+	for _, c := range cases {
+		s, sign, exact := c.s, c.sign, c.exact
+		if s == "+~0" || s == "-~0" {
+			s, sign, exact = "0", 0, true
+		}
+
+		if d := c.d(); d.String() != s || d.Sign() != sign || d.IsExact() != exact || !d.IsZero() && sign == 0 {
+			t.Errorf(`with DenormalizeToZero, %v should be %s of sign %d`, d, s, sign)
+		}
+	}
+
+	if d := tiny.Div(3); d != Zero {
+		t.Errorf(`with DenormalizeToZero, %v / 3 should be Zero and not %v`, tiny, d)
+	}
+}
+
 func TestNewFromFloat32(t *testing.T) {
 	if d := NewFromFloat32(0); d != Zero {
 		t.Errorf(`NewFromFloat32(0) should be Zero, d = %v`, d)
@@ -633,6 +1258,22 @@ func TestNewFromFloat32(t *testing.T) {
 		t.Errorf(`NewFromFloat32(NaN) should be NaN, d = %v`, d)
 	}
 
+	// only a zero fraction with the maximal exponent is an infinity, like NewFromFloat does for float64
+	for _, b := range []uint32{0x7f800001, 0xff800001, 0x7fc00000, 0xffffffff} {
+		if d := NewFromFloat32(math.Float32frombits(b)); !d.IsNaN() {
+			t.Errorf(`NewFromFloat32(%#x) should be NaN, d = %v`, b, d)
+		}
+	}
+	// the largest finite float32 is beyond the range of a decimal, it is not an infinite float32 though
+	if d := NewFromFloat32(math.MaxFloat32); d != PositiveInfinity {
+		t.Errorf(`NewFromFloat32(math.MaxFloat32) should overflow to +Inf, d = %v`, d)
+	}
+	if d := NewFromFloat32(1e30); d.IsInfinite() || d.Sign() <= 0 {
+		t.Errorf(`NewFromFloat32(1e30) should be finite and positive, d = %v`, d)
+	}
+	if d, f := NewFromFloat32(float32(math.Inf(-1))), NewFromFloat(math.Inf(-1)); d != f {
+		t.Errorf(`NewFromFloat32(-Inf) should be %v like NewFromFloat(-Inf), d = %v`, f, d)
+	}
 }
 
 func TestNewFromFloatWithExponent(t *testing.T) {
@@ -693,6 +1334,90 @@ func TestNewOneOrOnOrYesFromString(t *testing.T) {
 	}
 }
 
+func TestNewFromNumberWord(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"zero", Zero},
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+		{"four", 4},
+		{"five", 5},
+		{"six", 6},
+		{"seven", 7},
+		{"eight", 8},
+		{"nine", 9},
+		{"ten", 10},
+		{"dozen", 12},
+		{"hundred", 100},
+		{"thousand", 1000},
+		{"million", 1000000},
+		{"Thousand", 1000},
+		{"DOZEN", 12},
+		{" Ten ", 10},
+		{"-two", -2},
+		{"ZeRo", Zero},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromString(c.s); err != nil || d != c.want {
+			t.Errorf(`NewFromString(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	if w, err := NewWeightFromString("dozen"); err != nil || w.String() != "12kg" {
+		t.Errorf(`NewWeightFromString("dozen") should be 12kg and not %v (err = %v)`, w, err)
+	}
+
+	for _, s := range []string{"eleven", "twenty", "billion", "dozens", "one hundred", "2 dozen"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+}
+
+func TestNewFromStringNumeric(t *testing.T) {
+	cases := []struct {
+		s, want string
+	}{
+		{"0", "0"},
+		{"-123.45", "-123.45"},
+		{"+1.5e3", "1500"},
+		{"1E-3", "0.001"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"~0.1", "~0.1"},
+		{" 42 ", "42"},
+		{"inf", "+Inf"},
+		{"-Infinity", "-Inf"},
+		{"NaN", "NaN"},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromStringNumeric(c.s); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromStringNumeric(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"yes", "no", "on", "off", "nil", "null", "zero", "dozen", "-one", "50%", "25bp", "(12)", "1 000", "1_000", "1kg", "0x1F", "", "e", "1e"} {
+		if d, err := NewFromStringNumeric(s); err == nil {
+			t.Errorf(`NewFromStringNumeric(%q) should return an error and not %v`, s, d)
+		}
+	}
+	if _, err := NewFromStringNumeric("no"); err != ErrSyntax {
+		t.Errorf(`NewFromStringNumeric("no") should return ErrSyntax and not %v`, err)
+	}
+
+	// the lenient parser is unchanged
+	for _, c := range []struct{ s, want string }{{"yes", "1"}, {"off", "0"}, {"null", "0"}, {"50%", "0.5"}} {
+		if d, err := NewFromString(c.s); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromString(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+}
+
 func TestNewPositiveInfiniteFromString(t *testing.T) {
 	infs := [...]string{"inf", "inF", "iNf", "iNF", "Inf", "InF", "INf", "INF", "+inf", "+inF", "+iNf", "+iNF", "+Inf", "+InF", "+INf", "+INF", "1E1000", "123456789012345678901234567890123456789"}
 	for _, s := range infs {
@@ -770,6 +1495,35 @@ func TestNewFromStringErrors(t *testing.T) {
 	}
 }
 
+func TestNewFromStringLoneSign(t *testing.T) {
+	// a sign or a tilde without any digit is not a number, it must never be read as Null or Zero
+	for _, s := range []string{"-", "+", "~", " - ", "+~", "~-", `"-"`, `"+"`, `"~"`} {
+		if d, err := NewFromString(s); err != ErrSyntax {
+			t.Errorf(`NewFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
+
+		d := Decimal(1)
+		if err := d.UnmarshalJSON([]byte(s)); err != ErrSyntax || d != 1 {
+			t.Errorf(`UnmarshalJSON(%q) should return ErrSyntax and keep 1, not %v (err = %v)`, s, d, err)
+		}
+		if err := d.UnmarshalText([]byte(s)); err != ErrSyntax || d != 1 {
+			t.Errorf(`UnmarshalText(%q) should return ErrSyntax and keep 1, not %v (err = %v)`, s, d, err)
+		}
+
+		if w, err := NewWeightFromString(s); err != ErrSyntax {
+			t.Errorf(`NewWeightFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, w, err)
+		}
+		if l, err := NewLengthFromString(s); err != ErrSyntax {
+			t.Errorf(`NewLengthFromString(%q) should return ErrSyntax and not %v (err = %v)`, s, l, err)
+		}
+	}
+
+	// while an empty input is Null
+	if d, err := NewFromString(""); err != nil || d != Null {
+		t.Errorf(`NewFromString("") should be Null and not %v (err = %v)`, d, err)
+	}
+}
+
 func TestIsEqual(t *testing.T) {
 	d, err := NewFromString("0.001")
 	if err != nil {
@@ -803,6 +1557,44 @@ func TestIsEqual(t *testing.T) {
 	}
 }
 
+func TestEqualAtScale(t *testing.T) {
+	cases := []struct {
+		d1, d2 string
+		places int32
+		want   bool
+	}{
+		{"10.004", "9.996", 2, true},
+		{"10.004", "10.001", 2, true},
+		{"1.2345", "1.2349", 3, true},
+		{"1.2345", "1.2349", 4, false},
+		{"10.01", "10.02", 2, false},
+		{"10.004", "9.996", 3, false},
+		{"~0.3333333333333333", "0.33", 2, true},
+		{"1249", "1201", -2, true},
+		{"1249", "1251", -2, false},
+		{"0.004", "0", 2, true},
+		{"-0.004", "0.004", 2, true},
+		{"+Inf", "+Inf", 2, true},
+		{"-Inf", "-Inf", 0, true},
+		{"+Inf", "-Inf", 2, false},
+		{"+Inf", "1e30", 2, false},
+		{"NaN", "NaN", 2, false},
+		{"NaN", "1", 2, false},
+		{"1", "NaN", 2, false},
+	}
+
+	for _, c := range cases {
+		d1, d2 := RequireFromString(c.d1), RequireFromString(c.d2)
+
+		if r := d1.EqualAtScale(d2, c.places); r != c.want {
+			t.Errorf(`%v.EqualAtScale(%v, %d) should be %t and not %t`, d1, d2, c.places, c.want, r)
+		}
+		if r := d2.EqualAtScale(d1, c.places); r != c.want {
+			t.Errorf(`%v.EqualAtScale(%v, %d) should be %t and not %t`, d2, d1, c.places, c.want, r)
+		}
+	}
+}
+
 func TestRoundZeros(t *testing.T) {
 	zeros := [...]string{"0", "~0", "-~0", "+~0", "0.4999999999", "~0.49", "0.3567433445234", "1e-10", "-0.333", "-0.5", "~-0.5"}
 	for _, s := range zeros {
@@ -995,6 +1787,75 @@ func TestRoundFloor(t *testing.T) {
 	}
 }
 
+func TestRoundFloorCeilNoSignedZero(t *testing.T) {
+	values := []Decimal{New(1, -16), New(4, -3), New(5, -3), New(6, -3), New(5, -1), New(999, -3), 5, New(MaxInt, -16)}
+
+	for _, d := range values {
+		for places := int32(-40); places <= 16; places++ {
+			for _, r := range []Decimal{d.RoundFloor(places), d.Neg().RoundFloor(places), d.RoundCeil(places), d.Neg().RoundCeil(places)} {
+				// a zero result is always exactly Zero, never a signed or null zero
+				if r.IsZero() && r != Zero || r.String() == "-0" {
+					t.Errorf(`rounding floor/ceil ±%v to %d places should be exactly Zero and not %#v`, d, places, r)
+				}
+			}
+
+			// flooring a negative value (or ceiling a positive one) never reaches zero
+			if r := d.Neg().RoundFloor(places); !r.IsNegative() || r.GreaterThan(d.Neg()) {
+				t.Errorf(`-%v rounded floor to %d places should be a negative decimal below it and not %v`, d, places, r)
+			}
+			if r := d.RoundCeil(places); !r.IsPositive() || r.LessThan(d) {
+				t.Errorf(`%v rounded ceil to %d places should be a positive decimal above it and not %v`, d, places, r)
+			}
+
+			// while flooring a positive value (or ceiling a negative one) never changes its sign
+			if r := d.RoundFloor(places); r.IsNegative() || r.GreaterThan(d) {
+				t.Errorf(`%v rounded floor to %d places should be zero or a positive decimal below it and not %v`, d, places, r)
+			}
+			if r := d.Neg().RoundCeil(places); r.IsPositive() || r.LessThan(d.Neg()) {
+				t.Errorf(`-%v rounded ceil to %d places should be zero or a negative decimal above it and not %v`, d, places, r)
+			}
+		}
+	}
+
+	if d := New(-5, 0).RoundFloor(-20); d.String() != "-100000000000000000000" {
+		t.Errorf(`-5 rounded floor to -20 places should be -100000000000000000000 and not %v`, d)
+	}
+	if d := New(5, 0).RoundCeil(-20); d.String() != "100000000000000000000" {
+		t.Errorf(`5 rounded ceil to -20 places should be 100000000000000000000 and not %v`, d)
+	}
+	if d := New(-5, 0).RoundFloor(-40); d != NegativeInfinity {
+		t.Errorf(`-5 rounded floor to -40 places should be -Inf and not %v`, d)
+	}
+}
+
+func TestRoundNoop(t *testing.T) {
+	rounds := map[string]func(Decimal, int32) Decimal{
+		"Round":      Decimal.Round,
+		"RoundBank":  Decimal.RoundBank,
+		"RoundCeil":  Decimal.RoundCeil,
+		"RoundFloor": Decimal.RoundFloor,
+	}
+
+	for name, round := range rounds {
+		// already rounded exact values are returned as is
+		for _, d := range []Decimal{1, -1, New(145, -2), New(-145, -2), New(1, -2), New(7, 15), MaxInt} {
+			if r := round(d, 2); r != d || !r.IsExact() {
+				t.Errorf(`%v.%s(2) should be exactly %v and not %v`, d, name, d, r)
+			}
+		}
+
+		// no rounding needed but the loss bit is still cleared
+		if r := round(NewFromFloat64Exact(1.5, false), 2); r != New(15, -1) || !r.IsExact() {
+			t.Errorf(`~1.5.%s(2) should be exactly 1.5 and not %v`, name, r)
+		}
+
+		// Null is rounded to Zero
+		if r := round(Null, 2); r != Zero {
+			t.Errorf(`Null.%s(2) should be Zero and not %v`, name, r)
+		}
+	}
+}
+
 func TestRoundBank(t *testing.T) {
 	if d := NearZero.RoundBank(1); d != Zero {
 		t.Errorf(`~0 rounded ceil to 1 decimal should be exactly 0 and not %v`, d)
@@ -1016,8 +1877,51 @@ func TestRoundBank(t *testing.T) {
 		t.Errorf(`555 rounded bank to -1 decimals should be 560 and not %v`, d)
 	}
 
-	if d := NewFromFloat(5.45).RoundBank(1); d != New(54, -1) {
-		t.Errorf(`5.45 rounded bank to 1 decimals should be 5.4 and not %v`, d)
+	if d := NewFromFloat(5.45).RoundBank(1); d != New(54, -1) {
+		t.Errorf(`5.45 rounded bank to 1 decimals should be 5.4 and not %v`, d)
+	}
+}
+
+func TestRoundSQL(t *testing.T) {
+	// half boundaries: SQL Server / MySQL / PostgreSQL numeric ROUND (half away from zero) and half even
+	cases := []struct {
+		d            string
+		places       int32
+		halfUp, even string
+	}{
+		{"2.5", 0, "3", "2"},
+		{"3.5", 0, "4", "4"},
+		{"-2.5", 0, "-3", "-2"},
+		{"-3.5", 0, "-4", "-4"},
+		{"0.125", 2, "0.13", "0.12"},
+		{"-0.125", 2, "-0.13", "-0.12"},
+		{"-0.135", 2, "-0.14", "-0.14"},
+		{"1.2451", 2, "1.25", "1.25"},
+		{"-1.2449", 2, "-1.24", "-1.24"},
+		{"125", -1, "130", "120"},
+		{"-125", -1, "-130", "-120"},
+		{"-0.4", 0, "0", "0"},
+		{"7", 2, "7", "7"},
+	}
+
+	for _, c := range cases {
+		d := RequireFromString(c.d)
+		if r := d.RoundSQL(c.places, false); r.String() != c.halfUp {
+			t.Errorf(`%v.RoundSQL(%d, false) should be %s and not %v`, d, c.places, c.halfUp, r)
+		}
+		if r := d.RoundSQL(c.places, true); r.String() != c.even {
+			t.Errorf(`%v.RoundSQL(%d, true) should be %s and not %v`, d, c.places, c.even, r)
+		}
+	}
+
+	if d := NearNegativeZero.RoundSQL(2, false); d != Zero {
+		t.Errorf(`~-0.RoundSQL(2, false) should be exactly 0 and not %v`, d)
+	}
+	if d := NaN.RoundSQL(2, false); !d.IsNaN() {
+		t.Errorf(`NaN.RoundSQL(2, false) should be NaN and not %v`, d)
+	}
+	if d := NegativeInfinity.RoundSQL(2, false); d != NegativeInfinity {
+		t.Errorf(`-Inf.RoundSQL(2, false) should be -Inf and not %v`, d)
 	}
 }
 
@@ -1331,6 +2235,72 @@ func TestQuoRem(t *testing.T) {
 	log.Printf("%v = %v * %v, remainder = %v", d1, d2, q, r)
 }
 
+func TestQuoRemPrecision(t *testing.T) {
+	cases := []struct {
+		d1, d2    Decimal
+		precision int32
+		q, r      string
+	}{
+		{New(14569568235, -3), New(5607988, -5), -2, "259800", "15.411"},
+		{New(14569568235, -3), New(5607988, -5), -1, "259800", "15.411"},
+		{New(14569568235, -3), New(5607988, -5), 0, "259800", "15.411"},
+		{New(14569568235, -3), New(5607988, -5), 2, "259800.27", "0.2694324"},
+		{New(14569568235, -3), New(5607988, -5), -5, "200000", "3353592.235"},
+		{New(14569568235, -3), New(5607988, -5), -6, "0", "14569568.235"},
+		{-7, 2, 0, "-3", "-1"},
+		{7, -2, 0, "-3", "1"},
+		{-7, -2, 0, "3", "-1"},
+		{New(-12345, 0), 7, -2, "-1700", "-445"},
+		{New(76, 0), New(-15, -1), -1, "-50", "1"},
+		{New(4235, -2), New(55, -1), 1, "7.7", "0"},
+		{1, 3, 5, "0.33333", "0.00001"},
+		{1, New(3, 10), 0, "0", "1"},
+	}
+
+	for _, c := range cases {
+		q, r := c.d1.QuoRem(c.d2, c.precision)
+
+		if q.String() != c.q || r.String() != c.r {
+			t.Errorf(`%v.QuoRem(%v, %d) should be %s, %s and not %v, %v`, c.d1, c.d2, c.precision, c.q, c.r, q, r)
+		}
+
+		// d1 = d2 * q + r, r having the sign of d1 and |r| < |d2| * 10^-precision
+		if !q.Mul(c.d2).Add(r).Equal(c.d1) || !q.IsExact() || !r.IsExact() {
+			t.Errorf(`%v * %v + %v should be exactly %v`, q, c.d2, r, c.d1)
+		}
+		if r.Sign() != 0 && r.Sign() != c.d1.Sign() {
+			t.Errorf(`%v.QuoRem(%v, %d) remainder %v should have the sign of %v`, c.d1, c.d2, c.precision, r, c.d1)
+		}
+		if !r.Abs().LessThan(c.d2.Abs().Shift(-c.precision)) {
+			t.Errorf(`%v.QuoRem(%v, %d) remainder %v should be less than %v`, c.d1, c.d2, c.precision, r, c.d2.Abs().Shift(-c.precision))
+		}
+		if !q.Shift(c.precision).IsInteger() {
+			t.Errorf(`%v.QuoRem(%v, %d) quotient %v should be a multiple of 10^%d`, c.d1, c.d2, c.precision, q, -c.precision)
+		}
+	}
+}
+
+func TestQuoRemOverflow(t *testing.T) {
+	cases := []struct {
+		d1, d2    Decimal
+		precision int32
+		q, r      string
+	}{
+		{New(1, 15), 3, 16, "~333333333333333.33", "0.0000000000000001"},
+		{New(2, 15), 3, 16, "~666666666666666.67", "0.0000000000000002"},
+		{New(-5, 10), New(7, -3), 12, "~-7142857142857.1429", "-0.000000000000001"},
+	}
+
+	for _, c := range cases {
+		// the quotient is wider than 64 bits, it is rounded to the mantissa while the remainder stays exact
+		q, r := c.d1.QuoRem(c.d2, c.precision)
+
+		if q.String() != c.q || r.String() != c.r {
+			t.Errorf(`%v.QuoRem(%v, %d) should be %s, %s and not %v, %v`, c.d1, c.d2, c.precision, c.q, c.r, q, r)
+		}
+	}
+}
+
 func TestMod(t *testing.T) {
 	d1 := NewFromInt(4)
 	d2 := NewFromInt(3)
@@ -1360,6 +2330,34 @@ func TestNeg(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want string
+	}{
+		{"2", "2", ""},
+		{"~1.5", "~1.5", ""},
+		{"NaN", "NaN", ""},
+		{"2", "~1.9999999999999998", "a is 2 (exact), b is ~1.9999999999999998 (inexact, 2 ULP below a)"},
+		{"1.5", "1.51", "a is 1.5 (exact), b is 1.51 (exact, 1 ULP above a)"},
+		{"2", "~2", "a is 2 (exact), b is ~2 (inexact, same value as a)"},
+		{"-0.01", "0.01", "a is -0.01 (exact), b is 0.01 (exact, 2 ULP above a, opposite sign)"},
+		{"1", "0", "a is 1 (exact), b is 0 (exact, 1 ULP below a)"},
+		{"1e15", "1e-15", "a is 1000000000000000 (exact), b is 0.000000000000001 (exact, ~1000000000000000 below a)"},
+		{"1", "NaN", "a is 1 (exact), b is NaN (inexact, not comparable to a)"},
+		{"1", "+Inf", "a is 1 (exact), b is +Inf (inexact, above a)"},
+		{"-Inf", "1", "a is -Inf (inexact), b is 1 (exact, above a)"},
+		{"+Inf", "+Inf", ""},
+	}
+
+	for _, c := range cases {
+		a, b := RequireFromString(c.a), RequireFromString(c.b)
+		if s := Diff(a, b); s != c.want {
+			t.Errorf(`Diff(%v, %v) should be %q and not %q`, a, b, c.want, s)
+		}
+	}
+}
+
 func TestCompare(t *testing.T) {
 	zeros := [...]Decimal{0, Zero, NearZero, NearPositiveZero, NearNegativeZero}
 	for _, d1 := range zeros {
@@ -1480,6 +2478,108 @@ func TestDiv(t *testing.T) {
 	}
 }
 
+func TestChecked(t *testing.T) {
+	big := New(1, 31)
+	tiny := New(1, -16)
+
+	cases := []struct {
+		op     string
+		d1, d2 Decimal
+		r      string
+		err    error
+	}{
+		{"+", 1, 2, "3", nil},
+		{"+", New(15, -1), New(-5, -1), "1", nil},
+		{"+", MaxInt, 1, "~144115188075855870", ErrInexact},
+		{"+", New(MaxInt, -1), New(1, -16), "~14411518807585587.1", ErrInexact},
+		{"+", big.Mul(9), big.Mul(9), "+Inf", ErrOutOfRange},
+		{"+", PositiveInfinity, 1, "+Inf", nil},
+		{"+", NewFromInt(1).Div(3), 1, "~1.3333333333333333", nil},
+		{"-", 1, 3, "-2", nil},
+		{"-", big.Mul(-9), big.Mul(9), "-Inf", ErrOutOfRange},
+		{"-", PositiveInfinity, PositiveInfinity, "NaN", nil},
+		{"*", New(15, -1), 4, "6", nil},
+		{"*", MaxInt, 3, "~432345564227567610", ErrInexact},
+		{"*", big, 10, "100000000000000000000000000000000", nil},
+		{"*", big, 100, "+Inf", ErrOutOfRange},
+		{"*", big, -100, "-Inf", ErrOutOfRange},
+		{"*", tiny, tiny, "+~0", ErrOutOfRange},
+		{"*", tiny, New(-1, -3), "-~0", ErrOutOfRange},
+		{"*", NearPositiveZero, 2, "+~0", nil},
+		{"*", NewFromInt(2).Div(3), 3, "~2.0000000000000001", nil},
+		{"/", 1, 8, "0.125", nil},
+		{"/", 1, 3, "~0.3333333333333333", ErrInexact},
+		{"/", -2, 3, "~-0.6666666666666667", ErrInexact},
+		{"/", big, tiny, "+Inf", ErrOutOfRange},
+		{"/", tiny, big, "+~0", ErrOutOfRange},
+		{"/", 1, 0, "NaN", ErrDivisionByZero},
+		{"/", 1, Zero, "NaN", ErrDivisionByZero},
+		{"/", NewFromInt(1).Div(3), 2, "~0.1666666666666667", nil},
+	}
+
+	for _, c := range cases {
+		var r Decimal
+		var err error
+
+		switch c.op {
+		case "+":
+			r, err = c.d1.AddChecked(c.d2)
+		case "-":
+			r, err = c.d1.SubChecked(c.d2)
+		case "*":
+			r, err = c.d1.MulChecked(c.d2)
+		case "/":
+			r, err = c.d1.DivChecked(c.d2)
+		}
+
+		if r.String() != c.r || err != c.err {
+			t.Errorf(`%v %s %v should be %s with error %v and not %v with error %v`, c.d1, c.op, c.d2, c.r, c.err, r, err)
+		}
+	}
+}
+
+func TestDivPowerOfTen(t *testing.T) {
+	cases := []struct {
+		d1, d2, want string
+	}{
+		{"12345", "1000", "12.345"},
+		{"-12345", "100", "-123.45"},
+		{"1.5", "-10", "-0.15"},
+		{"-1.5", "-10", "0.15"},
+		{"12345", "0.01", "1234500"},
+		{"123", "1e15", "0.000000000000123"},
+		{"~0.3333333333333333", "10", "~0.0333333333333333"},
+		{"2", "~10", "~0.2"},
+		{"1", "1e16", "0.0000000000000001"},
+		{"123", "1e16", "0.0000000000000123"},
+		{"123", "1e17", "~0.0000000000000012"},
+		{"0", "1000", "0"},
+	}
+
+	for _, c := range cases {
+		d1, _ := NewFromString(c.d1)
+		d2, _ := NewFromString(c.d2)
+
+		if r := d1.Div(d2); r.String() != c.want {
+			t.Errorf(`%v / %v should be %s and not %v`, d1, d2, c.want, r)
+		}
+	}
+
+	// digits beyond DivisionPrecision go through the long division
+	DivisionPrecision = 2
+	defer func() { DivisionPrecision = 16 }()
+
+	if r := New(12345, -3).Div(10); r.IsExact() {
+		t.Errorf(`12.345 / 10 with DivisionPrecision = 2 should not be exact, r = %v`, r)
+	}
+	if r := New(12345, -3).Div(1000); r.IsExact() {
+		t.Errorf(`12.345 / 1000 with DivisionPrecision = 2 should not be exact, r = %v`, r)
+	}
+	if r := New(12345, 0).Div(100); r.String() != "123.45" || !r.IsExact() {
+		t.Errorf(`12345 / 100 with DivisionPrecision = 2 should be 123.45 and not %v`, r)
+	}
+}
+
 func TestDivMagic(t *testing.T) {
 	d := New(1, 0)
 
@@ -1601,6 +2701,202 @@ func TestSumAvg(t *testing.T) {
 	}
 }
 
+func TestClosest(t *testing.T) {
+	ladder := []Decimal{1, 5, 10, 25, 50, 100}
+
+	cases := []struct {
+		target     Decimal
+		candidates []Decimal
+		want       Decimal
+		index      int
+	}{
+		{New(74, -1), ladder, 5, 1},
+		{New(76, -1), ladder, 10, 2},
+		{New(75, -1), ladder, 5, 1}, // tie, the smaller one
+		{30, ladder, 25, 3},
+		{1000, ladder, 100, 5},
+		{-3, ladder, 1, 0},
+		{25, ladder, 25, 3},
+		{0, []Decimal{2, -2}, -2, 1},         // tie, the smaller one even if last
+		{0, []Decimal{NaN, 3, NaN, 1}, 1, 3}, // NaN skipped
+		{7, []Decimal{5, 9, 5}, 5, 0},        // equal candidates, the first one
+		{NewFromInt(1).Div(3), []Decimal{0, New(3, -1)}, New(3, -1), 1},
+		{PositiveInfinity, ladder, 1, 0}, // every distance is infinite
+	}
+
+	for _, c := range cases {
+		if v, i := Closest(c.target, c.candidates); v != c.want || i != c.index {
+			t.Errorf(`Closest(%v, %v) should be %v at %d and not %v at %d`, c.target, c.candidates, c.want, c.index, v, i)
+		}
+	}
+
+	for _, candidates := range [][]Decimal{nil, {}, {NaN, NaN}} {
+		if v, i := Closest(5, candidates); !v.IsNaN() || i != -1 {
+			t.Errorf(`Closest(5, %v) should be NaN at -1 and not %v at %d`, candidates, v, i)
+		}
+	}
+	if v, i := Closest(NaN, ladder); !v.IsNaN() || i != -1 {
+		t.Errorf(`Closest(NaN, %v) should be NaN at -1 and not %v at %d`, ladder, v, i)
+	}
+}
+
+func TestDot(t *testing.T) {
+	cases := []struct {
+		a, b []Decimal
+		want string
+	}{
+		{[]Decimal{New(1999, -2), New(5, -1)}, []Decimal{3, 4}, "61.97"},
+		{[]Decimal{1, 2, 3}, []Decimal{4, 5, 6}, "32"},
+		{[]Decimal{1, -2, 3}, []Decimal{New(5, -1), New(25, -2), -1}, "-3"},
+		{[]Decimal{New(1, -1), New(2, -1)}, []Decimal{New(1, -1), New(2, -1)}, "0.05"},
+		{[]Decimal{7}, []Decimal{New(-15, -1)}, "-10.5"},
+		{nil, nil, "0"},
+		// naive accumulation gives 0 as 1e20 + 3 loses the 3 with a 57 bits mantissa
+		{[]Decimal{New(1, 20), 3, New(-1, 20)}, []Decimal{1, 1, 1}, "~3"},
+		{[]Decimal{New(1, 10), 1, New(1, 10)}, []Decimal{New(1, 10), New(5, -1), New(-1, 10)}, "~0.5"},
+	}
+
+	for _, c := range cases {
+		if d, err := Dot(c.a, c.b); err != nil || d.String() != c.want {
+			t.Errorf(`Dot(%v, %v) should be %s and not %v (err = %v)`, c.a, c.b, c.want, d, err)
+		}
+	}
+
+	// the naive accumulation loses the small term
+	if naive := New(1, 20).Add(3).Add(New(-1, 20)); !naive.IsZero() {
+		t.Errorf(`1e20 + 3 - 1e20 should be ~0 with a naive accumulation and not %v`, naive)
+	}
+
+	if d, err := Dot([]Decimal{1, 2}, []Decimal{1}); err != ErrLengthMismatch || !d.IsNaN() {
+		t.Errorf(`Dot of slices of different lengths should be NaN with ErrLengthMismatch and not %v (err = %v)`, d, err)
+	}
+}
+
+func TestRoundPreservingSum(t *testing.T) {
+	third := NewFromInt(1).Div(3)
+
+	cases := []struct {
+		values []string
+		places int32
+		want   []string
+	}{
+		{[]string{"0.333", "0.333", "0.334"}, 2, []string{"0.33", "0.33", "0.34"}},
+		{[]string{"1.005", "1.005", "1.005", "1.005"}, 2, []string{"1.01", "1.01", "1", "1"}},
+		{[]string{"12.5", "12.5", "75"}, 0, []string{"13", "12", "75"}},
+		{[]string{"0.2", "0.2", "0.2", "0.2", "0.2"}, 0, []string{"1", "0", "0", "0", "0"}},
+		{[]string{"-0.333", "-0.333", "-0.334"}, 2, []string{"-0.33", "-0.33", "-0.34"}},
+		{[]string{"1.5", "-0.5"}, 0, []string{"2", "-1"}},
+		{[]string{"18.564", "81.436"}, 1, []string{"18.6", "81.4"}},
+		{[]string{"1.25", "2"}, 1, []string{"1.3", "2"}},
+		{[]string{"7"}, 2, []string{"7"}},
+	}
+
+	for _, c := range cases {
+		values := make([]Decimal, len(c.values))
+		for i, s := range c.values {
+			values[i] = RequireFromString(s)
+		}
+
+		r := RoundPreservingSum(values, c.places)
+		if fmt.Sprint(r) != fmt.Sprint(c.want) {
+			t.Errorf(`RoundPreservingSum(%v, %d) should be %v and not %v`, values, c.places, c.want, r)
+		}
+		if sum, total := Sum(r[0], r[1:]...), Sum(values[0], values[1:]...).Round(c.places); sum != total {
+			t.Errorf(`RoundPreservingSum(%v, %d) should sum to %v and not %v`, values, c.places, total, sum)
+		}
+	}
+
+	// naive rounding breaks the sum
+	values := []Decimal{third, third, third}
+	if sum := Sum(values[0].Round(2), values[1].Round(2), values[2].Round(2)); sum != New(99, -2) {
+		t.Errorf(`the sum of 3 times (1/3).Round(2) should be 0.99 and not %v`, sum)
+	}
+	if r := RoundPreservingSum(values, 2); fmt.Sprint(r) != "[0.34 0.33 0.33]" {
+		t.Errorf(`RoundPreservingSum(3 times 1/3, 2) should be [0.34 0.33 0.33] and not %v`, r)
+	}
+
+	if r := RoundPreservingSum(nil, 2); len(r) != 0 {
+		t.Errorf(`RoundPreservingSum(nil, 2) should be empty and not %v`, r)
+	}
+	if r := RoundPreservingSum([]Decimal{New(125, -2), PositiveInfinity}, 1); fmt.Sprint(r) != "[1.3 +Inf]" {
+		t.Errorf(`RoundPreservingSum([1.25 +Inf], 1) should be [1.3 +Inf] and not %v`, r)
+	}
+}
+
+func TestSumJSON(t *testing.T) {
+	data := []byte(`["0.1", 0.2, "1e30", 0.3, "-1e30", 1e-16, "0.0000000000000009"]`)
+
+	// 1e30 absorbs the small values on the way so the exact result is flagged as inexact
+	d, err := SumJSON(data)
+	if err != nil || d.String() != "~0.600000000000001" {
+		t.Errorf(`SumJSON(%s) should be ~0.600000000000001 and not %v (err = %v)`, data, d, err)
+	}
+
+	// the same naive sum through float64 loses every digit below 1e30
+	naive := 0.0
+	for _, f := range []float64{0.1, 0.2, 1e30, 0.3, -1e30, 1e-16, 0.0000000000000009} {
+		naive += f
+	}
+	if math.Abs(naive-0.600000000000001) < math.Abs(d.InexactFloat64()-0.600000000000001) {
+		t.Errorf(`naive float64 sum %v should not be closer to 0.600000000000001 than SumJSON %v`, naive, d)
+	}
+
+	if d, err := SumJSON([]byte(` [ ] `)); err != nil || d != Zero {
+		t.Errorf(`SumJSON([]) should be Zero and not %v (err = %v)`, d, err)
+	}
+	if d, err := SumJSON([]byte(`[1]`)); err != nil || d != 1 {
+		t.Errorf(`SumJSON([1]) should be 1 and not %v (err = %v)`, d, err)
+	}
+
+	for _, s := range []string{`[1, "abc", 2]`, `[1, true]`, `[1, null]`, `[1, [2]]`, `[1, {"a": 2}]`, `{"a": 1}`, `1`} {
+		if d, err := SumJSON([]byte(s)); err == nil {
+			t.Errorf(`SumJSON(%s) should error and not return %v`, s, d)
+		}
+	}
+	for _, s := range []string{``, `[1, 2`, `[1 2]`, `[1, 2]]`, `[1, 2] 3`} {
+		if d, err := SumJSON([]byte(s)); err == nil {
+			t.Errorf(`SumJSON(%s) should error and not return %v`, s, d)
+		}
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	// mean = 12, population stddev = sqrt(2400) ~ 48.99 so the z-score of 252 is ~ 4.9
+	values := []Decimal{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 252}
+
+	if idx := Outliers(values, 2); len(idx) != 1 || idx[0] != 24 {
+		t.Errorf(`Outliers(values, 2) should be [24] and not %v`, idx)
+	}
+	if idx := Outliers(values, 3); len(idx) != 1 || idx[0] != 24 {
+		t.Errorf(`Outliers(values, 3) should be [24] and not %v`, idx)
+	}
+	if idx := Outliers(values, 5); len(idx) != 0 {
+		t.Errorf(`Outliers(values, 5) should be empty and not %v`, idx)
+	}
+
+	values = []Decimal{10, 11, 9, 10, 12, 8, 10, 30}
+	if idx := Outliers(values, 2); len(idx) != 1 || idx[0] != 7 {
+		t.Errorf(`Outliers(%v, 2) should be [7] and not %v`, values, idx)
+	}
+	if idx := Outliers(values, 3); len(idx) != 0 {
+		t.Errorf(`Outliers(%v, 3) should be empty and not %v`, values, idx)
+	}
+
+	// constant dataset: zero standard deviation means no outlier
+	if idx := Outliers([]Decimal{New(15, -1), New(15, -1), New(15, -1)}, 2); idx != nil {
+		t.Errorf(`Outliers on a constant dataset should be nil and not %v`, idx)
+	}
+	if idx := Outliers(nil, 2); idx != nil {
+		t.Errorf(`Outliers(nil) should be nil and not %v`, idx)
+	}
+
+	// NaN and infinities are skipped and never reported
+	values = []Decimal{NaN, 10, 11, 9, 10, 12, 8, 10, PositiveInfinity, 30}
+	if idx := Outliers(values, 2); len(idx) != 1 || idx[0] != 9 {
+		t.Errorf(`Outliers(%v, 2) should be [9] and not %v`, values, idx)
+	}
+}
+
 func TestIntConversion(t *testing.T) {
 	var d Decimal
 
@@ -1710,6 +3006,78 @@ func TestSign(t *testing.T) {
 	}
 }
 
+func TestStringParseSymmetry(t *testing.T) {
+	third := NewFromInt(1).Div(3)
+
+	decimals := []Decimal{
+		Zero, NearZero, NearPositiveZero, NearNegativeZero, PositiveInfinity, NegativeInfinity, NaN,
+		1, -1, MaxInt, -MaxInt, MaxInt - 1, New(MaxInt+1, 0), New(-MaxInt-1, 0),
+		New(1, decimalMaxE), New(MaxInt, decimalMaxE), New(-MaxInt, decimalMaxE), New(1, decimalMinE), New(-1, decimalMinE), New(MaxInt, decimalMinE),
+		third, third.Neg(), NewFromInt(-2).Div(3), NewFromInt(22).Div(7).Mul(New(1, 20)), third.Mul(New(1, -10)),
+		NewFromFloat(1.1e-70), NewFromFloat(-1.1e-70), NewFromFloat(math.MaxFloat64), NewFromFloat(0.1), NewFromFloat(123456789.123456789),
+		RequireFromString("1e-40"), RequireFromString("-1e-40"), RequireFromString("~0"), RequireFromString("-~0"), RequireFromString("~-0.5"),
+		RequireFromString("123456789012345678901234567890"), RequireFromString("0.1234567890123456789"),
+	}
+
+	for _, d := range decimals {
+		s := d.String()
+		if p, err := NewFromString(s); err != nil || p != d && !(p.IsNaN() && d.IsNaN()) {
+			t.Errorf(`NewFromString(%q) should give back %x and not %x (%v)`, s, int64(d), int64(p), err)
+		}
+	}
+
+	var weights []Weight
+	for _, s := range []string{
+		"1kg", "-1.5lb", "~0lb", "+~0lb", "-~0lb", "1e-50kg", "-1e-50oz", "1e50kg", "-1e50kg", "1e50lb", "1e40g", "inf", "nan",
+		"2 gr", "1 ct", "1 short ton", "1 long ton", "3 stone", "1 mcg", "1 oz av", "~1.5 lb t", "0.45359237kg", "9007199254740991pg",
+	} {
+		w, err := NewWeightFromString(s)
+		if err != nil {
+			t.Errorf(`NewWeightFromString(%q) should not error, got %v`, s, err)
+		}
+		weights = append(weights, w, w.Div(3), w.Mul(New(1, 16)))
+	}
+
+	for _, w := range weights {
+		s := w.String()
+		if p, err := NewWeightFromString(s); err != nil || p != w && !(p.IsNaN() && w.IsNaN()) {
+			t.Errorf(`NewWeightFromString(%q) should give back %x and not %x (%v)`, s, int64(w), int64(p), err)
+		}
+	}
+
+	// any canonical bit pattern round-trips, Null excepted as it is output as 0
+	random := uint64(88172645463325252)
+	next := func() uint64 {
+		random ^= random << 13
+		random ^= random >> 7
+		random ^= random << 17
+
+		return random
+	}
+
+	for i := 0; i < 20000; i++ {
+		x := next()
+		if i%4 == 0 {
+			// magic values, with or without unit
+			x &^= MaxInt
+		}
+
+		if d := Decimal(x); d != Null && vmeAsDecimal(d.vme()) == d {
+			if p, err := NewFromString(d.String()); err != nil || p != d && !(p.IsNaN() && d.IsNaN()) {
+				t.Errorf(`NewFromString(%q) should give back %x and not %x (%v)`, d.String(), int64(d), int64(p), err)
+			}
+		}
+
+		if w := Weight(x); w != Null {
+			if v, m, e, u := w.vmet(); u.u != "" && vmeAsWeight(v, m, e) == w {
+				if p, err := NewWeightFromString(w.String()); err != nil || p != w && !(p.IsNaN() && w.IsNaN()) {
+					t.Errorf(`NewWeightFromString(%q) should give back %x and not %x (%v)`, w.String(), int64(w), int64(p), err)
+				}
+			}
+		}
+	}
+}
+
 func TestStringFixed(t *testing.T) {
 	var d Decimal
 
@@ -1757,6 +3125,92 @@ func TestStringFixed(t *testing.T) {
 		t.Errorf(`New(545, 0).StringFixedBank(-1) should be "540", but is %v`, s)
 	}
 }
+
+func TestRoundString(t *testing.T) {
+	cases := []struct {
+		d      Decimal
+		places int32
+		want   string
+	}{
+		{New(12345, -3), 2, "12.35"},
+		{New(-12345, -3), 2, "-12.34"},
+		{New(12345, -3), 0, "12"},
+		{New(12345, -3), 5, "12.34500"},
+		{New(545, 0), -1, "550"},
+		{New(1, 0).Div(New(3, 0)), 4, "0.3333"},
+		{Zero, 2, "0.00"},
+		{Null, 2, "0.00"},
+		{NearPositiveZero, 2, "0.00"},
+		{PositiveInfinity, 2, "+Inf"},
+		{NegativeInfinity, 2, "-Inf"},
+		{NaN, 2, "NaN"},
+	}
+
+	for _, c := range cases {
+		r, s := c.d.RoundString(c.places)
+
+		if r != c.d.Round(c.places) {
+			t.Errorf(`%v.RoundString(%d) should return %v and not %v`, c.d, c.places, c.d.Round(c.places), r)
+		}
+		if s != c.d.Round(c.places).StringFixed(c.places) || s != c.want {
+			t.Errorf(`%v.RoundString(%d) should return %q and not %q`, c.d, c.places, c.want, s)
+		}
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	cases := []struct {
+		d, want string
+	}{
+		{"2", "~1.414213562373095"}, // 1.41421356237309504880, the float64 square root gives 1.4142135623730951
+		{"3", "~1.7320508075688773"},
+		{"0.5", "~0.7071067811865475"},
+		{"10", "~3.1622776601683793"},
+		{"1e15", "~31622776.601683793"},
+		{"123456789", "~11111.1110605555554"},
+		{"144115188075855871", "~379625062.49700621"},
+		{"2e-16", "~0.0000000141421356"},
+		{"4", "2"},
+		{"0.0001", "0.01"},
+		{"1e-16", "0.00000001"},
+		{"15241578750190521", "123456789"},
+		{"0", "0"},
+		{"~0", "~0"},
+		{"+Inf", "+Inf"},
+		{"-Inf", "NaN"},
+		{"-4", "NaN"},
+		{"~-0.0001", "NaN"},
+		{"NaN", "NaN"},
+	}
+
+	for _, c := range cases {
+		d := RequireFromString(c.d)
+
+		if r := d.Sqrt(); r.String() != c.want {
+			t.Errorf(`%v.Sqrt() should be %s and not %v`, d, c.want, r)
+		}
+	}
+
+	// the square root of 2 is correct to 16 places, the float64 one is not
+	sqrt2 := New(2, 0).Sqrt()
+	if r := sqrt2.Round(16); !r.Equal(New(14142135623730950, -16)) {
+		t.Errorf(`(2).Sqrt().Round(16) should be 1.414213562373095 and not %v`, r)
+	}
+	if r := sqrt2.Mul(sqrt2).Round(15); !r.Equal(2) {
+		t.Errorf(`((2).Sqrt())² should be 2 to 15 places and not %v`, r)
+	}
+
+	// perfect squares have an exact square root
+	for i := int64(1); i <= 100000; i += 37 {
+		for _, exp := range []int32{-8, 0, 6} {
+			d := New(i, exp)
+			if r := d.Mul(d).Sqrt(); r != d.Mul(1) || !r.IsExact() {
+				t.Errorf(`(%v²).Sqrt() should be exactly %v and not %v`, d, d, r)
+			}
+		}
+	}
+}
+
 func TestTranscendantalFunctions(t *testing.T) {
 	sqrt2 := New(2, 0).Sqrt()
 	_sqrt2 := New(141421356237309514, -17) // FIXME: since exponent can only be between -16 and +15, mantissa will be truncated
@@ -1818,18 +3272,149 @@ func TestTextJSONMarshaling(t *testing.T) {
 		t.Errorf(`(%v).MarshalJSON() should be '123.456', buff = '%s'`, d, b)
 	}
 
-	for _, b := range []string{`456.123`, `"456.123"`} {
-		if err := d.UnmarshalText([]byte(b)); err != nil {
-			t.Errorf(`().UnmarshalText(%s) should be ok, error = %v`, b, err)
-		} else if d != New(456123, -3) {
-			t.Errorf(`().UnmarshalText(%s) should be '456.123', buff = '%s'`, b, d)
+	for _, b := range []string{`456.123`, `"456.123"`} {
+		if err := d.UnmarshalText([]byte(b)); err != nil {
+			t.Errorf(`().UnmarshalText(%s) should be ok, error = %v`, b, err)
+		} else if d != New(456123, -3) {
+			t.Errorf(`().UnmarshalText(%s) should be '456.123', buff = '%s'`, b, d)
+		}
+
+		if err := d.UnmarshalJSON([]byte(b)); err != nil {
+			t.Errorf(`().UnmarshalJSON(%s) should be ok, error = %v`, b, err)
+		} else if d != New(456123, -3) {
+			t.Errorf(`().UnmarshalJSON(%s) should be '456.123', buff = '%s'`, b, d)
+		}
+	}
+}
+
+func TestMarshalJSONAsString(t *testing.T) {
+	defer func() { MarshalJSONAsString = false }()
+	MarshalJSONAsString = true
+
+	cases := []struct {
+		d    Decimal
+		want string
+	}{
+		{New(123456, -3), `"123.456"`},
+		{New(-12345678901234567, -16), `"-1.2345678901234567"`},
+		{Zero, `"0"`},
+		{Null, `"0"`},
+	}
+
+	for _, c := range cases {
+		if b, err := c.d.MarshalJSON(); err != nil || string(b) != c.want {
+			t.Errorf(`(%v).MarshalJSON() should be %s and not %s (err = %v)`, c.d, c.want, b, err)
+		}
+
+		// the string output round-trips through UnmarshalJSON
+		var d Decimal
+		if b, err := json.Marshal(c.d); err != nil || string(b) != c.want {
+			t.Errorf(`json.Marshal(%v) should be %s and not %s (err = %v)`, c.d, c.want, b, err)
+		} else if err := json.Unmarshal(b, &d); err != nil || !d.Equal(c.d) {
+			t.Errorf(`json.Unmarshal(%s) should be %v and not %v (err = %v)`, b, c.d, d, err)
+		}
+	}
+
+	MarshalJSONAsString = false
+	if b, err := New(123456, -3).MarshalJSON(); err != nil || string(b) != `123.456` {
+		t.Errorf(`123.456.MarshalJSON() should be 123.456 and not %s (err = %v)`, b, err)
+	}
+}
+
+func TestMarshalJSONMagic(t *testing.T) {
+	defer func() { MarshalJSONAsString = false }()
+
+	cases := []struct {
+		d    Decimal
+		want string
+		back Decimal
+	}{
+		{PositiveInfinity, `"Infinity"`, PositiveInfinity},
+		{NegativeInfinity, `"-Infinity"`, NegativeInfinity},
+		{NaN, `"NaN"`, NaN},
+		{NearZero, `0`, Zero},
+		{NearPositiveZero, `0`, Zero},
+	}
+
+	for _, asString := range []bool{false, true} {
+		MarshalJSONAsString = asString
+
+		for _, c := range cases {
+			want := c.want
+			if asString && want == `0` {
+				want = `"0"`
+			}
+
+			b, err := json.Marshal([]Decimal{c.d})
+			if err != nil || string(b) != "["+want+"]" {
+				t.Errorf(`json.Marshal([%v]) should be [%s] and not %s (err = %v)`, c.d, want, b, err)
+			}
+
+			// the output is valid JSON which is read back by the strict decoder
+			var r []Decimal
+			if err := json.Unmarshal(b, &r); err != nil || len(r) != 1 || r[0] != c.back {
+				t.Errorf(`json.Unmarshal(%s) should be [%v] and not %v (err = %v)`, b, c.back, r, err)
+			}
+		}
+	}
+
+	// weights and lengths follow the same policy, no unit is appended to a magic value
+	if b, err := json.Marshal([]Weight{Weight(PositiveInfinity), Weight(NaN)}); err != nil || string(b) != `["Infinity","NaN"]` {
+		t.Errorf(`json.Marshal([+Inf NaN]) of weights should be ["Infinity","NaN"] and not %s (err = %v)`, b, err)
+	}
+	if b, err := json.Marshal([]Length{Length(NegativeInfinity), Length(NaN)}); err != nil || string(b) != `["-Infinity","NaN"]` {
+		t.Errorf(`json.Marshal([-Inf NaN]) of lengths should be ["-Infinity","NaN"] and not %s (err = %v)`, b, err)
+	}
+	var w []Weight
+	if err := json.Unmarshal([]byte(`["Infinity","-Infinity","NaN"]`), &w); err != nil || len(w) != 3 || !w[0].IsInfinite() || w[0].Sign() <= 0 || !w[1].IsInfinite() || w[1].Sign() >= 0 || !w[2].IsNaN() {
+		t.Errorf(`json.Unmarshal(["Infinity","-Infinity","NaN"]) of weights should be [+Inf -Inf NaN] and not %v (err = %v)`, w, err)
+	}
+}
+
+func TestUnmarshalJSONLenient(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"NaN", NaN},
+		{"Infinity", PositiveInfinity},
+		{"+Infinity", PositiveInfinity},
+		{"-Infinity", NegativeInfinity},
+		{" -Infinity\n", NegativeInfinity},
+		{"123.456", New(123456, -3)},
+		{`"123.456"`, New(123456, -3)},
+		{`"NaN"`, NaN},
+		{"null", Null},
+	}
+
+	for _, c := range cases {
+		d := Decimal(1)
+		if err := UnmarshalJSONLenient([]byte(c.s), &d); err != nil || d != c.want {
+			t.Errorf(`UnmarshalJSONLenient(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	for _, s := range []string{"Infinit", "-Infinityy", "Infinityx", "--Infinity"} {
+		d := Decimal(1)
+		if err := UnmarshalJSONLenient([]byte(s), &d); err == nil || d != 1 {
+			t.Errorf(`UnmarshalJSONLenient(%q) should return an error and not %v`, s, d)
 		}
+	}
 
-		if err := d.UnmarshalJSON([]byte(b)); err != nil {
-			t.Errorf(`().UnmarshalJSON(%s) should be ok, error = %v`, b, err)
-		} else if d != New(456123, -3) {
-			t.Errorf(`().UnmarshalJSON(%s) should be '456.123', buff = '%s'`, b, d)
+	// strict decoding of a JSON document still requires quotes for NaN and infinities
+	for _, s := range []string{"NaN", "Infinity", "-Infinity", "[1, NaN]"} {
+		var v []Decimal
+		var d Decimal
+		if err := json.Unmarshal([]byte(s), &d); err == nil {
+			t.Errorf(`json.Unmarshal(%q) should return an error and not %v`, s, d)
 		}
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			t.Errorf(`json.Unmarshal(%q) should return an error and not %v`, s, v)
+		}
+	}
+	var v []Decimal
+	if err := json.Unmarshal([]byte(`["NaN", "-Inf", 1.5]`), &v); err != nil || len(v) != 3 || !v[0].IsNaN() || v[1] != NegativeInfinity || v[2] != New(15, -1) {
+		t.Errorf(`json.Unmarshal(["NaN", "-Inf", 1.5]) should be [NaN -Inf 1.5] and not %v (err = %v)`, v, err)
 	}
 }
 
@@ -2039,6 +3624,30 @@ func TestMarshalBinary(t *testing.T) {
 	}
 }
 
+func TestAppendTextBinary(t *testing.T) {
+	for _, d := range []Decimal{Null, Zero, NearZero, NaN, PositiveInfinity, NegativeInfinity, 100, -320, New(101, -2), NewFromFloat(1.0 / 3.0)} {
+		text, _ := d.MarshalText()
+		if b, err := d.AppendText([]byte("x=")); err != nil || string(b) != "x="+string(text) {
+			t.Errorf(`(%v).AppendText("x=") should be %q and not %q (err = %v)`, d, "x="+string(text), b, err)
+		}
+
+		data, _ := d.MarshalBinary()
+		if b, err := d.AppendBinary([]byte{0xff}); err != nil || !bytes.Equal(b, append([]byte{0xff}, data...)) {
+			t.Errorf(`(%v).AppendBinary(0xff) should be 0xff followed by %v and not %v (err = %v)`, d, data, b, err)
+		}
+	}
+
+	// appending to a buffer with enough capacity does not allocate
+	d := New(-12345678901234567, -16)
+	buf := make([]byte, 0, 64)
+	if n := testing.AllocsPerRun(100, func() { buf, _ = d.AppendText(buf[:0]) }); n != 0 {
+		t.Errorf(`AppendText should not allocate and not %v times`, n)
+	}
+	if n := testing.AllocsPerRun(100, func() { buf, _ = d.AppendBinary(buf[:0]) }); n != 0 {
+		t.Errorf(`AppendBinary should not allocate and not %v times`, n)
+	}
+}
+
 func TestGobEncode(t *testing.T) {
 	d := NewFromInt(100)
 
@@ -2203,6 +3812,84 @@ func TestRoundCash(t *testing.T) {
 	}
 }
 
+func TestToCents(t *testing.T) {
+	cases := []struct {
+		d     Decimal
+		cents int64
+		err   error
+	}{
+		{New(12345, -3), 1234, nil},
+		{New(12355, -3), 1236, nil},
+		{New(-1999, -2), -1999, nil},
+		{New(-5, -3), 0, nil},
+		{7, 700, nil},
+		{Zero, 0, nil},
+		{New(1, 17), 0, ErrOutOfRange},
+		{PositiveInfinity, 0, ErrOutOfRange},
+		{NaN, 0, ErrOutOfRange},
+	}
+
+	for _, c := range cases {
+		if cents, err := c.d.ToCents(); err != c.err || err == nil && cents != c.cents {
+			t.Errorf(`%v.ToCents() should be %d, %v and not %d, %v`, c.d, c.cents, c.err, cents, err)
+		}
+	}
+
+	for _, c := range []int64{0, 1, -5, 1234, -1999, MaxInt / 100} {
+		if d := FromCents(c); d.String() != New(c, -2).String() {
+			t.Errorf(`FromCents(%d) should be %v and not %v`, c, New(c, -2), d)
+		} else if cents, err := d.ToCents(); err != nil || cents != c {
+			t.Errorf(`FromCents(%d).ToCents() should be %d and not %d, %v`, c, c, cents, err)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	cases := []struct {
+		d     Decimal
+		n     int
+		parts string
+	}{
+		{100, 3, "[33.34 33.33 33.33]"},
+		{New(1, -2), 3, "[0.01 0 0]"},
+		{New(-1, -2), 3, "[-0.01 0 0]"},
+		{-2, 7, "[-0.29 -0.29 -0.29 -0.29 -0.28 -0.28 -0.28]"},
+		{New(10005, -3), 4, "[2.502 2.501 2.501 2.501]"},
+		{New(5, -16), 3, "[0.0000000000000002 0.0000000000000002 0.0000000000000001]"},
+		{New(1050, -2), 1, "[10.5]"},
+		{Zero, 3, "[0 0 0]"},
+		{PositiveInfinity, 2, "[+Inf +Inf]"},
+		{NaN, 2, "[NaN NaN]"},
+	}
+
+	for _, c := range cases {
+		parts := c.d.Allocate(c.n)
+		if fmt.Sprint(parts) != c.parts {
+			t.Errorf(`%v.Allocate(%d) should be %s and not %v`, c.d, c.n, c.parts, parts)
+		}
+	}
+
+	if parts := Decimal(100).Allocate(0); parts != nil {
+		t.Errorf(`Allocate(0) should be nil and not %v`, parts)
+	}
+
+	// the parts sum exactly to d and differ by at most one minor unit
+	for _, c := range []struct{ d, unit Decimal }{
+		{1, New(1, -2)}, {100, New(1, -2)}, {New(1999, -2), New(1, -2)}, {New(-123456789, -2), New(1, -2)},
+		{New(7, -3), New(1, -3)}, {New(-10001, -4), New(1, -4)}, {New(98765432109, -2), New(1, -2)}, {New(1, 10), New(1, -2)},
+	} {
+		for n := 1; n <= 13; n++ {
+			parts := c.d.Allocate(n)
+			if sum := Sum(parts[0], parts[1:]...); sum != c.d {
+				t.Errorf(`the sum of %v.Allocate(%d) = %v should be %v and not %v`, c.d, n, parts, c.d, sum)
+			}
+			if spread := Max(parts[0], parts[1:]...).Sub(Min(parts[0], parts[1:]...)); spread.GreaterThan(c.unit) {
+				t.Errorf(`the parts of %v.Allocate(%d) = %v should differ by at most %v`, c.d, n, parts, c.unit)
+			}
+		}
+	}
+}
+
 func TestStringFixedCash(t *testing.T) {
 	if s := New(343, -2).StringFixedCash(5); s != "3.45" {
 		t.Errorf(`3.43.StringFixedCash(5) should be "3.45" and not %q`, s)
@@ -2273,135 +3960,577 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
-func TestNumDigits(t *testing.T) {
-	if n := New(0, 0).NumDigits(); n != 1 {
-		t.Errorf(`Zero.NumDigits() should be 1 and not %d`, n)
+func TestNumDigits(t *testing.T) {
+	if n := New(0, 0).NumDigits(); n != 1 {
+		t.Errorf(`Zero.NumDigits() should be 1 and not %d`, n)
+	}
+	if n := Decimal(Null).NumDigits(); n != 1 {
+		t.Errorf(`Null.NumDigits() should be 1 and not %d`, n)
+	}
+	if n := New(1, 0).NumDigits(); n != 1 {
+		t.Errorf(`1.NumDigits() should be 1 and not %d`, n)
+	}
+	if n := New(9, 0).NumDigits(); n != 1 {
+		t.Errorf(`9.NumDigits() should be 1 and not %d`, n)
+	}
+	if n := NewFromInt(10).NumDigits(); n != 2 {
+		t.Errorf(`10.NumDigits() should be 2 and not %d`, n)
+	}
+	if n := NewFromInt(100).NumDigits(); n != 3 {
+		t.Errorf(`100.NumDigits() should be 3 and not %d`, n)
+	}
+	if n := New(12345, -2).NumDigits(); n != 5 {
+		t.Errorf(`123.45.NumDigits() should be 5 and not %d`, n)
+	}
+	if n := New(-12345, -2).NumDigits(); n != 5 {
+		t.Errorf(`-123.45.NumDigits() should be 5 and not %d`, n)
+	}
+	if n := NewFromInt(99999999999999999).NumDigits(); n != 17 {
+		t.Errorf(`99999999999999999.NumDigits() should be 17 and not %d`, n)
+	}
+	if n := NaN.NumDigits(); n != 1 {
+		t.Errorf(`NaN.NumDigits() should be 1 and not %d`, n)
+	}
+	if n := PositiveInfinity.NumDigits(); n != 1 {
+		t.Errorf(`+Inf.NumDigits() should be 1 and not %d`, n)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	d := New(12345, -2)
+
+	if d.Copy() != d {
+		t.Errorf(`d.Copy() should be equal to d`)
+	}
+	if Decimal(Null).Copy() != Null {
+		t.Errorf(`Null.Copy() should be Null`)
+	}
+	if NaN.Copy() != NaN {
+		t.Errorf(`NaN.Copy() should be NaN`)
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	var d Decimal
+
+	cases := []struct {
+		in  interface{}
+		out Decimal
+	}{
+		{int64(42), 42},
+		{uint64(42), 42},
+		{float32(545), 545},
+		{float64(123456), 123456},
+		{"3.14", New(314, -2)},
+		{[]byte("2.71"), New(271, -2)},
+	}
+
+	for _, c := range cases {
+		if err := d.Scan(c.in); err != nil {
+			t.Errorf(`Scan(%v) should not error, got %v`, c.in, err)
+		} else if !d.Equal(c.out) {
+			t.Errorf(`Scan(%v) should be %v and not %v`, c.in, c.out, d)
+		}
+	}
+
+	// unsupported type → error
+	if err := d.Scan(struct{}{}); err == nil {
+		t.Errorf(`Scan(struct{}) should error`)
+	}
+
+	// invalid string → error
+	if err := d.Scan("not-a-number"); err == nil {
+		t.Errorf(`Scan("not-a-number") should error`)
+	}
+	if err := d.Scan([]byte("not-a-number")); err == nil {
+		t.Errorf(`Scan([]byte("not-a-number")) should error`)
+	}
+
+	// Value
+	d = New(123, -1)
+	if v, err := d.Value(); err != nil {
+		t.Errorf(`Value() should not error, got %v`, err)
+	} else if s, ok := v.(string); !ok || s != "12.3" {
+		t.Errorf(`Value() should be "12.3" and not %v`, v)
+	}
+}
+
+func TestNullDecimal(t *testing.T) {
+	var d NullDecimal
+
+	// a NULL column and a stored 0 are told apart
+	if err := d.Scan(nil); err != nil || d.Valid || d.Decimal != Null {
+		t.Errorf(`Scan(nil) should be an invalid NullDecimal and not %+v (err = %v)`, d, err)
+	}
+	if err := d.Scan(int64(0)); err != nil || !d.Valid || d.Decimal != Zero {
+		t.Errorf(`Scan(0) should be a valid Zero and not %+v (err = %v)`, d, err)
+	}
+	if err := d.Scan("3.14"); err != nil || !d.Valid || d.Decimal != New(314, -2) {
+		t.Errorf(`Scan("3.14") should be a valid 3.14 and not %+v (err = %v)`, d, err)
+	}
+	if err := d.Scan(struct{}{}); err == nil || d.Valid || d.Decimal != Null {
+		t.Errorf(`Scan(struct{}) should error and give an invalid NullDecimal and not %+v (err = %v)`, d, err)
+	}
+
+	// a value which cannot be read does not leave a valid NullDecimal behind
+	for _, in := range []interface{}{"not-a-number", []byte("1.2.3"), true} {
+		d = NewNullDecimal(New(314, -2))
+		if err := d.Scan(in); err == nil || d.Valid || d.Decimal != Null {
+			t.Errorf(`Scan(%#v) should error and give an invalid NullDecimal and not %+v (err = %v)`, in, d, err)
+		}
+	}
+
+	if v, err := (NullDecimal{}).Value(); err != nil || v != nil {
+		t.Errorf(`NullDecimal{}.Value() should be nil and not %#v (err = %v)`, v, err)
+	}
+	if v, err := NewNullDecimal(Zero).Value(); err != nil || v != "0" {
+		t.Errorf(`NewNullDecimal(0).Value() should be "0" and not %#v (err = %v)`, v, err)
+	}
+	if v, err := NewNullDecimal(New(123, -1)).Value(); err != nil || v != "12.3" {
+		t.Errorf(`NewNullDecimal(12.3).Value() should be "12.3" and not %#v (err = %v)`, v, err)
+	}
+
+	// JSON
+	if b, err := json.Marshal([]NullDecimal{{}, NewNullDecimal(Zero), NewNullDecimal(New(15, -1))}); err != nil || string(b) != `[null,0,1.5]` {
+		t.Errorf(`json.Marshal of NullDecimal should be [null,0,1.5] and not %s (err = %v)`, b, err)
+	}
+	var r []NullDecimal
+	if err := json.Unmarshal([]byte(`[null, 0, "1.5"]`), &r); err != nil || len(r) != 3 || r[0].Valid || !r[1].Valid || r[1].Decimal != Zero || !r[2].Valid || r[2].Decimal != New(15, -1) {
+		t.Errorf(`json.Unmarshal([null, 0, "1.5"]) should be [{0 false} {0 true} {1.5 true}] and not %+v (err = %v)`, r, err)
+	}
+}
+
+func TestScanValueMode(t *testing.T) {
+	defer func(mode ValueMode, exp int32) { SQLValueMode, SQLValueExp = mode, exp }(SQLValueMode, SQLValueExp)
+
+	SQLValueMode = ValueFloat64
+	if v, err := New(123, -1).Value(); err != nil || v != 12.3 {
+		t.Errorf(`12.3.Value() should be float64 12.3 and not %#v (err = %v)`, v, err)
+	}
+	var d Decimal
+	if err := d.Scan(12.3); err != nil || d != New(123, -1) {
+		t.Errorf(`Scan(12.3) should be 12.3 and not %v (err = %v)`, d, err)
+	}
+
+	SQLValueMode = ValueInteger
+	cases := []struct {
+		d    Decimal
+		exp  int32
+		want int64
+	}{
+		{New(12345, -2), -2, 12345},
+		{New(-12345, -2), -2, -12345},
+		{42, -2, 4200},
+		{New(1005, -3), -2, 101},
+		{New(12344, -3), -2, 1234},
+		{Zero, -2, 0},
+		{Null, -2, 0},
+		{New(12345, -2), 0, 123},
+		{New(12345, 0), 3, 12},
+		{New(15, -1), -3, 1500},
+	}
+
+	for _, c := range cases {
+		SQLValueExp = c.exp
+
+		v, err := c.d.Value()
+		if err != nil || v != c.want {
+			t.Errorf(`%v.Value() with SQLValueExp = %d should be int64 %d and not %#v (err = %v)`, c.d, c.exp, c.want, v, err)
+		}
+
+		// an integer sent by the driver is read back as a count of 10^SQLValueExp
+		want := New(c.want, c.exp)
+		for _, in := range []interface{}{c.want, uint64(c.want)} {
+			if c.want < 0 {
+				if _, ok := in.(uint64); ok {
+					continue
+				}
+			}
+			if err := d.Scan(in); err != nil || !d.Equal(want) {
+				t.Errorf(`Scan(%#v) with SQLValueExp = %d should be %v and not %v (err = %v)`, in, c.exp, want, d, err)
+			}
+		}
+
+		// while a string, bytes or float value is a plain decimal
+		want = New(c.want, 0)
+		for _, in := range []interface{}{float64(c.want), float32(c.want), strconv.FormatInt(c.want, 10), []byte(strconv.FormatInt(c.want, 10))} {
+			if err := d.Scan(in); err != nil || !d.Equal(want) {
+				t.Errorf(`Scan(%#v) with SQLValueExp = %d should be %v and not %v (err = %v)`, in, c.exp, want, d, err)
+			}
+		}
+	}
+
+	SQLValueExp = -2
+	for _, c := range []struct {
+		in   interface{}
+		want Decimal
+	}{
+		{"123.45", New(12345, -2)},
+		{[]byte("-0.5"), New(-5, -1)},
+		{12.3, New(123, -1)},
+		{float32(0.25), New(25, -2)},
+	} {
+		if err := d.Scan(c.in); err != nil || d != c.want {
+			t.Errorf(`Scan(%#v) in ValueInteger mode should be %v and not %v (err = %v)`, c.in, c.want, d, err)
+		}
+	}
+	for _, d := range []Decimal{NaN, PositiveInfinity, NegativeInfinity, New(1, 18), New(-1, 18)} {
+		if v, err := d.Value(); err != ErrOutOfRange {
+			t.Errorf(`%v.Value() should return ErrOutOfRange and not %#v (err = %v)`, d, v, err)
+		}
+	}
+	if err := d.Scan("not-a-number"); err == nil {
+		t.Errorf(`Scan("not-a-number") should error`)
+	}
+
+	SQLValueMode = ValueString
+	if v, err := New(123, -1).Value(); err != nil || v != "12.3" {
+		t.Errorf(`12.3.Value() should be "12.3" and not %#v (err = %v)`, v, err)
+	}
+}
+
+func TestBytesToFixedBank(t *testing.T) {
+	d := NewFromFloat(5.45)
+
+	if s := d.StringFixedBank(1); s != "5.4" {
+		t.Errorf(`5.45.StringFixedBank(1) should be "5.4" and not %q`, s)
+	}
+	if s := d.StringFixedBank(-1); s != "10" {
+		t.Errorf(`5.45.StringFixedBank(-1) should round-bank to "10" and not %q`, s)
+	}
+
+	if b := d.BytesToFixedBank(nil, 2); string(b) != "5.45" {
+		t.Errorf(`5.45.BytesToFixedBank(2) should be "5.45" and not %q`, string(b))
+	}
+
+	if s := New(5455, -3).StringFixedBank(2); s != "5.46" {
+		t.Errorf(`5.455.StringFixedBank(2) should be "5.46" and not %q`, s)
+	}
+	if s := NewFromFloat(5.455).StringFixedBank(2); s != "5.46" {
+		t.Errorf(`NewFromFloat(5.455).StringFixedBank(2) should be "5.46" and not %q`, s)
+	}
+	if s := New(5445, -3).StringFixedBank(2); s != "5.44" {
+		t.Errorf(`5.445.StringFixedBank(2) should be "5.44" and not %q`, s)
+	}
+	if s := New(-5445, -3).StringFixedBank(2); s != "-5.44" {
+		t.Errorf(`-5.445.StringFixedBank(2) should be "-5.44" and not %q`, s)
+	}
+	if s := New(545, 0).StringFixedBank(-1); s != "540" {
+		t.Errorf(`545.StringFixedBank(-1) should be "540" and not %q`, s)
+	}
+	if s := Decimal(Null).StringFixedBank(2); s != "0.00" {
+		t.Errorf(`Null.StringFixedBank(2) should be "0.00" and not %q`, s)
+	}
+
+	// negative places are formatted like StringFixed, without trailing decimal zeros
+	if b := New(545, 0).BytesToFixedBank(nil, -1); string(b) != "540" {
+		t.Errorf(`545.BytesToFixedBank(-1) should be "540" and not %q`, string(b))
+	}
+
+	// providing a pre-allocated slice
+	prefix := []byte("v=")
+	b := d.BytesToFixedBank(prefix, 1)
+	if string(b) != "v=5.4" {
+		t.Errorf(`prefix BytesToFixedBank should be "v=5.4" and not %q`, string(b))
+	}
+}
+
+func TestNewFromFormattedString(t *testing.T) {
+	r := regexp.MustCompile(`[$,€\s]`)
+
+	if d, err := NewFromFormattedString("$1,234.56", r); err != nil || d != New(123456, -2) {
+		t.Errorf(`NewFromFormattedString("$1,234.56") should be 1234.56 and not %v (err=%v)`, d, err)
+	}
+	if d, err := NewFromFormattedString("  9 876.54 €", r); err != nil || d != New(987654, -2) {
+		t.Errorf(`NewFromFormattedString("  9 876.54 €") should be 9876.54 and not %v (err=%v)`, d, err)
+	}
+	// invalid input still propagates the error
+	if _, err := NewFromFormattedString("$abc", r); err == nil {
+		t.Errorf(`NewFromFormattedString("$abc") should error`)
+	}
+}
+
+func TestNewFromStringLocale(t *testing.T) {
+	cases := []struct {
+		s                    string
+		decimalSep, groupSep rune
+		want                 string
+	}{
+		{"1.234,56", ',', '.', "1234.56"},
+		{"-1.234.567,8", ',', '.', "-1234567.8"},
+		{"1 234,56", ',', ' ', "1234.56"},
+		{"1\u00a0234,56", ',', '\u00a0', "1234.56"},
+		{"1\u00a0234,56", ',', ' ', "1234.56"},
+		{"1\u202f234.56", '.', ' ', "1234.56"},
+		{"-1\u2009234\u202f567,8", ',', '\u00a0', "-1234567.8"},
+		{"1 234\u00a0567", ',', '\u202f', "1234567"},
+		{"1,234.56", '.', ',', "1234.56"},
+		{"1'234'567", '.', '\'', "1234567"},
+		{"1234,56", ',', '.', "1234.56"},
+		{",5", ',', '.', "0.5"},
+		{"~-12,5", ',', '.', "~-12.5"},
+		{"1.234,5e3", ',', '.', "1234500"},
+		{"123", ',', '.', "123"},
+		{"+Inf", ',', '.', "+Inf"},
+	}
+
+	for _, c := range cases {
+		if d, err := NewFromStringLocale(c.s, c.decimalSep, c.groupSep); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromStringLocale(%q, %q, %q) should be %s and not %v (err = %v)`, c.s, c.decimalSep, c.groupSep, c.want, d, err)
+		}
+	}
+
+	errs := []struct {
+		s                    string
+		decimalSep, groupSep rune
+	}{
+		{"1.234,56", ',', ','},
+		{"1.234,56", '.', ','},
+		{"1,234.56", ',', '.'},
+		{"1.5", ',', ' '},
+		{"12.34,5", ',', '.'},
+		{"1234.567,8", ',', '.'},
+		{"1.2345,6", ',', '.'},
+		{".123,4", ',', '.'},
+		{"-.123", ',', '.'},
+		{"1.,5", ',', '.'},
+		{"1.", ',', '.'},
+		{"1,234,5", ',', '.'},
+		{"1,5.000", ',', '.'},
+		{"1..234", ',', '.'},
+		{"1\u00a023,56", ',', ' '},
+		{"1 234,5", ',', '.'},
+		{"1_234,5", ',', '.'},
+		{"1 234.5", '.', ','},
+		{"1\u202f234,5", ',', '\''},
+		{"12_34", ',', ' '},
+	}
+
+	for _, c := range errs {
+		if d, err := NewFromStringLocale(c.s, c.decimalSep, c.groupSep); err != ErrSyntax {
+			t.Errorf(`NewFromStringLocale(%q, %q, %q) should return ErrSyntax and not %v (err = %v)`, c.s, c.decimalSep, c.groupSep, d, err)
+		}
 	}
-	if n := Decimal(Null).NumDigits(); n != 1 {
-		t.Errorf(`Null.NumDigits() should be 1 and not %d`, n)
+
+	// unicode spaces are only group separators when grouping with spaces, and never for the default strict parser
+	if d, err := NewFromStringLocale("1\u00a0234,56", ',', '.'); err == nil {
+		t.Errorf(`NewFromStringLocale("1\u00a0234,56", ',', '.') should return an error and not %v`, d)
 	}
-	if n := New(1, 0).NumDigits(); n != 1 {
-		t.Errorf(`1.NumDigits() should be 1 and not %d`, n)
+	if d, err := NewFromStringLocale("1\u2028234", ',', ' '); err == nil {
+		t.Errorf(`NewFromStringLocale("1\u2028234", ',', ' ') should return an error and not %v`, d)
 	}
-	if n := New(9, 0).NumDigits(); n != 1 {
-		t.Errorf(`9.NumDigits() should be 1 and not %d`, n)
+	for _, s := range []string{"1\u00a0234.56", "1\u202f234.56", "1\u2009234"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
 	}
-	if n := NewFromInt(10).NumDigits(); n != 2 {
-		t.Errorf(`10.NumDigits() should be 2 and not %d`, n)
+}
+
+func TestQFormat(t *testing.T) {
+	cases := []struct {
+		d     string
+		bits  uint
+		q     int64
+		exact bool
+	}{
+		{"0.5", 15, 16384, true},
+		{"-1", 15, -32768, true},
+		{"0.999969482421875", 15, 32767, true},
+		{"0.25", 15, 8192, true},
+		{"-0.25", 31, -536870912, true},
+		{"0.1", 15, 3277, false},
+		{"-0.1", 15, -3277, false},
+		{"0.1", 31, 214748365, false},
+		{"0.000030517578125", 15, 1, true},
+		{"0.0000152587890625", 15, 1, false}, // half a LSB is rounded away from zero
+		{"-0.0000152587890625", 15, -1, false},
+		{"0.0000152587890624", 15, 0, false},
+		{"0", 31, 0, true},
+		{"~0", 31, 0, true},
+		{"123.456", 0, 123, false},
+		{"1", 62, 1 << 62, true},
+		{"-1", 63, math.MinInt64, true},
 	}
-	if n := NewFromInt(100).NumDigits(); n != 3 {
-		t.Errorf(`100.NumDigits() should be 3 and not %d`, n)
+
+	for _, c := range cases {
+		d := RequireFromString(c.d)
+
+		q, err := d.ToQ(c.bits)
+		if err != nil || q != c.q {
+			t.Errorf(`%v.ToQ(%d) should be %d and not %d (err = %v)`, d, c.bits, c.q, q, err)
+		}
+
+		// the round trip is exact when d is a multiple of 2^-bits
+		if r := FromQ(q, c.bits); c.exact && !r.Equal(d) {
+			t.Errorf(`FromQ(%d, %d) should be %v and not %v`, q, c.bits, d, r)
+		}
 	}
-	if n := New(12345, -2).NumDigits(); n != 5 {
-		t.Errorf(`123.45.NumDigits() should be 5 and not %d`, n)
+
+	errs := []struct {
+		d    string
+		bits uint
+	}{
+		{"1", 63},
+		{"-1.0000001", 63},
+		{"4294967296", 31},
+		{"1e15", 31},
+		{"+Inf", 15},
+		{"NaN", 15},
+		{"1", 64},
 	}
-	if n := New(-12345, -2).NumDigits(); n != 5 {
-		t.Errorf(`-123.45.NumDigits() should be 5 and not %d`, n)
+
+	for _, c := range errs {
+		d := RequireFromString(c.d)
+
+		if q, err := d.ToQ(c.bits); err != ErrOutOfRange {
+			t.Errorf(`%v.ToQ(%d) should return ErrOutOfRange and not %d (err = %v)`, d, c.bits, q, err)
+		}
 	}
-	if n := NewFromInt(99999999999999999).NumDigits(); n != 17 {
-		t.Errorf(`99999999999999999.NumDigits() should be 17 and not %d`, n)
+
+	if d := FromQ(1, 31); !d.Equal(RequireFromString("0.0000000004656612873077392578125")) {
+		t.Errorf(`FromQ(1, 31) should be about 4.656612873077393e-10 and not %v`, d)
 	}
-	if n := NaN.NumDigits(); n != 1 {
-		t.Errorf(`NaN.NumDigits() should be 1 and not %d`, n)
+	if q, _ := FromQ(1, 31).ToQ(31); q != 1 {
+		t.Errorf(`FromQ(1, 31).ToQ(31) should be 1 and not %d`, q)
 	}
-	if n := PositiveInfinity.NumDigits(); n != 1 {
-		t.Errorf(`+Inf.NumDigits() should be 1 and not %d`, n)
+	if q, _ := FromQ(-1234567890, 31).ToQ(31); q != -1234567890 {
+		t.Errorf(`FromQ(-1234567890, 31).ToQ(31) should be -1234567890 and not %d`, q)
 	}
 }
 
-func TestCopy(t *testing.T) {
-	d := New(12345, -2)
-
-	if d.Copy() != d {
-		t.Errorf(`d.Copy() should be equal to d`)
+func TestNewFromScientificUnicode(t *testing.T) {
+	cases := []struct {
+		s, want string
+	}{
+		{"1.23×10³", "1230"},
+		{"4.5×10⁻²", "0.045"},
+		{"4.5 × 10⁻²", "0.045"},
+		{"-6.02214076×10²³", "-602214076000000000000000"},
+		{"6.62607015×10⁻⁷", "0.000000662607015"},
+		{"1×10⁰", "1"},
+		{"2×10¹", "20"},
+		{"7×10⁺⁵", "700000"},
+		{"9.81×10⁴⁶⁷⁸⁹", "+Inf"},
+		{"123.45", "123.45"},
 	}
-	if Decimal(Null).Copy() != Null {
-		t.Errorf(`Null.Copy() should be Null`)
+
+	for _, c := range cases {
+		if d, err := NewFromScientificUnicode(c.s); err != nil || d.String() != c.want {
+			t.Errorf(`NewFromScientificUnicode(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
 	}
-	if NaN.Copy() != NaN {
-		t.Errorf(`NaN.Copy() should be NaN`)
+
+	for _, s := range []string{"1.23×10", "1.23×10^3", "1.23×103", "1.23×10³x", "1.23×10⁻", "1.23×10³⁻", "1.23×2³", "×10³", "1.23×10⁻⁻²"} {
+		if d, err := NewFromScientificUnicode(s); err != ErrSyntax {
+			t.Errorf(`NewFromScientificUnicode(%q) should return ErrSyntax and not %v (err = %v)`, s, d, err)
+		}
 	}
 }
 
-func TestScanValue(t *testing.T) {
-	var d Decimal
+func TestDecimalSeparator(t *testing.T) {
+	DecimalSeparator = ','
+	defer func() { DecimalSeparator = '.' }()
 
 	cases := []struct {
-		in  interface{}
-		out Decimal
+		s, want string
 	}{
-		{int64(42), 42},
-		{uint64(42), 42},
-		{float32(545), 545},
-		{float64(123456), 123456},
-		{"3.14", New(314, -2)},
-		{[]byte("2.71"), New(271, -2)},
+		{"1,5", "1,5"},
+		{"-12,345", "-12,345"},
+		{",5", "0,5"},
+		{"1 234,5", "1234,5"},
+		{"1,5e3", "1500"},
+		{"~0,1", "~0,1"},
+		{"1.5", "1,5"},
+		{"12,5%", "0,125"},
+		{"42", "42"},
 	}
 
 	for _, c := range cases {
-		if err := d.Scan(c.in); err != nil {
-			t.Errorf(`Scan(%v) should not error, got %v`, c.in, err)
-		} else if !d.Equal(c.out) {
-			t.Errorf(`Scan(%v) should be %v and not %v`, c.in, c.out, d)
+		d, err := NewFromString(c.s)
+		if err != nil || d.String() != c.want {
+			t.Errorf(`NewFromString(%q) should be %s and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+		if s := fmt.Sprintf("%v", d); s != c.want {
+			t.Errorf(`fmt.Sprintf("%%v", %v) should be %s and not %s`, d, c.want, s)
 		}
 	}
 
-	// unsupported type → error
-	if err := d.Scan(struct{}{}); err == nil {
-		t.Errorf(`Scan(struct{}) should error`)
+	for _, s := range []string{"1,5,0", "1,5.0", ",", "1,,5"} {
+		if d, err := NewFromString(s); err == nil {
+			t.Errorf(`NewFromString(%q) should return an error and not %v`, s, d)
+		}
 	}
 
-	// invalid string → error
-	if err := d.Scan("not-a-number"); err == nil {
-		t.Errorf(`Scan("not-a-number") should error`)
+	// machine readable outputs keep the dot
+	d := New(15, -1)
+	if b, _ := d.MarshalJSON(); string(b) != "1.5" {
+		t.Errorf(`%v.MarshalJSON() should be 1.5 and not %s`, d, b)
 	}
-	if err := d.Scan([]byte("not-a-number")); err == nil {
-		t.Errorf(`Scan([]byte("not-a-number")) should error`)
+	if b, _ := d.MarshalText(); string(b) != "1.5" {
+		t.Errorf(`%v.MarshalText() should be 1.5 and not %s`, d, b)
+	}
+	if s := d.StringFixed(2); s != "1.50" {
+		t.Errorf(`%v.StringFixed(2) should be 1.50 and not %s`, d, s)
+	}
+	if v, err := New(-12345, -3).Value(); err != nil || v != "-12.345" {
+		t.Errorf(`New(-12345, -3).Value() should be -12.345 and not %v (err = %v)`, v, err)
+	}
+	if y, err := New(-12345, -3).MarshalYAML(); err != nil || y != "-12.345" {
+		t.Errorf(`New(-12345, -3).MarshalYAML() should be -12.345 and not %v (err = %v)`, y, err)
+	}
+	if s, err := Eval("1.5*2.5"); err != nil || s != "3.75" {
+		t.Errorf(`Eval("1.5*2.5") should be 3.75 and not %s (err = %v)`, s, err)
+	}
+	if w, err := NewWeightFromString("1,5kg"); err != nil || w.String() != "1.5kg" {
+		t.Errorf(`NewWeightFromString("1,5kg") should be 1.5kg and not %v (err = %v)`, w, err)
 	}
 
-	// Value
-	d = New(123, -1)
-	if v, err := d.Value(); err != nil {
-		t.Errorf(`Value() should not error, got %v`, err)
-	} else if s, ok := v.(string); !ok || s != "12.3" {
-		t.Errorf(`Value() should be "12.3" and not %v`, v)
+	// the default behavior is restored with '.'
+	DecimalSeparator = '.'
+	if d, err := NewFromString("1,5"); err == nil {
+		t.Errorf(`NewFromString("1,5") should return an error with the default separator and not %v`, d)
+	}
+	if s := d.String(); s != "1.5" {
+		t.Errorf(`%v.String() should be 1.5 with the default separator and not %s`, d, s)
 	}
 }
 
-func TestBytesToFixedBank(t *testing.T) {
-	d := NewFromFloat(5.45)
+func TestAppendString(t *testing.T) {
+	cases := []string{"0", "-12.345", "~0.3333333333333333", "100020003000400050", "1e-16", "Inf", "-Inf", "NaN"}
 
-	if s := d.StringFixedBank(1); s != "5.4" {
-		t.Errorf(`5.45.StringFixedBank(1) should be "5.4" and not %q`, s)
-	}
-	if s := d.StringFixedBank(-1); s != "10" {
-		t.Errorf(`5.45.StringFixedBank(-1) should round-bank to "10" and not %q`, s)
-	}
+	for _, str := range cases {
+		d, _ := NewFromString(str)
 
-	if b := d.BytesToFixedBank(nil, 2); string(b) != "5.45" {
-		t.Errorf(`5.45.BytesToFixedBank(2) should be "5.45" and not %q`, string(b))
+		if b := d.AppendString([]byte("x=")); string(b) != "x="+d.String() {
+			t.Errorf(`%v.AppendString("x=") should be x=%s and not %s`, d, d.String(), b)
+		}
 	}
 
-	// providing a pre-allocated slice
-	prefix := []byte("v=")
-	b := d.BytesToFixedBank(prefix, 1)
-	if string(b) != "v=5.4" {
-		t.Errorf(`prefix BytesToFixedBank should be "v=5.4" and not %q`, string(b))
+	if b := Decimal(Null).AppendString(nil); string(b) != "0" {
+		t.Errorf(`Null.AppendString(nil) should be 0 and not %s`, b)
 	}
-}
 
-func TestNewFromFormattedString(t *testing.T) {
-	r := regexp.MustCompile(`[$,€\s]`)
+	// only the appended decimal point is replaced by DecimalSeparator, even by a multi-byte one
+	DecimalSeparator = '٫'
+	defer func() { DecimalSeparator = '.' }()
 
-	if d, err := NewFromFormattedString("$1,234.56", r); err != nil || d != New(123456, -2) {
-		t.Errorf(`NewFromFormattedString("$1,234.56") should be 1234.56 and not %v (err=%v)`, d, err)
+	if b := New(-12345, -3).AppendString([]byte("1.5 ")); string(b) != "1.5 -12٫345" {
+		t.Errorf(`New(-12345, -3).AppendString("1.5 ") should be 1.5 -12٫345 and not %s`, b)
 	}
-	if d, err := NewFromFormattedString("  9 876.54 €", r); err != nil || d != New(987654, -2) {
-		t.Errorf(`NewFromFormattedString("  9 876.54 €") should be 9876.54 and not %v (err=%v)`, d, err)
+	DecimalSeparator = ','
+	if b := New(15, -1).AppendString([]byte("1.5 ")); string(b) != "1.5 1,5" {
+		t.Errorf(`New(15, -1).AppendString("1.5 ") should be 1.5 1,5 and not %s`, b)
 	}
-	// invalid input still propagates the error
-	if _, err := NewFromFormattedString("$abc", r); err == nil {
-		t.Errorf(`NewFromFormattedString("$abc") should error`)
+	DecimalSeparator = '.'
+
+	// a reused buffer is never reallocated
+	d := New(-12345, -3)
+	buf := make([]byte, 0, 32)
+	if n := testing.AllocsPerRun(100, func() { buf = d.AppendString(buf[:0]) }); n != 0 {
+		t.Errorf(`%v.AppendString with a reused buffer should not allocate and not %v times`, d, n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = d.String() }); n > 1 {
+		t.Errorf(`%v.String() should allocate at most once and not %v times`, d, n)
 	}
 }
 
@@ -2484,7 +4613,7 @@ func TestBinaryV2DecimalExtensionRoundTrip(t *testing.T) {
 }
 
 func TestBinaryV2WeightRoundTrip(t *testing.T) {
-	cases := []string{"0kg", "5kg", "-5kg", "5g", "0.5lb", "12oz", "1mcg"}
+	cases := []string{"0kg", "5kg", "-5kg", "5g", "0.5lb", "12oz", "1mcg", "0g", "0lb", "0 oz t"}
 	for _, s := range cases {
 		w, err := NewWeightFromString(s)
 		if err != nil {
@@ -2514,7 +4643,7 @@ func TestBinaryV2WeightRoundTrip(t *testing.T) {
 }
 
 func TestBinaryV2LengthRoundTrip(t *testing.T) {
-	cases := []string{"0m", "5m", "-5m", "5km", "100cm", "1ft", "1mi", "1au"}
+	cases := []string{"0m", "5m", "-5m", "5km", "100cm", "1ft", "1mi", "1au", "0cm", "0ft"}
 	for _, s := range cases {
 		l, err := NewLengthFromString(s)
 		if err != nil {
@@ -2534,6 +4663,76 @@ func TestBinaryV2LengthRoundTrip(t *testing.T) {
 	}
 }
 
+func TestBinaryV2ZeroWithUnit(t *testing.T) {
+	// only the unit bits tell a zero weight of a unit from another one, the mantissa being zero
+	cases := []struct {
+		s    string
+		want []byte
+	}{
+		{"0g", []byte{0x08, 0x05, 0x00, 0x00}},
+		{"0lb", []byte{0x08, 0x0c, 0x00, 0x00}},
+		{"0kg", []byte{0x80}},
+		{"-0mg", []byte{0x08, 0x06, 0x00, 0x00}},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.s)
+
+		b, err := w.MarshalBinary()
+		if err != nil || !bytes.Equal(b, c.want) {
+			t.Errorf(`%v.MarshalBinary() should be % x and not % x (err = %v)`, w, c.want, b, err)
+		}
+
+		var w2 Weight
+		if err := w2.UnmarshalBinary(b); err != nil || w2 != w || w2.Unit() != w.Unit() || !w2.IsZero() {
+			t.Errorf(`UnmarshalBinary(% x) should be %v and not %v (err = %v)`, b, w, w2, err)
+		}
+	}
+
+	l, _ := NewLengthFromString("0ft")
+	b, _ := l.MarshalBinary()
+
+	var l2 Length
+	if err := l2.UnmarshalBinary(b); err != nil || l2.String() != "0ft" {
+		t.Errorf(`UnmarshalBinary(% x) should be 0ft and not %v (err = %v)`, b, l2, err)
+	}
+
+	// a magic value still has no unit
+	nan, _ := NewWeightFromString("NaN")
+	if b, _ := nan.MarshalBinary(); len(b) != 1 {
+		t.Errorf(`NaN.MarshalBinary() should be a single byte and not % x`, b)
+	}
+}
+
+func TestBinaryV2WeightVectors(t *testing.T) {
+	// the test vectors of BINARY_FORMAT.md
+	cases := []struct {
+		s    string
+		want []byte
+	}{
+		{"5kg", []byte{0x01, 0x05}},
+		{"5g", []byte{0x08, 0x05, 0x00, 0x05}},
+		{"-3g", []byte{0x88, 0x05, 0x00, 0x03}},
+		{"11lb", []byte{0x08, 0x0c, 0x00, 0x0b}},
+		{"11st", []byte{0x08, 0x0a, 0x00, 0x0b}},
+		{"1cwt", []byte{0x08, 0x0b, 0x00, 0x01}},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.s)
+
+		b, err := w.MarshalBinary()
+		if err != nil || !bytes.Equal(b, c.want) {
+			t.Errorf(`%v.MarshalBinary() should be % x and not % x (err = %v)`, w, c.want, b, err)
+		}
+
+		var w2 Weight
+		if err := w2.UnmarshalBinary(c.want); err != nil || w2 != w || w2.String() != c.s {
+			t.Errorf(`UnmarshalBinary(% x) should be %s and not %v (err = %v)`, c.want, c.s, w2, err)
+		}
+	}
+}
+
 func TestBinaryV2CrossRefusal(t *testing.T) {
 	// Weight should refuse a Length v2 stream and vice versa
 	l1ft, _ := NewLengthFromString("1ft")
@@ -2766,9 +4965,9 @@ func TestBinaryV2WeightUnmarshalErrors(t *testing.T) {
 	if err := w.UnmarshalBinary([]byte{0x0c, 0x01, 0x01, 0x01}); err == nil {
 		t.Errorf(`Weight should refuse Length v2 ext`)
 	}
-	// reserved unit (index 10 in weightUnits is empty/reserved)
-	if err := w.UnmarshalBinary([]byte{0x08, 10, 0x00, 0x01}); err == nil {
-		t.Errorf(`Weight with reserved unit code 10 should error`)
+	// unit code out of the 4 bits of the unit field (16 is the first alias in weightUnits)
+	if err := w.UnmarshalBinary([]byte{0x08, 16, 0x00, 0x01}); err == nil {
+		t.Errorf(`Weight with unit code 16 should error`)
 	}
 }
 
@@ -2923,6 +5122,38 @@ func TestNewFromIntEdges(t *testing.T) {
 	}
 }
 
+func TestNewFromUint(t *testing.T) {
+	if d := NewFromUint(0); d != Zero {
+		t.Errorf(`NewFromUint(0) should be Zero and not %v`, d)
+	}
+	if d := NewFromUint(12345); d != 12345 {
+		t.Errorf(`NewFromUint(12345) should be 12345 and not %v`, d)
+	}
+	if d := NewFromUint(MaxInt); d != MaxInt || !d.IsExact() {
+		t.Errorf(`NewFromUint(MaxInt) should be exactly MaxInt and not %v`, d)
+	}
+	if ^uint(0) == math.MaxUint64 {
+		max := uint64(math.MaxUint64)
+		if d := NewFromUint(uint(max)); d.String() != "~18446744073709552000" {
+			t.Errorf(`NewFromUint(math.MaxUint64) should be ~18446744073709552000 and not %v`, d)
+		}
+		// 2^63 would be corrupted by a cast through int64
+		if d := NewFromUint(uint(max>>1) + 1); d.IsNegative() || d.String() != "~9223372036854775800" {
+			t.Errorf(`NewFromUint(2^63) should be ~9223372036854775800 and not %v`, d)
+		}
+		if d := NewFromUint(uint(1e18)); d != New(1, 18) || !d.IsExact() {
+			t.Errorf(`NewFromUint(1e18) should be exactly 1e18 and not %v`, d)
+		}
+	}
+
+	if d := NewFromUint32(0); d != Zero {
+		t.Errorf(`NewFromUint32(0) should be Zero and not %v`, d)
+	}
+	if d := NewFromUint32(math.MaxUint32); d != 4294967295 {
+		t.Errorf(`NewFromUint32(math.MaxUint32) should be 4294967295 and not %v`, d)
+	}
+}
+
 func TestNewFromFloatExactValues(t *testing.T) {
 	// regression test: powers of 2 (1, 2, 4, 8, …) used to collapse to ~0 because the old
 	// fixFloatMantissa zeroed out the mantissa when the high 32 bits were empty.
@@ -3113,6 +5344,203 @@ func TestStringFixedIntegerWithDot(t *testing.T) {
 	if s := New(1, 3).StringFixed(2); s != "1000.00" {
 		t.Errorf(`New(1,3).StringFixed(2) should be "1000.00" and not %q`, s)
 	}
+	// positive exponent kept after normalization: 1e20 is mantissa=100000, exp=15
+	if s := New(1, 20).StringFixed(2); s != "100000000000000000000.00" {
+		t.Errorf(`New(1,20).StringFixed(2) should be "100000000000000000000.00" and not %q`, s)
+	}
+}
+
+func TestGoString(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		want string
+	}{
+		{New(101, -2), "decimal.Decimal(0x3c00000000000065=1.01, m=101, e=-2, loss=false)"},
+		{New(-101, -2), "decimal.Decimal(0xc3ffffffffffff9b=-1.01, m=-101, e=-2, loss=false)"},
+		{1234, "decimal.Decimal(0x4d2=1234, m=1234, e=0, loss=false)"},
+		{Null, "decimal.Decimal(0x0=0, m=0, e=0, loss=false)"},
+		{Zero, "decimal.Decimal(0x8000000000000000=0, m=0, e=0, loss=false)"},
+		{Decimal(1).Div(3), "decimal.Decimal(0x600bd7a625405555=~0.3333333333333333, m=3333333333333333, e=-16, loss=true)"},
+		{NaN, "decimal.Decimal(0x4200000000000000=NaN, m=0, e=1, loss=true)"},
+		{NegativeInfinity, "decimal.Decimal(0xa200000000000000=-Inf, m=0, e=15, loss=true)"},
+	}
+
+	for _, c := range cases {
+		if s := c.d.GoString(); s != c.want {
+			t.Errorf(`%v.GoString() should be %q and not %q`, c.d, c.want, s)
+		}
+		if s := fmt.Sprintf("%#v", c.d); s != c.want {
+			t.Errorf(`fmt.Sprintf("%%#v", %v) should be %q and not %q`, c.d, c.want, s)
+		}
+	}
+}
+
+func TestStringExp(t *testing.T) {
+	cases := []struct {
+		d      Decimal
+		places int32
+		want   string
+	}{
+		{New(123456, 0), 4, "1.2346e+05"},
+		{New(123456, 0), 0, "1e+05"},
+		{New(123456, 0), -1, "1.23456e+05"},
+		{New(123456, 0), 8, "1.23456000e+05"},
+		{New(-15, -10), -1, "-1.5e-09"},
+		{New(-15, -10), 3, "-1.500e-09"},
+		{New(99999, -4), 2, "1.00e+01"},
+		{New(-125, -2), 1, "-1.2e+00"},
+		{New(125, -2), 1, "1.3e+00"},
+		{New(1, -16), -1, "1e-16"},
+		{New(MaxInt, 15), 3, "1.441e+32"},
+		{Decimal(2).Div(3), 2, "~6.67e-01"},
+		{Decimal(-2).Div(3), -1, "~-6.666666666666667e-01"},
+		{Null, 2, "0.00e+00"},
+		{Zero, -1, "0e+00"},
+		{NearZero, 1, "~0.0e+00"},
+		{NearNegativeZero, 1, "-~0.0e+00"},
+		{NaN, 2, "NaN"},
+		{PositiveInfinity, 2, "+Inf"},
+		{NegativeInfinity, 2, "-Inf"},
+	}
+
+	for _, c := range cases {
+		if s := c.d.StringExp(c.places); s != c.want {
+			t.Errorf(`%v.StringExp(%d) should be %q and not %q`, c.d, c.places, c.want, s)
+		}
+	}
+
+	// scientific notation can be parsed back
+	for _, d := range []Decimal{New(123456, 0), New(-15, -10), New(1, -16), New(MaxInt, 15), New(-987654321, -3)} {
+		if r, err := NewFromString(d.StringExp(-1)); err != nil || r != d {
+			t.Errorf(`NewFromString(%v.StringExp(-1)) should be %v and not %v (err = %v)`, d, d, r, err)
+		}
+	}
+}
+
+func TestStringGrouped(t *testing.T) {
+	cases := []struct {
+		d                 Decimal
+		sep, decimalPoint rune
+		places            int32
+		want              string
+	}{
+		{New(123456789, -2), ',', '.', 2, "1,234,567.89"},
+		{New(-123456789, -2), ',', '.', 2, "-1,234,567.89"},
+		{New(-123456789, -2), '.', ',', 1, "-1.234.567,9"},
+		{New(123456789, -2), ' ', ',', 0, "1 234 568"},
+		{New(123456789, -2), 0, '.', 3, "1234567.890"},
+		{New(123456789, -2), '\u202f', '.', 2, "1\u202f234\u202f567.89"},
+		{New(123456, 0), ',', '.', 0, "123,456"},
+		{New(-12345, -2), ',', '.', 2, "-123.45"},
+		{New(1234, 0), '\'', '.', -2, "1'200"},
+		{New(1, 20), ',', '.', 2, "100,000,000,000,000,000,000.00"},
+		{Decimal(1).Div(3), ',', '.', 4, "0.3333"},
+		{Null, ',', '.', 2, "0.00"},
+		{NaN, ',', '.', 2, "NaN"},
+		{PositiveInfinity, ',', '.', 2, "+Inf"},
+		{NegativeInfinity, ',', '.', 2, "-Inf"},
+	}
+
+	for _, c := range cases {
+		if s := c.d.StringGrouped(c.sep, c.decimalPoint, c.places); s != c.want {
+			t.Errorf(`%v.StringGrouped(%q, %q, %d) should be %q and not %q`, c.d, c.sep, c.decimalPoint, c.places, c.want, s)
+		}
+	}
+}
+
+func TestFmtScanner(t *testing.T) {
+	var d, d2 Decimal
+
+	if n, err := fmt.Sscanf("price=12.34", "price=%v", FmtScanner(&d)); n != 1 || err != nil || d != New(1234, -2) {
+		t.Errorf(`fmt.Sscanf("price=12.34", "price=%%v") should be 12.34 and not %v (n = %d, err = %v)`, d, n, err)
+	}
+	if n, err := fmt.Sscanf("-1.5e3 ~0.25", "%f %g", FmtScanner(&d), FmtScanner(&d2)); n != 2 || err != nil || d != -1500 || d2.String() != "~0.25" {
+		t.Errorf(`fmt.Sscanf("-1.5e3 ~0.25", "%%f %%g") should be -1500 and ~0.25 and not %v and %v (n = %d, err = %v)`, d, d2, n, err)
+	}
+	if n, err := fmt.Sscan("  42\n  NaN", FmtScanner(&d), FmtScanner(&d2)); n != 2 || err != nil || d != 42 || !d2.IsNaN() {
+		t.Errorf(`fmt.Sscan("  42\n  NaN") should be 42 and NaN and not %v and %v (n = %d, err = %v)`, d, d2, n, err)
+	}
+
+	d = 7
+	if _, err := fmt.Sscanf("price=abc", "price=%v", FmtScanner(&d)); err == nil || d != 7 {
+		t.Errorf(`fmt.Sscanf("price=abc") should return an error and not %v`, d)
+	}
+	if _, err := fmt.Sscanf("12", "%x", FmtScanner(&d)); err != ErrFormat || d != 7 {
+		t.Errorf(`fmt.Sscanf("12", "%%x") should return ErrFormat and not %v (err = %v)`, d, err)
+	}
+	if _, err := fmt.Sscanf("", "%v", FmtScanner(&d)); err == nil || d != 7 {
+		t.Errorf(`fmt.Sscanf("", "%%v") should return an error and not %v`, d)
+	}
+
+	// the sql.Scanner Scan method is still there
+	if err := d.Scan("3.14"); err != nil || d != New(314, -2) {
+		t.Errorf(`Scan("3.14") should be 3.14 and not %v (err = %v)`, d, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		format string
+		d      Decimal
+		want   string
+	}{
+		{"%v", New(-12345, -3), "-12.345"},
+		{"%s", Decimal(1).Div(3), "~0.3333333333333333"},
+		{"%q", New(101, -2), `"1.01"`},
+		{"%6v|%-6v|", New(101, -2), "  1.01|1.01  |"},
+		{"%v", Null, "0"},
+		{"%f", New(12345, -3), "12.345000"},
+		{"%.2f", New(12345, -3), "12.35"},
+		{"%8.2f", New(12345, -3), "   12.35"},
+		{"%-8.2f|", New(12345, -3), "12.35   |"},
+		{"%08.2f", New(-12345, -3), "-0012.34"},
+		{"%+.1f", New(12345, -3), "+12.3"},
+		{"% .1f", New(12345, -3), " 12.3"},
+		{"%.2f", New(9999, -3), "10.00"},
+		{"%.0f", New(-5, -1), "0"},
+		{"%.2f", New(1, 20), "100000000000000000000.00"},
+		{"%F", Decimal(1).Div(3), "0.333333"},
+		{"%e", New(12345, -3), "1.234500e+01"},
+		{"%.2e", New(9999, -3), "1.00e+01"},
+		{"%.0e", New(-5, -1), "-5e-01"},
+		{"%E", New(1, -16), "1.000000E-16"},
+		{"%+.3e", Decimal(12345), "+1.235e+04"},
+		{"%e", Zero, "0.000000e+00"},
+		{"%g", New(12345, -3), "12.345"},
+		{"%g", Decimal(1234567), "1.234567e+06"},
+		{"%g", New(1, -5), "1e-05"},
+		{"%g", Decimal(123456789012345678), "1.23456789012345678e+17"},
+		{"%.3g", New(12345, -3), "12.3"},
+		{"%.3g", Decimal(100000), "1e+05"},
+		{"%G", New(1, 20), "1E+20"},
+		{"%d", New(-12345, -3), "-12"},
+		{"%5d", New(9999, -3), "    9"},
+		{"%f|%+f|% f|%08.2f|%e|%g|%d", NaN, "NaN|+NaN| NaN|     NaN|NaN|NaN|NaN"},
+		{"%f|%+f|% f|%08.2f|%e|%g|%d", PositiveInfinity, "+Inf|+Inf| Inf|    +Inf|+Inf|+Inf|+Inf"},
+		{"%f|%+f|% f|%08.2f|%e|%g|%d", NegativeInfinity, "-Inf|-Inf|-Inf|    -Inf|-Inf|-Inf|-Inf"},
+		{"%f|%e|%g|%d", NearNegativeZero, "0.000000|0.000000e+00|0|0"},
+		{"%x", New(101, -2), "%!x(decimal.Decimal=1.01)"},
+	}
+
+	for _, c := range cases {
+		args := make([]interface{}, strings.Count(c.format, "%"))
+		for i := range args {
+			args[i] = c.d
+		}
+
+		if s := fmt.Sprintf(c.format, args...); s != c.want {
+			t.Errorf(`fmt.Sprintf(%q, %v) should be %q and not %q`, c.format, c.d, c.want, s)
+		}
+	}
+
+	// floats and decimals print the same way
+	for _, format := range []string{"%.2f", "%10.3f", "%e", "%.3E", "%g", "%.4g", "%+08.1f"} {
+		for _, f := range []float64{0, 1, -2.5, 12.375, 1234567, 0.00012} {
+			if s, want := fmt.Sprintf(format, NewFromFloat(f)), fmt.Sprintf(format, f); s != want {
+				t.Errorf(`fmt.Sprintf(%q, NewFromFloat(%v)) should be %q and not %q`, format, f, want, s)
+			}
+		}
+	}
 }
 
 func BenchmarkIsExactlyZero(b *testing.B) {
@@ -3192,6 +5620,25 @@ func BenchmarkDecimalString(b *testing.B) {
 	}
 }
 
+func BenchmarkDecimalAppendString(b *testing.B) {
+	d, _ := NewFromString("100020003000400050e-17")
+	buf := make([]byte, 0, 32)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = d.AppendString(buf[:0])
+	}
+}
+
+func BenchmarkDecimalAppendText(b *testing.B) {
+	d, _ := NewFromString("100020003000400050e-17")
+	buf := make([]byte, 0, 32)
+
+	for i := 0; i < b.N; i++ {
+		buf, _ = d.AppendText(buf[:0])
+	}
+}
+
 func BenchmarkIntString(b *testing.B) {
 	var f int64 = 100020003000400050
 
@@ -3253,6 +5700,14 @@ func BenchmarkDecimalDiv(b *testing.B) {
 	}
 }
 
+func BenchmarkDecimalDivPowerOfTen(b *testing.B) {
+	d := New(123456789, 0)
+
+	for i := 0; i < b.N; i++ {
+		_ = d.Div(1000)
+	}
+}
+
 func BenchmarkFloat64Div(b *testing.B) {
 	var sf float64 = 1.00123456789
 	var f float64 = 123456789
@@ -3287,6 +5742,27 @@ func BenchmarkDecimalRoundCeil(b *testing.B) {
 	}
 }
 
+func BenchmarkDecimalRoundFloor(b *testing.B) {
+	s, _ := NewFromString("-1.454")
+
+	for i := 0; i < b.N; i++ {
+		s.RoundFloor(1)
+	}
+}
+
+// BenchmarkDecimalRoundNoop rounds values that already have fewer decimals than places, the frequent case of
+// rounding prices to cents, so that no rounding is needed at all.
+func BenchmarkDecimalRoundNoop(b *testing.B) {
+	s, _ := NewFromString("-1.45")
+
+	for i := 0; i < b.N; i++ {
+		s.Round(2)
+		s.RoundBank(2)
+		s.RoundCeil(2)
+		s.RoundFloor(2)
+	}
+}
+
 // BenchmarkPublicDecimalCart simulates a shopping-cart total: 8 line items, each
 // (quantity * unit_price), summed into a running total, then a tax rate applied.
 // Quantities are integers (fast-path Mul); unit prices and tax rate have decimals
@@ -0,0 +1,175 @@
+package decimal
+
+// evalPlaces is the number of decimal places of the result of Eval, enough for a calculator
+// while hiding the last digits of a rounded division (1/3*3 is output as 1).
+const evalPlaces = 12
+
+// exprParser is a recursive descent parser of arithmetic expressions:
+//
+//	expr   = term { ("+" | "-") term }
+//	term   = factor { ("*" | "/") factor }
+//	factor = ("+" | "-") factor | number | "(" expr ")"
+type exprParser struct {
+	s string
+	i int
+}
+
+// Evaluate parses and computes an arithmetic expression made of decimal numbers (as accepted by NewFromString, without unit),
+// the four operators + - * / with the usual precedence, unary signs and parentheses, e.g. "1/3 + 1/6" or "-(2.5 + 0.5) * 4".
+// It returns ErrSyntax for a malformed expression and ErrDivisionByZero for a division by zero.
+func Evaluate(expr string) (Decimal, error) {
+	if MaxParseLength > 0 && len(expr) > MaxParseLength {
+		return NaN, ErrTooLong
+	}
+
+	p := exprParser{s: expr}
+
+	d, err := p.expr()
+	if err != nil {
+		return NaN, err
+	}
+
+	if p.skip(); p.i < len(p.s) {
+		return NaN, ErrSyntax
+	}
+
+	return d, nil
+}
+
+// Eval evaluates an arithmetic expression like Evaluate and returns its result formatted like String, with a '.' decimal point
+// whatever DecimalSeparator is, after rounding to 12 decimal places, so that a command line calculator only has to print it:
+//
+//	s, err := Eval("1/3 + 1/6") // "0.5"
+//	s, err := Eval("2 + 3 * 4") // "14"
+//	s, err := Eval("1/0")       // "", ErrDivisionByZero
+func Eval(expr string) (string, error) {
+	d, err := Evaluate(expr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(d.Round(evalPlaces).BytesTo(nil)), nil
+}
+
+// skip advances over spaces
+func (p *exprParser) skip() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n' || p.s[p.i] == '\r') {
+		p.i++
+	}
+}
+
+func (p *exprParser) expr() (Decimal, error) {
+	d, err := p.term()
+	if err != nil {
+		return d, err
+	}
+
+	for {
+		p.skip()
+		if p.i == len(p.s) || p.s[p.i] != '+' && p.s[p.i] != '-' {
+			return d, nil
+		}
+
+		op := p.s[p.i]
+		p.i++
+
+		t, err := p.term()
+		if err != nil {
+			return t, err
+		}
+
+		if op == '+' {
+			d = d.Add(t)
+		} else {
+			d = d.Sub(t)
+		}
+	}
+}
+
+func (p *exprParser) term() (Decimal, error) {
+	d, err := p.factor()
+	if err != nil {
+		return d, err
+	}
+
+	for {
+		p.skip()
+		if p.i == len(p.s) || p.s[p.i] != '*' && p.s[p.i] != '/' {
+			return d, nil
+		}
+
+		op := p.s[p.i]
+		p.i++
+
+		f, err := p.factor()
+		if err != nil {
+			return f, err
+		}
+
+		if op == '*' {
+			d = d.Mul(f)
+		} else if f.IsZero() {
+			return NaN, ErrDivisionByZero
+		} else {
+			d = d.Div(f)
+		}
+	}
+}
+
+func (p *exprParser) factor() (Decimal, error) {
+	p.skip()
+	if p.i == len(p.s) {
+		return NaN, ErrSyntax
+	}
+
+	switch c := p.s[p.i]; {
+	case c == '+' || c == '-':
+		p.i++
+
+		d, err := p.factor()
+		if c == '-' {
+			d = d.Neg()
+		}
+
+		return d, err
+	case c == '(':
+		p.i++
+
+		d, err := p.expr()
+		if err != nil {
+			return d, err
+		}
+
+		if p.skip(); p.i == len(p.s) || p.s[p.i] != ')' {
+			return NaN, ErrSyntax
+		}
+		p.i++
+
+		return d, nil
+	case c >= '0' && c <= '9' || c == '.':
+		// a number is made of digits, a dot, underscores and an optional signed exponent
+		j := p.i
+		for j < len(p.s) {
+			if c := p.s[j]; c >= '0' && c <= '9' || c == '.' || c == '_' {
+				j++
+			} else if (c == 'e' || c == 'E') && j+1 < len(p.s) {
+				j++
+				if p.s[j] == '+' || p.s[j] == '-' {
+					j++
+				}
+			} else {
+				break
+			}
+		}
+
+		d, err := NewFromString(p.s[p.i:j])
+		if err != nil {
+			return NaN, ErrSyntax
+		}
+		p.i = j
+
+		return d, nil
+	default:
+		return NaN, ErrSyntax
+	}
+}
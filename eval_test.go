@@ -0,0 +1,63 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	cases := []struct {
+		expr, want string
+	}{
+		{"1/3 + 1/6", "0.5"},
+		{"2 + 3 * 4", "14"},
+		{"(2 + 3) * 4", "20"},
+		{"2 * 3 + 4", "10"},
+		{"-(2.5 + 0.5) * 4", "-12"},
+		{"10 - 4 - 3", "3"},
+		{"100 / 10 / 2", "5"},
+		{"1/3", "0.333333333333"},
+		{"2/3", "0.666666666667"},
+		{"1e3 / 8", "125"},
+		{"1.5e-2 * 2", "0.03"},
+		{"  7 ", "7"},
+		{"--1", "1"},
+		{"-2 * -3", "6"},
+		{"1_000 * 2", "2000"},
+		{"0.1 + 0.2", "0.3"},
+		{"((1))", "1"},
+		{"1/3 * 3", "1"},
+	}
+
+	for _, c := range cases {
+		if s, err := Eval(c.expr); err != nil || s != c.want {
+			t.Errorf(`Eval(%q) should be %s and not %s (err = %v)`, c.expr, c.want, s, err)
+		}
+	}
+
+	errs := []struct {
+		expr string
+		err  error
+	}{
+		{"", ErrSyntax},
+		{"1 +", ErrSyntax},
+		{"(1", ErrSyntax},
+		{"1)", ErrSyntax},
+		{"2 * * 3", ErrSyntax},
+		{"abc", ErrSyntax},
+		{"1.2.3", ErrSyntax},
+		{"2 3", ErrSyntax},
+		{"1/0", ErrDivisionByZero},
+		{"1 / (2 - 2)", ErrDivisionByZero},
+	}
+
+	for _, c := range errs {
+		if s, err := Eval(c.expr); err != c.err || s != "" {
+			t.Errorf(`Eval(%q) should return %v and not %q (err = %v)`, c.expr, c.err, s, err)
+		}
+	}
+
+	// Evaluate keeps the full precision
+	if d, err := Evaluate("1/3"); err != nil || d.String() != "~0.3333333333333333" {
+		t.Errorf(`Evaluate("1/3") should be ~0.3333333333333333 and not %v (err = %v)`, d, err)
+	}
+}
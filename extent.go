@@ -0,0 +1,73 @@
+package decimal
+
+// Extent tracks the smallest and the largest of a stream of decimals in one pass,
+// without collecting them in a slice to call Min and Max.
+// NaN values are skipped: they are not comparable, so they are neither a minimum nor a maximum and are not counted.
+//
+// The zero Extent is empty and ready to use, its Min, Max and Range are NaN until a value is added.
+//
+// Example:
+//
+//	var x Extent
+//	for _, d := range []Decimal{5, -2, New(75, -1)} {
+//		x.Add(d)
+//	}
+//	x.Min()   // -2
+//	x.Max()   // 7.5
+//	x.Range() // 9.5
+type Extent struct {
+	min, max Decimal
+	n        int
+}
+
+// Add takes d into account, NaN being skipped.
+func (x *Extent) Add(d Decimal) {
+	if d.IsNaN() {
+		return
+	}
+
+	if x.n == 0 {
+		x.min, x.max = d, d
+	} else if d.LessThan(x.min) {
+		x.min = d
+	} else if d.GreaterThan(x.max) {
+		x.max = d
+	}
+	x.n++
+}
+
+// Count returns the number of values taken into account, NaN excluded.
+func (x *Extent) Count() int {
+	return x.n
+}
+
+// Min returns the smallest value added so far, or NaN if x is empty.
+func (x *Extent) Min() Decimal {
+	if x.n == 0 {
+		return NaN
+	}
+
+	return x.min
+}
+
+// Max returns the largest value added so far, or NaN if x is empty.
+func (x *Extent) Max() Decimal {
+	if x.n == 0 {
+		return NaN
+	}
+
+	return x.max
+}
+
+// Range returns Max() - Min(), or NaN if x is empty.
+// It is Zero when all the values are equal, even infinite ones, and +Inf when the difference overflows.
+func (x *Extent) Range() Decimal {
+	if x.n == 0 {
+		return NaN
+	}
+	if x.min == x.max {
+		return Zero
+	}
+
+	return x.max.Sub(x.min)
+}
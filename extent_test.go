@@ -0,0 +1,57 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestExtent(t *testing.T) {
+	var x Extent
+
+	if !x.Min().IsNaN() || !x.Max().IsNaN() || !x.Range().IsNaN() || x.Count() != 0 {
+		t.Errorf(`empty Extent should have NaN min, max and range and not %v, %v, %v`, x.Min(), x.Max(), x.Range())
+	}
+
+	cases := []struct {
+		d             Decimal
+		min, max, rng string
+		count         int
+	}{
+		{5, "5", "5", "0", 1},
+		{-2, "-2", "5", "7", 2},
+		{New(75, -1), "-2", "7.5", "9.5", 3},
+		{NaN, "-2", "7.5", "9.5", 3},
+		{3, "-2", "7.5", "9.5", 4},
+		{New(-25, -1), "-2.5", "7.5", "10", 5},
+		{NewFromInt(1).Div(3), "-2.5", "7.5", "10", 6},
+		{New(1, 20), "-2.5", "100000000000000000000", "~100000000000000000000", 7},
+		{New(-1, 32), "-100000000000000000000000000000000", "100000000000000000000", "100000000000100000000000000000000", 8},
+		{PositiveInfinity, "-100000000000000000000000000000000", "+Inf", "+Inf", 9},
+	}
+
+	for _, c := range cases {
+		x.Add(c.d)
+
+		if x.Min().String() != c.min || x.Max().String() != c.max || x.Range().String() != c.rng || x.Count() != c.count {
+			t.Errorf(`after adding %v, Extent should have min %s, max %s, range %s and count %d and not %v, %v, %v and %d`,
+				c.d, c.min, c.max, c.rng, c.count, x.Min(), x.Max(), x.Range(), x.Count())
+		}
+	}
+
+	// NaN only leaves the extent empty
+	var y Extent
+	y.Add(NaN)
+	if !y.Min().IsNaN() || !y.Max().IsNaN() || y.Count() != 0 {
+		t.Errorf(`Extent of NaN only should be empty and not %v, %v`, y.Min(), y.Max())
+	}
+
+	// equal infinities have no range
+	y.Add(PositiveInfinity)
+	y.Add(PositiveInfinity)
+	if y.Range() != Zero {
+		t.Errorf(`range of +Inf and +Inf should be 0 and not %v`, y.Range())
+	}
+	y.Add(NegativeInfinity)
+	if y.Min() != NegativeInfinity || y.Max() != PositiveInfinity || y.Range() != PositiveInfinity {
+		t.Errorf(`Extent of -Inf and +Inf should be [-Inf, +Inf] and range +Inf and not [%v, %v] and %v`, y.Min(), y.Max(), y.Range())
+	}
+}
@@ -16,7 +16,7 @@ func FuzzNewFromString(f *testing.F) {
 		"123.456e+15", ".0001", "1_000",
 		"~0", "+~0", "-~0", "+Inf", "-Inf", "NaN",
 		"null", "Null", "nil",
-		"", " ", "abc", "1.2.3", "1ee2", "+", "-", "~",
+		"", " ", "abc", "1.2.3", "1ee2", "12e", "12e+", "12e-", "+", "-", "~",
 		"1.7976931348623157e+308", "5e-324",
 	}
 	for _, s := range seeds {
@@ -21,14 +21,14 @@ type Length int64
 const (
 	// LengthMaxInt constant is the maximal int64 value that can be safely saved as Length with exponent still 0.
 	// LengthMaxInt is as well the maximum value of mantissa of Length and the bitmask to extract mantissa value of a Length.
-	LengthMaxInt = 0x001fffffffffffff
-
-	lengthMinE     = -16
-	lengthMaxE     = 15
-	lengthBitE     = 57
-	lengthEBitmask = 0x3e00000000000000
-	lengthBitT     = 53
-	lengthTBitmask = 0x01e0000000000000
+	LengthMaxInt = quantityMaxInt
+
+	lengthMinE     = quantityMinE
+	lengthMaxE     = quantityMaxE
+	lengthBitE     = quantityBitE
+	lengthEBitmask = quantityEBitmask
+	lengthBitT     = quantityBitT
+	lengthTBitmask = quantityTBitmask
 )
 
 var (
@@ -63,101 +63,44 @@ var (
 	}
 )
 
-// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
-func (l Length) vmet() (v, m uint64, e int64, t *unit) {
-	var u uint64
-
-	if l < 0 {
-		u = uint64(-l)
-		v = (u & loss) | sign
-	} else {
-		u = uint64(l)
-		v = u & loss
-	}
-
-	e = int64((u&lengthEBitmask)<<2) >> (2 + lengthBitE) // e is now fully signed exponent
-
-	m = u & LengthMaxInt
+// lengthTable is the unit table of lengths, an overflowing length keeps its unit
+var lengthTable = &UnitTable{units: lengthUnits[:], fixed: true}
 
-	t = &lengthUnits[(u&lengthTBitmask)>>lengthBitT]
-	v |= u & lengthTBitmask // v keep unit
-
-	// take care of special number
-	if m == 0 {
-		if e == lengthMinE {
-			e = math.MinInt64
-		} else if e == lengthMaxE {
-			e = math.MaxInt64
-		}
-	}
+func init() {
+	hashUnits(lengthUnits[:])
+}
 
-	return
+// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func (l Length) vmet() (v, m uint64, e int64, t *unit) {
+	return lengthTable.vmet(int64(l))
 }
 
 // internal function to define a decimal from a VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
 func vmeAsLength(v, m uint64, e int64) Length {
-	// handle special case for null and zero
-	if m == 0 && v&loss == 0 {
-		if v == 0 && e == 0 {
-			return Null
-		} else {
-			if v&lengthTBitmask == 0 {
-				return Length(math.MinInt64)
-			} else {
-				return Length(v & lengthTBitmask)
-			}
-		}
-	} else {
-		// FIXME: vmeNormalize does not try to change unit
-		v, m, e = vmeNormalize(v, m, e, LengthMaxInt, lengthMinE, lengthMaxE)
-
-		// FIXME: out-of-range cannot occurs as normalization has been done
-		v |= m | uint64(e<<lengthBitE)&lengthEBitmask
-
-		if v&sign != 0 {
-			return -Length(v ^ sign)
-		} else {
-			return Length(v)
-		}
-	}
+	return Length(lengthTable.vmeAs(v, m, e))
 }
 
 // NewLength returns a new fixed-point decimal length, value * 10 ^ exp using unit.
-func NewLength(value int64, exp int32, unit string) (l Length, err error) {
-	var v, m uint64
-	var e int64
+func NewLength(value int64, exp int32, unit string) (Length, error) {
+	l, err := lengthTable.fromInt(value, exp, unit)
 
-	if value <= 0 {
-		v, m, e = sign, uint64(-value), int64(exp)
-	} else {
-		v, m, e = 0, uint64(value), int64(exp)
-	}
-
-	v, m, e, err = vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, lengthUnits[:])
-	l = vmeAsLength(v, m, e)
-
-	return
+	return Length(l), err
 }
 
 // NewLengthFromDecimal converts a Decimal to Length using unit.
-func NewLengthFromDecimal(value Decimal, unit string) (l Length, err error) {
-	v, m, e := value.vme()
+func NewLengthFromDecimal(value Decimal, unit string) (Length, error) {
+	l, err := lengthTable.fromDecimal(value, unit)
 
-	v, m, e, err = vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, lengthUnits[:])
-	l = vmeAsLength(v, m, e)
-
-	return
+	return Length(l), err
 }
 
 // NewLengthFromBytes returns a new Length from a slice of bytes representation.
 //
 // If no length unit is given, 'm' is assumed.
 func NewLengthFromBytes(value []byte) (Length, error) {
-	if v, m, e, err := vmeFromBytes(value, lengthUnits[:]); err == nil {
-		return vmeAsLength(v, m, e), nil
-	} else {
-		return 0, err
-	}
+	l, err := lengthTable.fromBytes(value)
+
+	return Length(l), err
 }
 
 // NewLengthFromString returns a new Length from a string representation.
@@ -185,15 +128,9 @@ func NewLengthFromString(value string) (Length, error) {
 //
 //	cm
 func (l Length) Unit() string {
-	var u uint64
+	_, _, _, t := l.vmet()
 
-	if l < 0 {
-		u = uint64(-l)
-	} else {
-		u = uint64(l)
-	}
-
-	return lengthUnits[(u&lengthTBitmask)>>lengthBitT].u
+	return t.u
 }
 
 // Abs returns the absolute value of the length.
@@ -220,36 +157,7 @@ func (l Length) Abs() Length {
 //	124km
 //	124000m
 func (l1 Length) Add(l2 Length) Length {
-	v1, m1, e1, t1 := l1.vmet()
-	v2, m2, e2, t2 := l2.vmet()
-
-	if t2.c.IsInteger() {
-		e2 += t2.c.Int64()
-	} else {
-		vc, mc, ec := t2.c.vme()
-		v2, m2, e2 = vmeMul(v2, m2, e2, vc, mc, ec)
-	}
-	if t1.c.IsInteger() {
-		e2 -= t1.c.Int64()
-	} else {
-		vc, mc, ec := t1.c.vme()
-
-		var rem uint64
-		v2, m2, e2, rem, _ = vmeDivRem(v2, m2, e2, vc, mc, ec, int32(DivisionPrecision))
-
-		if rem != 0 {
-			v2 |= loss
-
-			// FIXME: fix m so that the result is the nearest, like shopspring/decimal
-			if (rem << 1) >= mc {
-				m2++
-			}
-		}
-	}
-
-	v, m, e := vmeAdd(v1, m1, e1, v2, m2, e2)
-
-	return vmeAsLength(v, m, e)
+	return Length(lengthTable.add(int64(l1), int64(l2)))
 }
 
 // Sub returns l1 - l2 using l1 unit.
@@ -259,28 +167,12 @@ func (l1 Length) Sub(l2 Length) Length {
 
 // Mul returns l * d using l unit.
 func (l Length) Mul(d Decimal) Length {
-	v1, m1, e1, _ := l.vmet()
-	v2, m2, e2 := d.vme()
-
-	return vmeAsLength(vmeMul(v1, m1, e1, v2, m2, e2))
+	return Length(lengthTable.mul(int64(l), d))
 }
 
 // Div returns l / d using l unit. If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
 func (l Length) Div(d Decimal) Length {
-	v1, m1, e1, _ := l.vmet()
-	v2, m2, e2 := d.vme()
-
-	v, m, e, rem, _ := vmeDivRem(v1, m1, e1, v2, m2, e2, int32(DivisionPrecision))
-
-	if rem != 0 {
-		v |= loss
-
-		// fix m so that the result is the nearest, like in shopspring/decimal
-		if (rem << 1) >= m2 {
-			m++
-		}
-	}
-	return vmeAsLength(v, m, e)
+	return Length(lengthTable.div(int64(l), d))
 }
 
 // String returns the string representation of the length with the fixed point and unit.
@@ -299,23 +191,19 @@ func (l Length) String() string {
 
 // BytesTo appends the string representation of the decimal to a slice of byte, if the decimal is Null it appends 0.
 func (l Length) BytesTo(b []byte) []byte {
-	v, m, e, t := l.vmet()
-
 	// the maximal length of decimal representation in bytes in such conditions is 20
-	return vmetBytesTo(b, v, m, e, 0, t, true, false)
+	return lengthTable.bytesTo(b, int64(l), true)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
 func (l Length) MarshalJSON() ([]byte, error) {
-	v, m, e, t := l.vmet()
-
-	return vmetBytesTo(nil, v, m, e, 0, t, false, false), nil
+	return lengthTable.bytesTo(nil, int64(l), false), nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (l *Length) UnmarshalJSON(b []byte) error {
-	if v, m, e, err := vmeFromBytes(b, lengthUnits[:]); err == nil {
-		*l = vmeAsLength(v, m, e)
+	if _l, err := lengthTable.fromBytes(b); err == nil {
+		*l = Length(_l)
 
 		return nil
 	} else {
@@ -349,7 +237,8 @@ func (l Length) MarshalBinary() (data []byte, err error) {
 	v, m, e, _ := l.vmet()
 	unit := (v & lengthTBitmask) >> lengthBitT
 
-	if m == 0 || unit == 0 {
+	// a zero keeps its unit (0g is not 0kg), only the magic values have no unit
+	if unit == 0 || m == 0 && v&loss != 0 {
 		return marshalBinaryV1(v, m, e), nil
 	}
 
@@ -496,7 +385,7 @@ func (l Length) IsExactlyZero() bool {
 //	false if l < 0
 //	false if l > 0
 func (l Length) IsZero() bool {
-	return l.IsExactlyZero() || Length(uint64(l)&^sign&^lengthTBitmask) == Length(loss)
+	return quantityIsZero(int64(l))
 }
 
 // IsExact return true if a length has its loss bit not set, ie it has not lost its precision during computation or conversion.
@@ -537,13 +426,7 @@ func (l Length) IsInfinite() bool {
 //	true if l is not a number (NaN)
 //	false in any other case
 func (l Length) IsNaN() bool {
-	v, m, e, _ := l.vmet()
-	if m == 0 && v&loss != 0 {
-		if e != 0 && e != math.MinInt64 && e != math.MaxInt64 {
-			return true
-		}
-	}
-	return false
+	return quantityIsNaN(int64(l))
 }
 
 // Sign return
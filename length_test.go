@@ -96,6 +96,35 @@ func TestLengthAdd(t *testing.T) {
 	}
 }
 
+func TestLengthAddMixedUnits(t *testing.T) {
+	cases := []struct {
+		l1, l2, want string
+	}{
+		{"100cm", "1m", "200cm"},
+		{"1m", "100cm", "2m"},
+		{"1km", "500m", "1.5km"},
+		{"2.5mm", "500µm", "3mm"},
+		{"1µm", "1nm", "1.001µm"},
+		{"1ft", "6in", "1.5ft"},
+		{"1yd", "1ft", "~1.333333333333333yd"},
+		{"1mi", "1760yd", "2mi"},
+		{"1in", "2.54cm", "2in"},
+	}
+
+	for _, c := range cases {
+		l1, err1 := NewLengthFromString(c.l1)
+		l2, err2 := NewLengthFromString(c.l2)
+		if err1 != nil || err2 != nil {
+			t.Errorf(`NewLengthFromString(%q) and NewLengthFromString(%q) should be ok, errors = %v, %v`, c.l1, c.l2, err1, err2)
+			continue
+		}
+
+		if l := l1.Add(l2); l.String() != c.want {
+			t.Errorf(`%v + %v should be %s and not %v`, l1, l2, c.want, l)
+		}
+	}
+}
+
 func TestLengthMul(t *testing.T) {
 	l1, err := NewLengthFromString("11mm")
 	if err != nil {
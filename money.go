@@ -0,0 +1,113 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCurrencyMismatch occurs when an operation combines amounts of different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currencySymbols holds the symbol output by Money.String for the most common currencies, the others are output with their ISO 4217 code.
+var currencySymbols = map[string]string{
+	"EUR": "€", "GBP": "£", "INR": "₹", "JPY": "¥", "KRW": "₩", "USD": "$",
+}
+
+// Money represents an amount of an ISO 4217 currency, always rounded to the minor unit digits of the currency
+// (2 for USD or EUR, 0 for JPY and 3 for BHD) with banker's rounding, see RoundToCurrency.
+// Operations combining amounts of different currencies return ErrCurrencyMismatch rather than a meaningless amount.
+//
+// Note the zero Money has no currency.
+type Money struct {
+	amount Decimal
+	code   string
+	places int32
+}
+
+// NewMoney returns a new Money of amount rounded to the minor unit digits of the ISO 4217 currency code (case-insensitive).
+// An unknown code returns ErrCurrency.
+//
+// Example:
+//
+//	m, err := NewMoney(New(12345675, -4), "USD") // $1,234.57
+func NewMoney(amount Decimal, code string) (Money, error) {
+	places, err := currencyPlaces(code)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount: amount.RoundBank(places), code: strings.ToUpper(code), places: places}, nil
+}
+
+// Amount returns the amount of m, rounded to the minor unit digits of its currency.
+func (m Money) Amount() Decimal {
+	return m.amount
+}
+
+// Currency returns the ISO 4217 code of the currency of m.
+func (m Money) Currency() string {
+	return m.code
+}
+
+// Add returns m1 + m2, it returns ErrCurrencyMismatch if m1 and m2 are not of the same currency.
+func (m1 Money) Add(m2 Money) (Money, error) {
+	if m1.code != m2.code {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{amount: m1.amount.Add(m2.amount).RoundBank(m1.places), code: m1.code, places: m1.places}, nil
+}
+
+// Sub returns m1 - m2, it returns ErrCurrencyMismatch if m1 and m2 are not of the same currency.
+func (m1 Money) Sub(m2 Money) (Money, error) {
+	if m1.code != m2.code {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{amount: m1.amount.Sub(m2.amount).RoundBank(m1.places), code: m1.code, places: m1.places}, nil
+}
+
+// Mul returns m * d rounded to the minor unit digits of the currency with banker's rounding.
+func (m Money) Mul(d Decimal) Money {
+	return Money{amount: m.amount.Mul(d).RoundBank(m.places), code: m.code, places: m.places}
+}
+
+// Div returns m / d rounded to the minor unit digits of the currency with banker's rounding.
+func (m Money) Div(d Decimal) Money {
+	return Money{amount: m.amount.Div(d).RoundBank(m.places), code: m.code, places: m.places}
+}
+
+// String returns the amount with all the minor unit digits of its currency and its integer part grouped by thousands,
+// after the symbol of the currency if it has a common one or its ISO 4217 code otherwise, e.g. "$1,234.50", "-€12.00" or "BHD 1,234.568".
+func (m Money) String() string {
+	if m.code == "" {
+		return m.amount.String()
+	}
+
+	s := m.amount.StringGrouped(',', '.', m.places)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	if symbol, ok := currencySymbols[m.code]; ok {
+		return sign + symbol + s
+	}
+
+	return sign + m.code + " " + s
+}
+
+// Format implements the fmt.Formatter interface: %v and %s have the output of String, %q the double-quoted one,
+// the other verbs format the amount like Decimal.Format does, without symbol.
+func (m Money) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		formatPadTo(s, []byte(m.String()), false)
+	case 'q':
+		formatPadTo(s, []byte(`"`+m.String()+`"`), false)
+	default:
+		m.amount.Format(s, verb)
+	}
+}
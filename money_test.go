@@ -0,0 +1,74 @@
+package decimal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	cases := []struct {
+		amount Decimal
+		code   string
+		want   string
+	}{
+		{New(12345675, -4), "USD", "$1,234.57"},
+		{New(12345675, -4), "eur", "€1,234.57"},
+		{New(12345675, -4), "JPY", "¥1,235"},
+		{New(12345675, -4), "BHD", "BHD 1,234.568"},
+		{New(-12, 0), "EUR", "-€12.00"},
+		{New(5, -1), "CHF", "CHF 0.50"},
+		{New(1225, -3), "USD", "$1.22"},
+		{Zero, "GBP", "£0.00"},
+	}
+
+	for _, c := range cases {
+		m, err := NewMoney(c.amount, c.code)
+		if err != nil || m.String() != c.want {
+			t.Errorf(`NewMoney(%v, %q) should be %s and not %v (err = %v)`, c.amount, c.code, c.want, m, err)
+		}
+		if s := fmt.Sprintf("%v", m); s != c.want {
+			t.Errorf(`fmt.Sprintf("%%v", %v) should be %s and not %s`, m, c.want, s)
+		}
+	}
+
+	if m, err := NewMoney(1, "XYZ"); err != ErrCurrency {
+		t.Errorf(`NewMoney(1, "XYZ") should return ErrCurrency and not %v (err = %v)`, m, err)
+	}
+
+	usd, _ := NewMoney(New(1000, -2), "usd")
+	if usd.Currency() != "USD" || usd.Amount() != 10 {
+		t.Errorf(`%v should be 10 USD and not %v %s`, usd, usd.Amount(), usd.Currency())
+	}
+
+	// every operation is rounded to the minor unit digits with banker's rounding
+	if r := usd.Div(3); r.String() != "$3.33" || !r.Amount().IsExact() {
+		t.Errorf(`$10.00 / 3 should be $3.33 and not %v`, r)
+	}
+	if r := usd.Mul(New(1125, -4)); r.String() != "$1.12" {
+		t.Errorf(`$10.00 * 0.1125 should be $1.12 and not %v`, r)
+	}
+	if r := usd.Mul(New(1135, -4)); r.String() != "$1.14" {
+		t.Errorf(`$10.00 * 0.1135 should be $1.14 and not %v`, r)
+	}
+
+	third := usd.Div(3)
+	if r, err := third.Add(third); err != nil || r.String() != "$6.66" {
+		t.Errorf(`$3.33 + $3.33 should be $6.66 and not %v (err = %v)`, r, err)
+	}
+	if r, err := third.Sub(usd); err != nil || r.String() != "-$6.67" {
+		t.Errorf(`$3.33 - $10.00 should be -$6.67 and not %v (err = %v)`, r, err)
+	}
+
+	// cross-currency operations are rejected
+	eur, _ := NewMoney(10, "EUR")
+	if r, err := usd.Add(eur); err != ErrCurrencyMismatch {
+		t.Errorf(`$10.00 + €10.00 should return ErrCurrencyMismatch and not %v (err = %v)`, r, err)
+	}
+	if r, err := usd.Sub(eur); err != ErrCurrencyMismatch {
+		t.Errorf(`$10.00 - €10.00 should return ErrCurrencyMismatch and not %v (err = %v)`, r, err)
+	}
+
+	if s := fmt.Sprintf("%.1f|%q|%12v", usd, usd, usd); s != `10.0|"$10.00"|      $10.00` {
+		t.Errorf(`fmt.Sprintf of %v should be 10.0|"$10.00"|      $10.00 and not %s`, usd, s)
+	}
+}
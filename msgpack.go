@@ -0,0 +1,81 @@
+package decimal
+
+// MessagePack support without any dependency: a decimal is encoded as a msgpack bin 8 value
+// (0xc4, length, payload) whose payload is the MarshalBinary output, so that all magic values round-trip.
+// MarshalMsgpack/UnmarshalMsgpack match the github.com/vmihailenco/msgpack Marshaler and Unmarshaler interfaces,
+// MarshalMsg/UnmarshalMsg/Msgsize match the github.com/tinylib/msgp Marshaler, Unmarshaler and Sizer interfaces.
+
+const (
+	msgpackNil  = 0xc0
+	msgpackBin8 = 0xc4
+
+	// msgpackMaxSize is the size of the largest msgpack encoding of a decimal: bin 8 header and a 10 bytes payload
+	msgpackMaxSize = 12
+)
+
+// MarshalMsgpack returns the MessagePack encoding of the decimal, a bin 8 value holding its MarshalBinary output.
+func (d Decimal) MarshalMsgpack() ([]byte, error) {
+	return d.MarshalMsg(make([]byte, 0, msgpackMaxSize))
+}
+
+// UnmarshalMsgpack decodes a MessagePack bin value as written by MarshalMsgpack, a msgpack nil gives Null.
+func (d *Decimal) UnmarshalMsgpack(data []byte) error {
+	var _d Decimal
+
+	if rest, err := _d.UnmarshalMsg(data); err != nil {
+		return err
+	} else if len(rest) != 0 {
+		return ErrFormat
+	}
+	*d = _d
+
+	return nil
+}
+
+// MarshalMsg appends the MessagePack encoding of the decimal to b.
+func (d Decimal) MarshalMsg(b []byte) ([]byte, error) {
+	n := len(b)
+
+	b, err := d.AppendBinary(append(b, msgpackBin8, 0))
+	if err != nil {
+		return b[:n], err
+	}
+	b[n+1] = byte(len(b) - n - 2)
+
+	return b, nil
+}
+
+// UnmarshalMsg decodes the MessagePack encoding of a decimal at the start of b and returns the remaining bytes.
+func (d *Decimal) UnmarshalMsg(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return b, ErrFormat
+	}
+
+	switch b[0] {
+	case msgpackNil:
+		*d = Null
+
+		return b[1:], nil
+	case msgpackBin8:
+		if len(b) < 2 || len(b) < 2+int(b[1]) {
+			return b, ErrFormat
+		}
+
+		n := 2 + int(b[1])
+
+		var _d Decimal
+		if err := _d.UnmarshalBinary(b[2:n]); err != nil {
+			return b, err
+		}
+		*d = _d
+
+		return b[n:], nil
+	default:
+		return b, ErrFormat
+	}
+}
+
+// Msgsize returns an upper bound of the size of the MessagePack encoding of the decimal.
+func (d Decimal) Msgsize() int {
+	return msgpackMaxSize
+}
@@ -0,0 +1,80 @@
+package decimal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarshalMsgpack(t *testing.T) {
+	cases := []struct {
+		d    Decimal
+		want []byte
+	}{
+		{100, []byte{0xc4, 0x02, 0x01, 0x64}},
+		{-320, []byte{0xc4, 0x03, 0x81, 0xc0, 0x02}},
+		{New(101, -2), []byte{0xc4, 0x02, 0x3d, 0x65}},
+		{Null, []byte{0xc4, 0x01, 0x00}},
+		{Zero, []byte{0xc4, 0x01, 0x80}},
+		{NearZero, []byte{0xc4, 0x01, 0xc0}},
+		{NearPositiveZero, []byte{0xc4, 0x01, 0x60}},
+	}
+
+	for _, c := range cases {
+		if b, err := c.d.MarshalMsgpack(); err != nil || !bytes.Equal(b, c.want) {
+			t.Errorf(`(%v).MarshalMsgpack() should be %#v and not %#v (err = %v)`, c.d, c.want, b, err)
+		}
+
+		// tinylib/msgp style appends to an existing buffer
+		if b, err := c.d.MarshalMsg([]byte{0x92}); err != nil || !bytes.Equal(b, append([]byte{0x92}, c.want...)) {
+			t.Errorf(`(%v).MarshalMsg(0x92) should be 0x92 followed by %#v and not %#v (err = %v)`, c.d, c.want, b, err)
+		}
+		if n := c.d.Msgsize(); n < len(c.want) {
+			t.Errorf(`(%v).Msgsize() should be at least %d and not %d`, c.d, len(c.want), n)
+		}
+	}
+}
+
+func TestUnmarshalMsgpack(t *testing.T) {
+	for _, d := range []Decimal{
+		Null, Zero, NearZero, NearPositiveZero, NearNegativeZero, PositiveInfinity, NegativeInfinity,
+		100, -320, New(101, -2), New(-12345678901234567, -16), New(1, 15), New(MaxInt, -16), NewFromFloat(1.0 / 3.0),
+	} {
+		b, err := d.MarshalMsgpack()
+		if err != nil {
+			t.Errorf(`(%v).MarshalMsgpack() should be ok, error = %v`, d, err)
+			continue
+		}
+
+		var r Decimal = 99
+		if err := r.UnmarshalMsgpack(b); err != nil || r != d {
+			t.Errorf(`UnmarshalMsgpack(%#v) should be %v and not %v (err = %v)`, b, d, r, err)
+		}
+
+		// tinylib/msgp style returns the remaining bytes
+		r = 99
+		if rest, err := r.UnmarshalMsg(append(b, 0x01)); err != nil || r != d || !bytes.Equal(rest, []byte{0x01}) {
+			t.Errorf(`UnmarshalMsg(%#v) should be %v with rest [0x01] and not %v with rest %#v (err = %v)`, b, d, r, rest, err)
+		}
+	}
+
+	// NaN has several representations, only check it stays NaN
+	var r Decimal
+	if b, err := NaN.MarshalMsgpack(); err != nil {
+		t.Errorf(`NaN.MarshalMsgpack() should be ok, error = %v`, err)
+	} else if err := r.UnmarshalMsgpack(b); err != nil || !r.IsNaN() {
+		t.Errorf(`UnmarshalMsgpack(%#v) should be NaN and not %v (err = %v)`, b, r, err)
+	}
+
+	// msgpack nil is Null
+	r = 99
+	if err := r.UnmarshalMsgpack([]byte{0xc0}); err != nil || r != Null {
+		t.Errorf(`UnmarshalMsgpack(0xc0) should be Null and not %v (err = %v)`, r, err)
+	}
+
+	for _, b := range [][]byte{nil, {0xc4}, {0xc4, 0x02, 0x01}, {0xc4, 0x00}, {0xa1, 0x31}, {0x01}, {0xc4, 0x01, 0x80, 0x00}} {
+		r = 99
+		if err := r.UnmarshalMsgpack(b); err == nil || r != 99 {
+			t.Errorf(`UnmarshalMsgpack(%#v) should return an error and leave the decimal unchanged, not %v`, b, r)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package decimal
+
+// Quantity represents a fixed-point decimal with a unit taken from a UnitTable, it has the same 64 bits layout as Weight
+// (53 bits mantissa and 4 bits unit code, the index of the unit in its table) plus a pointer to its table.
+//
+// Note the zero Quantity has no table, it is Null: in Add, Sub, Equal and Compare it takes the table of the other operand,
+// so that a zero Quantity can be used to sum quantities of any table.
+type Quantity struct {
+	raw   int64
+	table *UnitTable
+}
+
+// internal function to extract quantity into VME tuple, the unit being taken from the table of q
+func (q Quantity) vmet() (v, m uint64, e int64, t *unit) {
+	return q.table.vmet(q.raw)
+}
+
+// NewQuantity returns a new fixed-point decimal quantity, value * 10 ^ exp using unit of table.
+// It returns ErrUnitSyntax for an unknown unit or a nil table.
+func NewQuantity(value int64, exp int32, unit string, table *UnitTable) (Quantity, error) {
+	if table == nil {
+		return Quantity{}, ErrUnitSyntax
+	}
+
+	raw, err := table.fromInt(value, exp, unit)
+
+	return Quantity{raw: raw, table: table}, err
+}
+
+// NewQuantityFromDecimal converts a Decimal to a Quantity using unit of table.
+// It returns ErrUnitSyntax for an unknown unit or a nil table.
+func NewQuantityFromDecimal(value Decimal, unit string, table *UnitTable) (Quantity, error) {
+	if table == nil {
+		return Quantity{}, ErrUnitSyntax
+	}
+
+	raw, err := table.fromDecimal(value, unit)
+
+	return Quantity{raw: raw, table: table}, err
+}
+
+// NewQuantityFromString returns a new Quantity from a string representation using the units of table.
+// It returns ErrUnitSyntax for a nil table.
+//
+// If no unit is given, the base unit of table is assumed.
+func NewQuantityFromString(value string, table *UnitTable) (Quantity, error) {
+	if table == nil {
+		return Quantity{}, ErrUnitSyntax
+	}
+
+	raw, err := table.fromBytes([]byte(value))
+
+	return Quantity{raw: raw, table: table}, err
+}
+
+// Table returns the unit table of q.
+func (q Quantity) Table() *UnitTable {
+	return q.table
+}
+
+// Unit returns unit string of q.
+func (q Quantity) Unit() string {
+	_, _, _, t := q.vmet()
+
+	return t.u
+}
+
+// Columns splits q into its unit string (as returned by Unit) and its numeric value expressed in that unit.
+func (q Quantity) Columns() (unit string, value Decimal) {
+	return q.Unit(), quantityValue(q.raw)
+}
+
+// In returns q converted to unit of its table. It returns ErrUnitSyntax for an unknown unit.
+func (q Quantity) In(unit string) (Quantity, error) {
+	if q.table == nil {
+		return q, ErrUnitSyntax
+	}
+
+	raw, err := q.table.in(q.raw, unit)
+
+	return Quantity{raw: raw, table: q.table}, err
+}
+
+// ToDecimal returns the value of q expressed in unit as a plain Decimal. It returns ErrUnitSyntax for an unknown unit.
+func (q Quantity) ToDecimal(unit string) (Decimal, error) {
+	q, err := q.In(unit)
+	if err != nil {
+		return NaN, err
+	}
+
+	return quantityValue(q.raw), nil
+}
+
+// tableWith returns the unit table shared by q1 and q2, a zero Quantity taking the table of the other one,
+// ok being false when both have a table and they are different
+func (q1 Quantity) tableWith(q2 Quantity) (table *UnitTable, ok bool) {
+	switch {
+	case q1.table == q2.table || q2.table == nil:
+		return q1.table, true
+	case q1.table == nil:
+		return q2.table, true
+	default:
+		return q1.table, false
+	}
+}
+
+// Add returns q1 + q2 using q1 unit, q2 being converted through the conversion factors of the units.
+// A zero Quantity q1 takes the table of q2 and the result is then in its base unit, as for a Null Weight.
+// Quantities of different unit tables cannot be added, the result is then NaN whatever the order of the operands.
+func (q1 Quantity) Add(q2 Quantity) Quantity {
+	table, ok := q1.tableWith(q2)
+	if !ok {
+		return Quantity{raw: table.vmeAs(loss, 0, 1), table: table}
+	}
+
+	return Quantity{raw: table.add(q1.raw, q2.raw), table: table}
+}
+
+// Sub returns q1 - q2 using q1 unit.
+func (q1 Quantity) Sub(q2 Quantity) Quantity {
+	return q1.Add(q2.Neg())
+}
+
+// Neg returns -q.
+func (q Quantity) Neg() Quantity {
+	return Quantity{raw: quantityNeg(q.raw), table: q.table}
+}
+
+// Mul returns q * d using q unit.
+func (q Quantity) Mul(d Decimal) Quantity {
+	return Quantity{raw: q.table.mul(q.raw, d), table: q.table}
+}
+
+// Div returns q / d using q unit. If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
+func (q Quantity) Div(d Decimal) Quantity {
+	return Quantity{raw: q.table.div(q.raw, d), table: q.table}
+}
+
+// String returns the string representation of the quantity followed by its unit.
+func (q Quantity) String() string {
+	return string(q.table.bytesTo(nil, q.raw, true))
+}
+
+// IsZero return true if q is zero or near zero, whatever its unit.
+func (q Quantity) IsZero() bool {
+	return quantityIsZero(q.raw)
+}
+
+// IsNaN return true if q is not a number (NaN).
+func (q Quantity) IsNaN() bool {
+	return quantityIsNaN(q.raw)
+}
+
+// Equal returns whether q1 and q2 represent the same quantity, whatever their units.
+func (q1 Quantity) Equal(q2 Quantity) bool {
+	return q1.Sub(q2).IsZero()
+}
+
+// Compare compares q1 and q2 and returns:
+//
+//	-1 if q1 <  q2
+//	 0 if q1 == q2
+//	+1 if q1 >  q2
+//
+// A zero Quantity compares as Null with a quantity of any table.
+// Quantities of different unit tables are not comparable, Compare panics in that case.
+func (q1 Quantity) Compare(q2 Quantity) int {
+	table, ok := q1.tableWith(q2)
+	if !ok {
+		panic("Quantity does not support comparing quantities of different unit tables")
+	}
+
+	return table.compare(q1.raw, q2.raw)
+}
@@ -0,0 +1,252 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestQuantity(t *testing.T) {
+	volumes, err := NewUnitTable(Unit{"L", 1}, Unit{"mL", New(1, -3)}, Unit{"m3", 1000}, Unit{"gal", New(3785411784, -9)}, Unit{"pt", New(473176473, -9)})
+	if err != nil {
+		t.Fatalf(`NewUnitTable() should be ok, error = %v`, err)
+	}
+
+	cases := []struct {
+		s, str, unit, in, want string
+	}{
+		{"1.5", "1.5L", "L", "mL", "1500mL"},
+		{"250 mL", "250mL", "mL", "L", "0.25L"},
+		{"2m3", "2m3", "m3", "L", "2000L"},
+		{"1gal", "1gal", "gal", "L", "3.785411784L"},
+		{"1gal", "1gal", "gal", "pt", "8pt"},
+		{"-3pt", "-3pt", "pt", "mL", "-1419.529419mL"},
+		{"0gal", "0gal", "gal", "L", "0L"},
+	}
+
+	for _, c := range cases {
+		q, err := NewQuantityFromString(c.s, volumes)
+		if err != nil {
+			t.Errorf(`NewQuantityFromString(%q) has result = %v and error = %v`, c.s, q, err)
+			continue
+		}
+
+		if q.String() != c.str {
+			t.Errorf(`NewQuantityFromString(%q) should be %s and not %v`, c.s, c.str, q)
+		}
+		if q.Unit() != c.unit {
+			t.Errorf(`%v.Unit() should be %s and not %s`, q, c.unit, q.Unit())
+		}
+		if r, err := q.In(c.in); err != nil || r.String() != c.want {
+			t.Errorf(`%v.In(%q) should be %s and not %v (err = %v)`, q, c.in, c.want, r, err)
+		} else if !r.Equal(q) {
+			t.Errorf(`%v.Equal(%v) should be true`, r, q)
+		}
+	}
+
+	for _, s := range []string{"1kg", "1 liter", "1L2"} {
+		if q, err := NewQuantityFromString(s, volumes); err == nil {
+			t.Errorf(`NewQuantityFromString(%q) should return an error and not %v`, s, q)
+		}
+	}
+
+	// arithmetic keeps the unit of the first operand
+	l, _ := NewQuantity(1, 0, "L", volumes)
+	ml, _ := NewQuantity(250, 0, "mL", volumes)
+	if r := l.Add(ml); r.String() != "1.25L" {
+		t.Errorf(`1L + 250mL should be 1.25L and not %v`, r)
+	}
+	if r := ml.Sub(l).Mul(2); r.String() != "-1500mL" {
+		t.Errorf(`(250mL - 1L) * 2 should be -1500mL and not %v`, r)
+	}
+	if r := l.Div(4); !r.Equal(ml) {
+		t.Errorf(`1L / 4 should be equal to 250mL and not %v`, r)
+	}
+	if l.Compare(ml) != 1 || ml.Compare(l) != -1 || l.Compare(l) != 0 {
+		t.Errorf(`1L should be greater than 250mL`)
+	}
+	if d, err := ml.ToDecimal("L"); err != nil || !d.Equal(New(25, -2)) {
+		t.Errorf(`250mL.ToDecimal("L") should be 0.25 and not %v (err = %v)`, d, err)
+	}
+
+	// quantities of different tables cannot be mixed
+	other, _ := NewUnitTable(Unit{"L", 1})
+	o, _ := NewQuantity(1, 0, "L", other)
+	if r := l.Add(o); !r.IsNaN() {
+		t.Errorf(`quantities of different tables should add to NaN and not %v`, r)
+	}
+	if r := o.Add(l); !r.IsNaN() {
+		t.Errorf(`quantities of different tables should add to NaN whatever their order and not %v`, r)
+	}
+	for _, c := range [][2]Quantity{{l, o}, {o, l}} {
+		func(q1, q2 Quantity) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf(`%v.Compare(%v) of different tables should panic`, q1, q2)
+				}
+			}()
+			_ = q1.Compare(q2)
+		}(c[0], c[1])
+	}
+}
+
+func TestNewUnitTable(t *testing.T) {
+	cases := [][]Unit{
+		{},
+		{{"", 1}},
+		{{"L", 1}, {"l", 1}},
+		{{"L", 1}, {"mL", Zero}},
+		{{"L", 1}, {"mL", -1}},
+		{{"L", 1}, {"x", PositiveInfinity}},
+		{{"L", 1}, {"x", NaN}},
+		make([]Unit, 17),
+	}
+
+	for _, units := range cases {
+		if table, err := NewUnitTable(units...); err == nil {
+			t.Errorf(`NewUnitTable(%v) should return an error and not %v`, units, table.Units())
+		}
+	}
+
+	table, err := NewUnitTable(Unit{"s", 1}, Unit{"min", 60}, Unit{"h", 3600})
+	if err != nil {
+		t.Fatalf(`NewUnitTable() should be ok, error = %v`, err)
+	}
+	if units := table.Units(); len(units) != 3 || units[0] != "s" || units[2] != "h" {
+		t.Errorf(`table.Units() should be [s min h] and not %v`, units)
+	}
+
+	h, _ := NewQuantityFromString("1.5h", table)
+	if r, _ := h.In("min"); r.String() != "90min" {
+		t.Errorf(`1.5h.In("min") should be 90min and not %v`, r)
+	}
+}
+
+func TestRegisterUnit(t *testing.T) {
+	volumes, _ := NewUnitTable(Unit{"L", 1}, Unit{"mL", New(1, -3)})
+
+	if err := RegisterUnit(volumes, "bu", New(3523907016688, -11)); err != nil {
+		t.Fatalf(`RegisterUnit(volumes, "bu") should be ok, error = %v`, err)
+	}
+	if err := RegisterUnit(volumes, "board-ft", New(2359737216, -12)); err != nil {
+		t.Fatalf(`RegisterUnit(volumes, "board-ft") should be ok, error = %v`, err)
+	}
+	if units := volumes.Units(); len(units) != 4 || units[2] != "bu" || units[3] != "board-ft" {
+		t.Errorf(`volumes.Units() should be [L mL bu board-ft] and not %v`, units)
+	}
+
+	bu, err := NewQuantityFromString("2bu", volumes)
+	if err != nil || bu.String() != "2bu" {
+		t.Errorf(`NewQuantityFromString("2bu") should be 2bu and not %v (err = %v)`, bu, err)
+	}
+	if l, _ := bu.In("L"); l.String() != "70.47814033376L" {
+		t.Errorf(`2bu.In("L") should be 70.47814033376L and not %v`, l)
+	}
+	if b, _ := NewQuantityFromString("1000 board-ft", volumes); b.String() != "1000board-ft" {
+		t.Errorf(`NewQuantityFromString("1000 board-ft") should be 1000board-ft and not %v`, b)
+	}
+
+	cases := []struct {
+		symbol string
+		factor Decimal
+		err    error
+	}{
+		{"bu", 1, ErrUnitSyntax},
+		{"BU", 1, ErrUnitSyntax},
+		{"", 1, ErrUnitSyntax},
+		{"x", Zero, ErrOutOfRange},
+		{"x", -1, ErrOutOfRange},
+		{"x", NaN, ErrOutOfRange},
+	}
+
+	for _, c := range cases {
+		if err := RegisterUnit(volumes, c.symbol, c.factor); err != c.err {
+			t.Errorf(`RegisterUnit(volumes, %q, %v) should return %v and not %v`, c.symbol, c.factor, c.err, err)
+		}
+	}
+
+	// only 16 units fit in the 4 bits unit code
+	for i := len(volumes.Units()); i < 16; i++ {
+		if err := RegisterUnit(volumes, "u"+string(rune('a'+i)), NewFromInt(int64(i))); err != nil {
+			t.Fatalf(`RegisterUnit of unit %d should be ok, error = %v`, i, err)
+		}
+	}
+	if err := RegisterUnit(volumes, "full", 2); err != ErrOutOfRange {
+		t.Errorf(`RegisterUnit of a 17th unit should return ErrOutOfRange and not %v`, err)
+	}
+	if q, err := NewQuantityFromString("3up", volumes); err != nil || q.Unit() != "up" {
+		t.Errorf(`NewQuantityFromString("3up") should be 3up and not %v (err = %v)`, q, err)
+	}
+}
+
+func TestQuantityOverflow(t *testing.T) {
+	volumes, _ := NewUnitTable(Unit{"L", 1}, Unit{"mL", New(1, -3)}, Unit{"gal", New(3785411784, -9)})
+
+	// an overflowing quantity is only promoted to a coarser unit of its own table, never to a weight unit
+	cases := []struct {
+		s, want string
+	}{
+		{"1234567891234567e15mL", "1234567891234567000000000000000L"},
+		{"1234567891234567e15L", "+Inf"},
+		{"1234567891234567e15gal", "+Inf"},
+		{"-1234567891234567e15mL", "-1234567891234567000000000000000L"},
+	}
+
+	for _, c := range cases {
+		q, err := NewQuantityFromString(c.s, volumes)
+		if err != nil {
+			t.Fatalf(`NewQuantityFromString(%q) should be ok, error = %v`, c.s, err)
+		}
+
+		if r := q.Mul(1000); r.String() != c.want {
+			t.Errorf(`%v * 1000 should be %s and not %v`, q, c.want, r)
+		}
+		if r := q.Add(q.Mul(999)); r.String() != c.want && r.String() != "~"+c.want {
+			t.Errorf(`%v + %v * 999 should be %s and not %v`, q, q, c.want, r)
+		}
+	}
+}
+
+func TestQuantityNilTable(t *testing.T) {
+	if q, err := NewQuantity(1, 0, "L", nil); err != ErrUnitSyntax {
+		t.Errorf(`NewQuantity(1, 0, "L", nil) should return ErrUnitSyntax and not %v (err = %v)`, q, err)
+	}
+	if q, err := NewQuantityFromDecimal(1, "L", nil); err != ErrUnitSyntax {
+		t.Errorf(`NewQuantityFromDecimal(1, "L", nil) should return ErrUnitSyntax and not %v (err = %v)`, q, err)
+	}
+	if q, err := NewQuantityFromString("1L", nil); err != ErrUnitSyntax {
+		t.Errorf(`NewQuantityFromString("1L", nil) should return ErrUnitSyntax and not %v (err = %v)`, q, err)
+	}
+
+	// the zero Quantity has no table, it is Null
+	var q Quantity
+	if q.String() != "0" || !q.IsZero() || q.Unit() != "" {
+		t.Errorf(`the zero Quantity should be 0 without unit and not %v`, q)
+	}
+	if r, err := q.In("L"); err != ErrUnitSyntax {
+		t.Errorf(`the zero Quantity In("L") should return ErrUnitSyntax and not %v (err = %v)`, r, err)
+	}
+	if r := q.Add(q).Mul(2); !r.IsZero() {
+		t.Errorf(`the zero Quantity + itself * 2 should be zero and not %v`, r)
+	}
+
+	// while in arithmetic it takes the table of the other operand
+	table, _ := NewUnitTable(Unit{"L", 1}, Unit{"mL", New(1, -3)})
+	l, _ := NewQuantity(1, 0, "L", table)
+	ml, _ := NewQuantity(250, 0, "mL", table)
+
+	var sum Quantity
+	for _, x := range []Quantity{ml, l, ml} {
+		sum = sum.Add(x)
+	}
+	if sum.String() != "1.5L" || sum.Table() != table {
+		t.Errorf(`the sum of 250mL, 1L and 250mL from the zero Quantity should be 1.5L and not %v`, sum)
+	}
+	if r := ml.Add(q); r.String() != "250mL" || r.Table() != table {
+		t.Errorf(`250mL + the zero Quantity should be 250mL and not %v`, r)
+	}
+	if r := q.Sub(ml); r.String() != "-0.25L" || r.Table() != table {
+		t.Errorf(`the zero Quantity - 250mL should be -0.25L and not %v`, r)
+	}
+	if q.Compare(l) != -1 || l.Compare(q) != 1 || q.Compare(q) != 0 || !q.Equal(l.Sub(l)) {
+		t.Errorf(`the zero Quantity should compare as zero with 1L`)
+	}
+}
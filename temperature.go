@@ -0,0 +1,221 @@
+package decimal
+
+// Temperature represents a fixed-point decimal hold as a 64 bits integer including a temperature unit, in degrees Celsius by default.
+// integer value between -9007199254740991 and 9007199254740991 (or TemperatureMaxInt) can safely be used as Temperature using '°C' unit, example :
+//
+//	var a Temperature = 20 // a is a Temperature of value 20°C
+//
+// Temperature has the same 64 bits representation as Weight and Length: 4 bits are used to encode the unit
+// and its mantissa has 53 bits instead of Decimal mantissa of 57 bits.
+//
+// Unlike other units, temperature scales are affine: converting involves an offset and not only a factor,
+// so a temperature is a point on a scale which can be converted and compared but not added to another one
+// (20°C + 20°C is not 40°C in Fahrenheit or Kelvin). Use ToDecimal to compute temperature differences in a given unit.
+type Temperature int64
+
+const (
+	// TemperatureMaxInt constant is the maximal int64 value that can be safely saved as Temperature with exponent still 0.
+	// TemperatureMaxInt is as well the maximum value of mantissa of Temperature and the bitmask to extract mantissa value of a Temperature.
+	TemperatureMaxInt = quantityMaxInt
+)
+
+var (
+	// the conversion scale is the Fahrenheit one as it is the only one where all the factors and offsets are exact decimals:
+	// a value x of a unit is x * c + o degrees Fahrenheit
+	temperatureUnits = [...]unit{
+		{u: "°C", c: 18 + 31<<decimalBitE /* 1.8 °F */, o: 32, v: 0},
+		{u: "°F", c: 0, v: 1 << quantityBitT},
+		{u: "K", c: 18 + 31<<decimalBitE /* 1.8 °F */, o: -(45967 + 30<<decimalBitE) /* -459.67 °F */, v: 2 << quantityBitT},
+		{u: "°R", c: 0, o: -(45967 + 30<<decimalBitE) /* -459.67 °F */, v: 3 << quantityBitT},
+
+		{}, //  4 is reserved for future use
+		{}, //  5 is reserved for future use
+		{}, //  6 is reserved for future use
+		{}, //  7 is reserved for future use
+		{}, //  8 is reserved for future use
+		{}, //  9 is reserved for future use
+		{}, // 10 is reserved for future use
+		{}, // 11 is reserved for future use
+		{}, // 12 is reserved for future use
+		{}, // 13 is reserved for future use
+		{}, // 14 is reserved for future use
+		{}, // 15 is reserved for future use
+
+		// aliases
+		{u: "℃", c: 18 + 31<<decimalBitE, o: 32, v: 0},
+		{u: "C", c: 18 + 31<<decimalBitE, o: 32, v: 0},
+		{u: "℉", c: 0, v: 1 << quantityBitT},
+		{u: "F", c: 0, v: 1 << quantityBitT},
+	}
+)
+
+// temperatureTable is the unit table of temperatures, an overflowing temperature keeps its unit as the scales are affine
+var temperatureTable = &UnitTable{units: temperatureUnits[:], fixed: true}
+
+func init() {
+	hashUnits(temperatureUnits[:])
+}
+
+// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func (t Temperature) vmet() (v, m uint64, e int64, u *unit) {
+	return temperatureTable.vmet(int64(t))
+}
+
+// internal function to define a decimal from a VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func vmeAsTemperature(v, m uint64, e int64) Temperature {
+	return Temperature(temperatureTable.vmeAs(v, m, e))
+}
+
+// NewTemperature returns a new fixed-point decimal temperature, value * 10 ^ exp using unit.
+func NewTemperature(value int64, exp int32, unit string) (Temperature, error) {
+	t, err := temperatureTable.fromInt(value, exp, unit)
+
+	return Temperature(t), err
+}
+
+// NewTemperatureFromDecimal converts a Decimal to Temperature using unit.
+func NewTemperatureFromDecimal(value Decimal, unit string) (Temperature, error) {
+	t, err := temperatureTable.fromDecimal(value, unit)
+
+	return Temperature(t), err
+}
+
+// NewTemperatureFromBytes returns a new Temperature from a slice of bytes representation.
+//
+// If no temperature unit is given, '°C' is assumed.
+func NewTemperatureFromBytes(value []byte) (Temperature, error) {
+	t, err := temperatureTable.fromBytes(value)
+
+	return Temperature(t), err
+}
+
+// NewTemperatureFromString returns a new Temperature from a string representation.
+//
+// If no temperature unit is given, '°C' is assumed.
+//
+// Example:
+//
+//	t, err := NewTemperatureFromString("21.5")
+//	t2, err := NewTemperatureFromString("100°C")
+//	t3, err := NewTemperatureFromString("-40 °F")
+//	t4, err := NewTemperatureFromString("273.15K")
+func NewTemperatureFromString(value string) (Temperature, error) {
+	return NewTemperatureFromBytes([]byte(value))
+}
+
+// Unit returns unit string of t.
+func (t Temperature) Unit() string {
+	_, _, _, u := t.vmet()
+
+	return u.u
+}
+
+// value returns the scalar value of t in its own unit
+func (t Temperature) value() Decimal {
+	return quantityValue(int64(t))
+}
+
+// In returns t converted to unit, it returns ErrUnitSyntax for an unknown unit.
+// The conversion goes through the offsets of the units, so 0°C is 32°F and 273.15K.
+//
+// Example:
+//
+//	t, _ := NewTemperatureFromString("100°C")
+//	f, _ := t.In("°F") // 212°F
+//	k, _ := t.In("K")  // 373.15K
+func (t Temperature) In(unit string) (Temperature, error) {
+	zero, err := NewTemperature(0, 0, unit)
+	if err != nil {
+		return zero, err
+	}
+
+	v, m, e, u1 := t.vmet()
+	_, _, _, u2 := zero.vmet()
+
+	// a value x in unit u1 is x * c1 + o1 on the conversion scale, which is (x * c1 + o1 - o2) / c2 in unit u2
+	d := vmeAsDecimal(v&^quantityTBitmask, m, e).Mul(u1.factor()).Add(u1.o).Sub(u2.o).Div(u2.factor())
+
+	v, m, e = d.vme()
+
+	return vmeAsTemperature(v|u2.v, m, e), nil
+}
+
+// ToDecimal returns the value of t expressed in unit as a plain Decimal, 373.15 for 100°C in "K".
+// It returns ErrUnitSyntax for an unknown unit.
+func (t Temperature) ToDecimal(unit string) (Decimal, error) {
+	t, err := t.In(unit)
+	if err != nil {
+		return NaN, err
+	}
+
+	return t.value(), nil
+}
+
+// String returns the string representation of the temperature with the fixed point and unit.
+func (t Temperature) String() string {
+	return string(t.BytesTo(nil))
+}
+
+// BytesTo appends the string representation of the temperature to a slice of byte.
+func (t Temperature) BytesTo(b []byte) []byte {
+	return temperatureTable.bytesTo(b, int64(t), true)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t Temperature) MarshalJSON() ([]byte, error) {
+	return temperatureTable.bytesTo(nil, int64(t), false), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Temperature) UnmarshalJSON(b []byte) error {
+	if _t, err := temperatureTable.fromBytes(b); err == nil {
+		*t = Temperature(_t)
+
+		return nil
+	} else {
+		return err
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for XML deserialization.
+func (t *Temperature) UnmarshalText(text []byte) error {
+	if _t, err := NewTemperatureFromBytes(text); err != nil {
+		return err
+	} else {
+		*t = _t
+
+		return nil
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for XML serialization.
+func (t Temperature) MarshalText() (text []byte, err error) {
+	return t.BytesTo(nil), nil
+}
+
+// IsNaN return
+//
+//	true if t is not a number (NaN)
+//	false in any other case
+func (t Temperature) IsNaN() bool {
+	return quantityIsNaN(int64(t))
+}
+
+// Compare compares the temperatures represented by t1 and t2 whatever their units without taking into account lost precision and returns:
+//
+//	-1 if t1 <  t2
+//	 0 if t1 == t2
+//	+1 if t1 >  t2
+func (t1 Temperature) Compare(t2 Temperature) int {
+	t2, _ = t2.In(t1.Unit())
+
+	return t1.value().Compare(t2.value())
+}
+
+// Equal returns whether t1 and t2 are the same temperature whatever their units, 100°C and 212°F are equal.
+// NaN temperatures are never equal.
+func (t1 Temperature) Equal(t2 Temperature) bool {
+	t2, _ = t2.In(t1.Unit())
+
+	return t1.value().Equal(t2.value())
+}
@@ -0,0 +1,115 @@
+package decimal
+
+import (
+	"testing"
+)
+
+func TestTemperatureIn(t *testing.T) {
+	cases := []struct {
+		s, str, unit, want string
+	}{
+		{"100°C", "100°C", "°F", "212°F"},
+		{"100°C", "100°C", "K", "373.15K"},
+		{"0°C", "0°C", "°F", "32°F"},
+		{"0°C", "0°C", "K", "273.15K"},
+		{"-40°C", "-40°C", "°F", "-40°F"},
+		{"212°F", "212°F", "°C", "100°C"},
+		{"32 °F", "32°F", "°C", "0°C"},
+		{"100°F", "100°F", "°C", "~37.77777777777778°C"},
+		{"0K", "0K", "°C", "-273.15°C"},
+		{"0K", "0K", "°F", "-459.67°F"},
+		{"0K", "0K", "°R", "0°R"},
+		{"491.67°R", "491.67°R", "°C", "0°C"},
+		{"373.15K", "373.15K", "°C", "100°C"},
+		{"37", "37°C", "°F", "98.6°F"},
+		{"20℃", "20°C", "°C", "20°C"},
+		{"68F", "68°F", "°C", "20°C"},
+		{"36.6 C", "36.6°C", "K", "309.75K"},
+	}
+
+	for _, c := range cases {
+		tp, err := NewTemperatureFromString(c.s)
+		if err != nil {
+			t.Errorf(`NewTemperatureFromString(%q) has result = %v and error = %v`, c.s, tp, err)
+			continue
+		}
+
+		if tp.String() != c.str {
+			t.Errorf(`NewTemperatureFromString(%q) should be %s and not %v`, c.s, c.str, tp)
+		}
+		if r, err := tp.In(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.In(%q) should be %s and not %v (err = %v)`, tp, c.unit, c.want, r, err)
+		} else if !r.Equal(tp) || r.Compare(tp) != 0 {
+			t.Errorf(`%v should be equal to %v`, r, tp)
+		}
+	}
+
+	for _, s := range []string{"1kg", "10°X", "1 degree"} {
+		if tp, err := NewTemperatureFromString(s); err == nil {
+			t.Errorf(`NewTemperatureFromString(%q) should return an error and not %v`, s, tp)
+		}
+	}
+
+	tp, _ := NewTemperatureFromString("20°C")
+	if _, err := tp.In("°X"); err != ErrUnitSyntax {
+		t.Errorf(`%v.In("°X") should return ErrUnitSyntax and not %v`, tp, err)
+	}
+	if d, err := tp.ToDecimal("K"); err != nil || !d.Equal(New(29315, -2)) {
+		t.Errorf(`%v.ToDecimal("K") should be 293.15 and not %v (err = %v)`, tp, d, err)
+	}
+}
+
+func TestTemperatureCompare(t *testing.T) {
+	cases := []struct {
+		t1, t2 string
+		want   int
+	}{
+		{"100°C", "212°F", 0},
+		{"100°C", "211°F", 1},
+		{"0°C", "273.15K", 0},
+		{"0°C", "273K", 1},
+		{"-40°F", "-40°C", 0},
+		{"20°C", "70°F", -1},
+		{"300K", "26°C", 1},
+	}
+
+	for _, c := range cases {
+		t1, _ := NewTemperatureFromString(c.t1)
+		t2, _ := NewTemperatureFromString(c.t2)
+
+		if r := t1.Compare(t2); r != c.want {
+			t.Errorf(`%v.Compare(%v) should be %d and not %d`, t1, t2, c.want, r)
+		}
+		if r := t2.Compare(t1); r != -c.want {
+			t.Errorf(`%v.Compare(%v) should be %d and not %d`, t2, t1, -c.want, r)
+		}
+		if eq := t1.Equal(t2); eq != (c.want == 0) {
+			t.Errorf(`%v.Equal(%v) should be %t and not %t`, t1, t2, c.want == 0, eq)
+		}
+	}
+
+	nan, _ := NewTemperatureFromString("NaN")
+	if !nan.IsNaN() || nan.Equal(nan) {
+		t.Errorf(`NaN temperature should be NaN and never equal`)
+	}
+}
+
+func TestTemperatureMarshal(t *testing.T) {
+	for _, str := range []string{"21.5°C", "-40°F", "273.15K", "0°R", "0°F"} {
+		tp, _ := NewTemperatureFromString(str)
+
+		var r Temperature
+		if b, err := tp.MarshalText(); err != nil || string(b) != str {
+			t.Errorf(`%v.MarshalText() should be %s and not %s (err = %v)`, tp, str, b, err)
+		} else if err := r.UnmarshalText(b); err != nil || r != tp {
+			t.Errorf(`UnmarshalText(%s) should be %v and not %v (err = %v)`, b, tp, r, err)
+		}
+
+		r = 0
+		if b, err := tp.MarshalJSON(); err != nil {
+			t.Errorf(`%v.MarshalJSON() should be ok, error = %v`, tp, err)
+		} else if err := r.UnmarshalJSON(b); err != nil || r != tp {
+			t.Errorf(`UnmarshalJSON(%s) should be %v and not %v (err = %v)`, b, tp, r, err)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package decimal
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// TimeSpan represents a fixed-point decimal hold as a 64 bits integer including a time unit, in seconds.
+// integer value between -9007199254740991 and 9007199254740991 (or TimeSpanMaxInt) can safely be used as TimeSpan using 's' unit, example :
+//
+//	var a TimeSpan = 90 // a is a TimeSpan of value 90s
+//
+// Note 0 is unitialized TimeSpan and its value for calculation is 0.
+// Note you need to use TimeSpan method for calculation, you cannot use + - * / or any other operators unless TimeSpan is a real non-zero integer value with 's' unit.
+//
+// TimeSpan has the same 64 bits representation as Weight and Length: 4 bits are used to encode the unit
+// and its mantissa has 53 bits instead of Decimal mantissa of 57 bits.
+// Unlike time.Duration, it is meant for human readable quantities such as "1.5h" or "90min" without loss.
+type TimeSpan int64
+
+// Duration is an alias of TimeSpan, a decimal duration with a time unit (ns, µs, ms, s, min, h or d)
+// which converts from and to time.Duration with NewDurationFromTimeDuration and TimeDuration.
+type Duration = TimeSpan
+
+const (
+	// TimeSpanMaxInt constant is the maximal int64 value that can be safely saved as TimeSpan with exponent still 0.
+	// TimeSpanMaxInt is as well the maximum value of mantissa of TimeSpan and the bitmask to extract mantissa value of a TimeSpan.
+	TimeSpanMaxInt = quantityMaxInt
+)
+
+var (
+	timeSpanUnits = [...]unit{
+		// International System of Units where 's' is the base unit
+		{u: "s", c: 0, v: 0},
+		{u: "ms", c: -3, v: 1 << quantityBitT},
+		{u: "µs", c: -6, v: 2 << quantityBitT},
+		{u: "ns", c: -9, v: 3 << quantityBitT},
+
+		{}, //  4 is reserved for future use
+		{}, //  5 is reserved for future use
+		{}, //  6 is reserved for future use
+		{}, //  7 is reserved for future use
+
+		// non-SI units accepted for use with the SI, the factor is not a power of ten so it is stored with a non-zero exponent
+		{u: "min", c: 600 + 31<<decimalBitE /* 60 s */, v: 8 << quantityBitT},
+		{u: "h", c: 36000 + 31<<decimalBitE /* 3600 s */, v: 9 << quantityBitT},
+		{u: "d", c: 864000 + 31<<decimalBitE /* 86400 s */, v: 10 << quantityBitT},
+
+		{}, // 11 is reserved for future use
+		{}, // 12 is reserved for future use
+		{}, // 13 is reserved for future use
+		{}, // 14 is reserved for future use
+		{}, // 15 is reserved for future use
+
+		// aliases
+		{u: "us", c: -6, v: 2 << quantityBitT},
+	}
+)
+
+// timeSpanTable is the unit table of time spans, an overflowing time span keeps its unit
+var timeSpanTable = &UnitTable{units: timeSpanUnits[:], fixed: true}
+
+func init() {
+	hashUnits(timeSpanUnits[:])
+}
+
+// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func (s TimeSpan) vmet() (v, m uint64, e int64, t *unit) {
+	return timeSpanTable.vmet(int64(s))
+}
+
+// NewTimeSpan returns a new fixed-point decimal time span, value * 10 ^ exp using unit.
+func NewTimeSpan(value int64, exp int32, unit string) (TimeSpan, error) {
+	s, err := timeSpanTable.fromInt(value, exp, unit)
+
+	return TimeSpan(s), err
+}
+
+// NewTimeSpanFromDecimal converts a Decimal to TimeSpan using unit.
+func NewTimeSpanFromDecimal(value Decimal, unit string) (TimeSpan, error) {
+	s, err := timeSpanTable.fromDecimal(value, unit)
+
+	return TimeSpan(s), err
+}
+
+// NewTimeSpanFromBytes returns a new TimeSpan from a slice of bytes representation.
+//
+// If no time unit is given, 's' is assumed.
+func NewTimeSpanFromBytes(value []byte) (TimeSpan, error) {
+	s, err := timeSpanTable.fromBytes(value)
+
+	return TimeSpan(s), err
+}
+
+// NewTimeSpanFromString returns a new TimeSpan from a string representation.
+//
+// If no time unit is given, 's' is assumed.
+//
+// Example:
+//
+//	s, err := NewTimeSpanFromString("90")
+//	s2, err := NewTimeSpanFromString("1.5h")
+//	s3, err := NewTimeSpanFromString("250 ms")
+func NewTimeSpanFromString(value string) (TimeSpan, error) {
+	return NewTimeSpanFromBytes([]byte(value))
+}
+
+// NewDuration returns a new fixed-point decimal duration, value * 10 ^ exp using unit, like NewTimeSpan does.
+func NewDuration(value int64, exp int32, unit string) (Duration, error) {
+	return NewTimeSpan(value, exp, unit)
+}
+
+// NewDurationFromString returns a new Duration from a string representation, like NewTimeSpanFromString does.
+//
+// Example:
+//
+//	d, err := NewDurationFromString("90min")
+//	println(d.In("h")) // 1.5h
+func NewDurationFromString(value string) (Duration, error) {
+	return NewTimeSpanFromString(value)
+}
+
+// Unit returns unit string of s.
+func (s TimeSpan) Unit() string {
+	_, _, _, t := s.vmet()
+
+	return t.u
+}
+
+// NewTimeSpanFromDuration returns the TimeSpan of a time.Duration, expressed exactly in the largest unit among h, min, s, ms, µs and ns
+// it is a whole number of, so that 90 * time.Minute is 90min and 1500 * time.Millisecond is 1500ms.
+// Note a count of more than 9007199254740991 (TimeSpanMaxInt) units, e.g. more than about 104 days of nanoseconds, is rounded.
+func NewTimeSpanFromDuration(d time.Duration) TimeSpan {
+	units := [...]struct {
+		d time.Duration
+		u string
+	}{
+		{time.Hour, "h"}, {time.Minute, "min"}, {time.Second, "s"}, {time.Millisecond, "ms"}, {time.Microsecond, "µs"},
+	}
+
+	if d != 0 {
+		for _, u := range units {
+			if d%u.d == 0 {
+				s, _ := NewTimeSpan(int64(d/u.d), 0, u.u)
+
+				return s
+			}
+		}
+	}
+
+	s, _ := NewTimeSpan(int64(d), 0, "ns")
+
+	return s
+}
+
+// Duration returns s as a time.Duration, rounded to the nearest nanosecond.
+// It returns ErrOutOfRange for an infinite or not-a-number time span or one which overflows an int64 count of nanoseconds (about 292 years).
+func (s TimeSpan) Duration() (time.Duration, error) {
+	ns, _ := s.In("ns")
+	v, m, e, _ := ns.vmet()
+
+	if m == 0 {
+		if v&loss != 0 && e != 0 && e != math.MinInt64 {
+			return 0, ErrOutOfRange
+		}
+
+		return 0, nil
+	}
+
+	if e < 0 {
+		p := tenPow[-e]
+		q, r := m/p, m%p
+		if r<<1 >= p {
+			q++
+		}
+		m = q
+	} else if e > 0 {
+		hi, lo := bits.Mul64(m, tenPow[e])
+		if hi != 0 {
+			return 0, ErrOutOfRange
+		}
+		m = lo
+	}
+
+	if v&sign != 0 {
+		if m > 1<<63 {
+			return 0, ErrOutOfRange
+		}
+
+		return time.Duration(-m), nil
+	}
+
+	if m > math.MaxInt64 {
+		return 0, ErrOutOfRange
+	}
+
+	return time.Duration(m), nil
+}
+
+// NewDurationFromTimeDuration returns the Duration of a time.Duration, like NewTimeSpanFromDuration does.
+func NewDurationFromTimeDuration(d time.Duration) Duration {
+	return NewTimeSpanFromDuration(d)
+}
+
+// TimeDuration returns s as a time.Duration like Duration does, it returns ErrOutOfRange when it overflows an int64 count of nanoseconds.
+func (s TimeSpan) TimeDuration() (time.Duration, error) {
+	return s.Duration()
+}
+
+// Seconds returns the number of seconds of s as a Decimal, 1.5min giving 90.
+func (s TimeSpan) Seconds() Decimal {
+	r, _ := s.In("s")
+
+	return r.value()
+}
+
+// value returns the scalar value of s in its own unit
+func (s TimeSpan) value() Decimal {
+	return quantityValue(int64(s))
+}
+
+// In returns s converted to unit, it returns ErrUnitSyntax for an unknown unit.
+//
+// Example:
+//
+//	s, err := NewTimeSpanFromString("90min")
+//	println(s.In("h"))
+//	println(s.In("s"))
+//
+// Output:
+//
+//	1.5h
+//	5400s
+func (s TimeSpan) In(unit string) (TimeSpan, error) {
+	r, err := timeSpanTable.in(int64(s), unit)
+
+	return TimeSpan(r), err
+}
+
+// To returns s converted to unit like In does.
+func (s TimeSpan) To(unit string) (TimeSpan, error) {
+	return s.In(unit)
+}
+
+// Add returns s1 + s2 using s1 unit.
+func (s1 TimeSpan) Add(s2 TimeSpan) TimeSpan {
+	return TimeSpan(timeSpanTable.add(int64(s1), int64(s2)))
+}
+
+// Sub returns s1 - s2 using s1 unit.
+func (s1 TimeSpan) Sub(s2 TimeSpan) TimeSpan {
+	return s1.Add(-s2)
+}
+
+// Mul returns s * d using s unit.
+func (s TimeSpan) Mul(d Decimal) TimeSpan {
+	return TimeSpan(timeSpanTable.mul(int64(s), d))
+}
+
+// String returns the string representation of the time span with the fixed point and unit.
+func (s TimeSpan) String() string {
+	return string(s.BytesTo(nil))
+}
+
+// BytesTo appends the string representation of the time span to a slice of byte.
+func (s TimeSpan) BytesTo(b []byte) []byte {
+	return timeSpanTable.bytesTo(b, int64(s), true)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s TimeSpan) MarshalJSON() ([]byte, error) {
+	return timeSpanTable.bytesTo(nil, int64(s), false), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *TimeSpan) UnmarshalJSON(b []byte) error {
+	if _s, err := timeSpanTable.fromBytes(b); err == nil {
+		*s = TimeSpan(_s)
+
+		return nil
+	} else {
+		return err
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for XML deserialization.
+func (s *TimeSpan) UnmarshalText(text []byte) error {
+	if _s, err := NewTimeSpanFromBytes(text); err != nil {
+		return err
+	} else {
+		*s = _s
+
+		return nil
+	}
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for XML serialization.
+func (s TimeSpan) MarshalText() (text []byte, err error) {
+	return s.BytesTo(nil), nil
+}
+
+// IsZero return
+//
+//	true if s == Null or s == Zero
+//	true if s == ~0 or s == -~0 or s == +~0
+//	false if s < 0
+//	false if s > 0
+func (s TimeSpan) IsZero() bool {
+	return quantityIsZero(int64(s))
+}
+
+// IsPositive return
+//
+//	true if s > 0 or s == ~+0
+//	false if s == Null or s == Zero or s == ~0
+//	false if s < 0 or s == ~-0
+//	false if s is NaN
+func (s TimeSpan) IsPositive() bool {
+	return s > 0 && !s.IsNaN()
+}
+
+// IsNaN return
+//
+//	true if s is not a number (NaN)
+//	false in any other case
+func (s TimeSpan) IsNaN() bool {
+	return quantityIsNaN(int64(s))
+}
+
+// Compare compares the time spans represented by s1 and s2 whatever their units without taking into account lost precision and returns:
+//
+//	-1 if s1 <  s2
+//	 0 if s1 == s2
+//	+1 if s1 >  s2
+func (s1 TimeSpan) Compare(s2 TimeSpan) int {
+	return timeSpanTable.compare(int64(s1), int64(s2))
+}
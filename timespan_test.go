@@ -0,0 +1,221 @@
+package decimal
+
+import (
+	"testing"
+
+	"time"
+)
+
+func TestTimeSpanConversions(t *testing.T) {
+	cases := []struct {
+		s       string
+		str     string
+		seconds Decimal
+	}{
+		{"90min", "90min", 5400},
+		{"1.5h", "1.5h", 5400},
+		{"2d", "2d", 172800},
+		{"0.5d", "0.5d", 43200},
+		{"250ms", "250ms", New(25, -2)},
+		{"250 ms", "250ms", New(25, -2)},
+		{"10us", "10µs", New(1, -5)},
+		{"10µs", "10µs", New(1, -5)},
+		{"1ns", "1ns", New(1, -9)},
+		{"90", "90s", 90},
+		{"-15min", "-15min", -900},
+		{"1e3ms", "1000ms", 1},
+		{"0h", "0h", Zero},
+	}
+
+	for _, c := range cases {
+		s, err := NewTimeSpanFromString(c.s)
+		if err != nil {
+			t.Errorf(`NewTimeSpanFromString(%q) has result = %v and error = %v`, c.s, s, err)
+			continue
+		}
+
+		if s.String() != c.str {
+			t.Errorf(`NewTimeSpanFromString(%q) should be %s and not %v`, c.s, c.str, s)
+		}
+		if sec := s.Seconds(); !sec.Equal(c.seconds) {
+			t.Errorf(`%v.Seconds() should be %v and not %v`, s, c.seconds, sec)
+		}
+
+		// String round-trips
+		if r, err := NewTimeSpanFromString(s.String()); err != nil || r != s {
+			t.Errorf(`NewTimeSpanFromString(%q) should be %v and not %v (err = %v)`, s.String(), s, r, err)
+		}
+	}
+
+	for _, s := range []string{"1y", "1m", "1.5 hours", "1kg", "1hmin"} {
+		if d, err := NewTimeSpanFromString(s); err == nil {
+			t.Errorf(`NewTimeSpanFromString(%q) should return an error and not %v`, s, d)
+		}
+	}
+}
+
+func TestTimeSpanTo(t *testing.T) {
+	cases := []struct {
+		s, unit, want string
+	}{
+		{"90min", "h", "1.5h"},
+		{"1.5h", "min", "90min"},
+		{"45min", "h", "0.75h"},
+		{"20min", "h", "~0.3333333333333333h"},
+		{"1h", "s", "3600s"},
+		{"1d", "h", "24h"},
+		{"36h", "d", "1.5d"},
+		{"1500ms", "s", "1.5s"},
+		{"1s", "ns", "1000000000ns"},
+		{"1min", "ms", "60000ms"},
+		{"2.5us", "ns", "2500ns"},
+	}
+
+	for _, c := range cases {
+		s, _ := NewTimeSpanFromString(c.s)
+
+		if r, err := s.In(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.In(%q) should be %s and not %v (err = %v)`, s, c.unit, c.want, r, err)
+		} else if r.Compare(s) != 0 {
+			t.Errorf(`%v.Compare(%v) should be 0 and not %d`, r, s, r.Compare(s))
+		}
+		if r, err := s.To(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.To(%q) should be %s and not %v (err = %v)`, s, c.unit, c.want, r, err)
+		}
+	}
+
+	s, _ := NewTimeSpanFromString("1h")
+	if _, err := s.To("hours"); err != ErrUnitSyntax {
+		t.Errorf(`%v.To("hours") should return ErrUnitSyntax and not %v`, s, err)
+	}
+
+	// arithmetic keeps the unit of the first operand
+	m, _ := NewTimeSpanFromString("30min")
+	if r := s.Add(m); r.String() != "1.5h" {
+		t.Errorf(`1h + 30min should be 1.5h and not %v`, r)
+	}
+	if r := m.Sub(s).Mul(2); r.String() != "-60min" {
+		t.Errorf(`(30min - 1h) * 2 should be -60min and not %v`, r)
+	}
+	if s.Compare(m) != 1 || m.Compare(s) != -1 {
+		t.Errorf(`1h should be greater than 30min`)
+	}
+}
+
+func TestTimeSpanMarshal(t *testing.T) {
+	for _, str := range []string{"1.5h", "-90min", "250ms", "10µs", "0d"} {
+		s, _ := NewTimeSpanFromString(str)
+
+		var r TimeSpan
+		if b, err := s.MarshalText(); err != nil || string(b) != str {
+			t.Errorf(`%v.MarshalText() should be %s and not %s (err = %v)`, s, str, b, err)
+		} else if err := r.UnmarshalText(b); err != nil || r != s {
+			t.Errorf(`UnmarshalText(%s) should be %v and not %v (err = %v)`, b, s, r, err)
+		}
+
+		r = 0
+		if b, err := s.MarshalJSON(); err != nil {
+			t.Errorf(`%v.MarshalJSON() should be ok, error = %v`, s, err)
+		} else if err := r.UnmarshalJSON(b); err != nil || r != s {
+			t.Errorf(`UnmarshalJSON(%s) should be %v and not %v (err = %v)`, b, s, r, err)
+		}
+	}
+}
+
+func TestTimeSpanDuration(t *testing.T) {
+	cases := []struct {
+		d   time.Duration
+		str string
+	}{
+		{90 * time.Minute, "90min"},
+		{2 * time.Hour, "2h"},
+		{1500 * time.Millisecond, "1500ms"},
+		{-45 * time.Second, "-45s"},
+		{10 * time.Microsecond, "10µs"},
+		{1234567 * time.Nanosecond, "1234567ns"},
+		{0, "0ns"},
+	}
+
+	for _, c := range cases {
+		s := NewTimeSpanFromDuration(c.d)
+		if s.String() != c.str {
+			t.Errorf(`NewTimeSpanFromDuration(%v) should be %s and not %v`, c.d, c.str, s)
+		}
+
+		if d, err := s.Duration(); err != nil || d != c.d {
+			t.Errorf(`%v.Duration() should be %v and not %v (err = %v)`, s, c.d, d, err)
+		}
+	}
+
+	conversions := []struct {
+		s string
+		d time.Duration
+	}{
+		{"90min", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"0.5d", 12 * time.Hour},
+		{"2.5ns", 3 * time.Nanosecond},
+		{"-2.5ns", -3 * time.Nanosecond},
+		{"1.25µs", 1250 * time.Nanosecond},
+		{"0.1s", 100 * time.Millisecond},
+		{"100000d", 100000 * 24 * time.Hour},
+	}
+
+	for _, c := range conversions {
+		s, _ := NewTimeSpanFromString(c.s)
+
+		if d, err := s.Duration(); err != nil || d != c.d {
+			t.Errorf(`%v.Duration() should be %v and not %v (err = %v)`, s, c.d, d, err)
+		}
+	}
+
+	for _, str := range []string{"200000d", "-200000d", "+Inf", "NaN", "1e15h"} {
+		s, _ := NewTimeSpanFromString(str)
+
+		if d, err := s.Duration(); err != ErrOutOfRange {
+			t.Errorf(`%v.Duration() should return ErrOutOfRange and not %v (err = %v)`, s, d, err)
+		}
+	}
+
+	// arithmetic on time spans converts back to a time.Duration
+	s := NewTimeSpanFromDuration(90 * time.Minute)
+	if h, _ := s.To("h"); h.String() != "1.5h" {
+		t.Errorf(`%v.To("h") should be 1.5h and not %v`, s, h)
+	}
+	if d, err := s.Add(NewTimeSpanFromDuration(30 * time.Second)).Duration(); err != nil || d != 90*time.Minute+30*time.Second {
+		t.Errorf(`90min + 30s should be 1h30m30s and not %v (err = %v)`, d, err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	d, err := NewDurationFromString("90min")
+	if err != nil {
+		t.Fatalf(`NewDurationFromString("90min") should not error (err = %v)`, err)
+	}
+	if h, err := d.In("h"); err != nil || h.String() != "1.5h" {
+		t.Errorf(`%v.In("h") should be 1.5h and not %v (err = %v)`, d, h, err)
+	}
+
+	// arithmetic keeps the left unit and converts the right one
+	sum, _ := NewDuration(3600, 0, "s")
+	sum = sum.Add(d).Sub(NewDurationFromTimeDuration(30 * time.Second))
+	if sum.String() != "8970s" {
+		t.Errorf(`3600s + 90min - 30s should be 8970s and not %v`, sum)
+	}
+	if td, err := sum.TimeDuration(); err != nil || td != 2*time.Hour+29*time.Minute+30*time.Second {
+		t.Errorf(`%v.TimeDuration() should be 2h29m30s and not %v (err = %v)`, sum, td, err)
+	}
+
+	for _, c := range []time.Duration{0, time.Nanosecond, -1500 * time.Millisecond, 36 * time.Hour, 1<<53 - 1} {
+		if td, err := NewDurationFromTimeDuration(c).TimeDuration(); err != nil || td != c {
+			t.Errorf(`NewDurationFromTimeDuration(%v).TimeDuration() should be %v and not %v (err = %v)`, c, c, td, err)
+		}
+	}
+
+	for _, str := range []string{"200000d", "-1e7h", "NaN"} {
+		d, _ := NewDurationFromString(str)
+		if td, err := d.TimeDuration(); err != ErrOutOfRange {
+			t.Errorf(`%v.TimeDuration() should return ErrOutOfRange and not %v (err = %v)`, d, td, err)
+		}
+	}
+}
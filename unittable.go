@@ -0,0 +1,363 @@
+package decimal
+
+import (
+	"math"
+)
+
+// The types with a unit (Weight, Length, DataSize, TimeSpan, Temperature and Quantity) share the same 64 bits layout:
+// the one of Decimal except 4 bits of the mantissa are used to encode a unit code, the index of the unit in a UnitTable.
+const (
+	quantityMaxInt   = 0x001fffffffffffff
+	quantityMinE     = -16
+	quantityMaxE     = 15
+	quantityBitE     = 57
+	quantityEBitmask = 0x3e00000000000000
+	quantityBitT     = 53
+	quantityTBitmask = 0x01e0000000000000
+)
+
+// Unit describes a unit of a UnitTable: its symbol and its conversion factor, the number of base units in one unit.
+type Unit struct {
+	Symbol string
+	Factor Decimal
+}
+
+// UnitTable is a table of up to 16 units of the same physical quantity, the first one being the base unit.
+// The built-in tables of Weight, Length, DataSize, TimeSpan and Temperature are unit tables too, Quantity uses one built by NewUnitTable.
+type UnitTable struct {
+	units []unit
+
+	// fixed is set when an overflowing value keeps its unit and becomes infinite, instead of being promoted to a coarser unit of the table
+	fixed bool
+}
+
+// noUnit is the unit of a value without table
+var noUnit unit
+
+// NewUnitTable returns a new UnitTable from 1 to 16 units, the first one being the base unit whose factor should be 1.
+// A factor which is a power of ten is stored as an exponent, so that conversions between such units are exact.
+// The units are validated like RegisterUnit does.
+//
+// Example:
+//
+//	volumes, err := NewUnitTable(Unit{"L", 1}, Unit{"mL", New(1, -3)}, Unit{"gal", New(3785411784, -9)})
+func NewUnitTable(units ...Unit) (*UnitTable, error) {
+	if len(units) == 0 {
+		return nil, ErrOutOfRange
+	}
+
+	table := &UnitTable{units: make([]unit, 0, len(units))}
+
+	for _, u := range units {
+		if err := RegisterUnit(table, u.Symbol, u.Factor); err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+// RegisterUnit appends a unit of symbol to table, factor being the number of base units in one unit.
+// It returns ErrUnitSyntax for an empty symbol or a symbol already in table and ErrOutOfRange for a factor which is not positive and finite
+// or has too many digits, or when table has already 16 units, the maximum a 4 bits unit code can index.
+//
+// Note RegisterUnit is not safe for concurrent use: units must be registered before the table is used to parse or convert quantities concurrently,
+// typically at program initialization.
+//
+// Example:
+//
+//	volumes, _ := NewUnitTable(Unit{"L", 1})
+//	err := RegisterUnit(volumes, "bu", New(3523907016688, -11)) // US bushel
+func RegisterUnit(table *UnitTable, symbol string, factor Decimal) error {
+	i := len(table.units)
+	if i > quantityTBitmask>>quantityBitT {
+		return ErrOutOfRange
+	}
+
+	h := unitHash(symbol)
+	if h == 0 {
+		return ErrUnitSyntax
+	}
+	for j := range table.units {
+		if table.units[j].h == h {
+			return ErrUnitSyntax
+		}
+	}
+
+	if !factor.IsPositive() || factor.IsInfinite() || !factor.IsExact() {
+		return ErrOutOfRange
+	}
+
+	_, m, e := factor.vme()
+	for m%10 == 0 {
+		m /= 10
+		e++
+	}
+
+	var c Decimal
+	if m == 1 {
+		// an integer conversion factor is the power of ten of the factor
+		c = Decimal(e)
+	} else {
+		// a non integer conversion factor needs a non zero exponent to be told apart
+		if e == 0 {
+			if m > MaxInt/10 {
+				return ErrOutOfRange
+			}
+			m, e = m*10, -1
+		}
+		if e < decimalMinE || e > decimalMaxE {
+			return ErrOutOfRange
+		}
+		c = Decimal(m | uint64(e<<decimalBitE)&decimalEBitmask)
+	}
+
+	table.units = append(table.units, unit{u: symbol, v: uint64(i) << quantityBitT, h: h, c: c})
+
+	return nil
+}
+
+// Units returns the list of unit symbols of the table, base unit first.
+func (table *UnitTable) Units() []string {
+	units := make([]string, len(table.units))
+	for i := range table.units {
+		units[i] = table.units[i].u
+	}
+
+	return units
+}
+
+// internal function to extract a value of the layout with a unit into VME tuple : Value of sign, loss and unit code, Mantissa and Exponent
+func quantityVme(q int64) (v, m uint64, e int64) {
+	var u uint64
+
+	if q < 0 {
+		u = uint64(-q)
+		v = (u & loss) | sign
+	} else {
+		u = uint64(q)
+		v = u & loss
+	}
+
+	e = int64((u&quantityEBitmask)<<2) >> (2 + quantityBitE) // e is now fully signed exponent
+
+	m = u & quantityMaxInt
+
+	v |= u & quantityTBitmask // v keep unit
+
+	// take care of special number
+	if m == 0 {
+		if e == quantityMinE {
+			e = math.MinInt64
+		} else if e == quantityMaxE {
+			e = math.MaxInt64
+		}
+	}
+
+	return
+}
+
+// vmet is quantityVme which also returns the unit of q in table, a nil table having a single unit without symbol
+func (table *UnitTable) vmet(q int64) (v, m uint64, e int64, t *unit) {
+	v, m, e = quantityVme(q)
+
+	if table == nil {
+		t = &noUnit
+	} else {
+		t = &table.units[(v&quantityTBitmask)>>quantityBitT]
+	}
+
+	return
+}
+
+// vmeAs returns the value of the layout with a unit of a VME tuple whose unit code indexes table,
+// an overflowing value being promoted to a coarser unit of table unless it is fixed
+func (table *UnitTable) vmeAs(v, m uint64, e int64) int64 {
+	// handle special case for null and zero
+	if m == 0 && v&loss == 0 {
+		if v == 0 && e == 0 {
+			return Null
+		} else {
+			if v&quantityTBitmask == 0 {
+				return math.MinInt64
+			} else {
+				return int64(v & quantityTBitmask)
+			}
+		}
+	} else {
+		vn, mn, en := vmeNormalize(v, m, e, quantityMaxInt, quantityMinE, quantityMaxE)
+
+		// rather than becoming infinite, an overflowing value of a unit is expressed in a coarser unit
+		if mn == 0 && m != 0 && en == quantityMaxE && table != nil && !table.fixed {
+			vn, mn, en = table.promote(v, m, e, vn, mn, en)
+		}
+		v, m, e = vn, mn, en
+
+		// infinities and not-a-number have no unit, it is dropped so that they parse back from their string to themselves
+		if m == 0 && e != 0 && e != quantityMinE {
+			v &^= quantityTBitmask
+		}
+
+		v |= m | uint64(e<<quantityBitE)&quantityEBitmask
+
+		if v&sign != 0 {
+			return -int64(v ^ sign)
+		} else {
+			return int64(v)
+		}
+	}
+}
+
+// promote returns the normalized VME tuple of (v, m, e) in the finest unit of table coarser than its own by a power of ten
+// in which it does not overflow, or the infinite tuple (vi, mi, ei) if there is none, as for a unit such as lb whose conversion would not be exact
+func (table *UnitTable) promote(v, m uint64, e int64, vi, mi uint64, ei int64) (uint64, uint64, int64) {
+	units := table.units
+
+	i := int((v & quantityTBitmask) >> quantityBitT)
+	if i >= len(units) || !units[i].c.IsInteger() {
+		return vi, mi, ei
+	}
+	c := units[i].c.Int64()
+
+	if len(units) > quantityTBitmask>>quantityBitT+1 {
+		// aliases follow the unit codes
+		units = units[:quantityTBitmask>>quantityBitT+1]
+	}
+
+	best := int64(math.MaxInt64)
+	for i := range units {
+		u := &units[i]
+		if u.u == "" || !u.c.IsInteger() || u.c.Int64() <= c || u.c.Int64() >= best {
+			continue
+		}
+
+		if vp, mp, ep := vmeNormalize(v&^quantityTBitmask|u.v, m, e-(u.c.Int64()-c), quantityMaxInt, quantityMinE, quantityMaxE); mp != 0 {
+			best = u.c.Int64()
+			vi, mi, ei = vp, mp, ep
+		}
+	}
+
+	return vi, mi, ei
+}
+
+// fromInt returns the value of the layout with a unit of value * 10 ^ exp using unit of table
+func (table *UnitTable) fromInt(value int64, exp int32, unit string) (int64, error) {
+	var v, m uint64
+	var e int64
+
+	if value <= 0 {
+		v, m, e = sign, uint64(-value), int64(exp)
+	} else {
+		v, m, e = 0, uint64(value), int64(exp)
+	}
+
+	v, m, e, err := vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, table.units)
+
+	return table.vmeAs(v, m, e), err
+}
+
+// fromDecimal returns the value of the layout with a unit of value using unit of table
+func (table *UnitTable) fromDecimal(value Decimal, unit string) (int64, error) {
+	v, m, e := value.vme()
+
+	v, m, e, err := vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, table.units)
+
+	return table.vmeAs(v, m, e), err
+}
+
+// fromBytes returns the value of the layout with a unit parsed from b with the units of table, the base unit being assumed when there is none
+func (table *UnitTable) fromBytes(b []byte) (int64, error) {
+	if v, m, e, err := vmeFromBytes(b, table.units); err == nil {
+		return table.vmeAs(v, m, e), nil
+	} else {
+		return Null, err
+	}
+}
+
+// in returns q converted to unit of table, it returns ErrUnitSyntax for an unknown unit.
+func (table *UnitTable) in(q int64, unit string) (int64, error) {
+	// adding q to a zero value in unit converts q to unit
+	z, err := table.fromInt(0, 0, unit)
+	if err != nil {
+		return Null, err
+	}
+
+	return table.add(z, q), nil
+}
+
+// add returns q1 + q2 using q1 unit, q2 being converted through the conversion factors of the units of table
+func (table *UnitTable) add(q1, q2 int64) int64 {
+	v1, m1, e1, t1 := table.vmet(q1)
+	v2, m2, e2, t2 := table.vmet(q2)
+
+	v2, m2, e2 = vmeConvert(v2, m2, e2, t2, t1)
+
+	return table.vmeAs(vmeAdd(v1, m1, e1, v2, m2, e2))
+}
+
+// mul returns q * d using q unit
+func (table *UnitTable) mul(q int64, d Decimal) int64 {
+	v1, m1, e1 := quantityVme(q)
+	v2, m2, e2 := d.vme()
+
+	return table.vmeAs(vmeMul(v1, m1, e1, v2, m2, e2))
+}
+
+// div returns q / d using q unit, rounded to DivisionPrecision digits after the decimal point
+func (table *UnitTable) div(q int64, d Decimal) int64 {
+	v1, m1, e1 := quantityVme(q)
+	v2, m2, e2 := d.vme()
+
+	return table.vmeAs(vmeDiv(v1, m1, e1, v2, m2, e2, int32(DivisionPrecision), quantityMaxInt, quantityMinE))
+}
+
+// compare returns the sign of q1 - q2, -1 if it is NaN
+func (table *UnitTable) compare(q1, q2 int64) int {
+	q := table.add(q1, -q2)
+
+	if quantityIsZero(q) {
+		return 0
+	} else if q > 0 && !quantityIsNaN(q) {
+		return 1
+	} else {
+		return -1
+	}
+}
+
+// bytesTo appends the string representation of q followed by its unit of table to b, a JSON one when ext is false
+func (table *UnitTable) bytesTo(b []byte, q int64, ext bool) []byte {
+	v, m, e, t := table.vmet(q)
+
+	return vmetBytesTo(b, v, m, e, 0, t, ext, false)
+}
+
+// quantityValue returns the scalar value of q in its own unit
+func quantityValue(q int64) Decimal {
+	v, m, e := quantityVme(q)
+
+	return vmeAsDecimal(v&^quantityTBitmask, m, e)
+}
+
+// quantityNeg returns -q keeping q unit, zero (whatever its unit) and near zero values are returned unchanged like Decimal.Neg does
+func quantityNeg(q int64) int64 {
+	if v, m, e := quantityVme(q); m == 0 && (v&loss == 0 || e == 0) {
+		return q
+	}
+
+	return -q
+}
+
+// quantityIsZero returns whether q is zero or near zero, whatever its unit
+func quantityIsZero(q int64) bool {
+	// the unit bits are mixed with the loss bit once a near zero is negated, so check the decoded tuple
+	v, m, e := quantityVme(q)
+
+	return m == 0 && (v&loss == 0 || e == 0 || e == math.MinInt64)
+}
+
+// quantityIsNaN returns whether q is not a number (NaN)
+func quantityIsNaN(q int64) bool {
+	v, m, e := quantityVme(q)
+
+	return m == 0 && v&loss != 0 && e != 0 && e != math.MinInt64 && e != math.MaxInt64
+}
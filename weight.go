@@ -1,8 +1,14 @@
 package decimal
 
 import (
+	"bytes"
+	"database/sql/driver"
 	"encoding/binary"
+	"encoding/json"
 	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
 )
 
 // Weight represents a fixed-point decimal hold as a 64 bits integer including unit among 14 possible.
@@ -21,14 +27,14 @@ type Weight int64
 const (
 	// WeightMaxInt constant is the maximal int64 value that can be safely saved as Weight with exponent still 0.
 	// WeightMaxInt is as well the maximum value of mantissa of Weight and the bitmask to extract mantissa value of a Weight.
-	WeightMaxInt = 0x001fffffffffffff
-
-	weightMinE     = -16
-	weightMaxE     = 15
-	weightBitE     = 57
-	weightEBitmask = 0x3e00000000000000
-	weightBitT     = 53
-	weightTBitmask = 0x01e0000000000000
+	WeightMaxInt = quantityMaxInt
+
+	weightMinE     = quantityMinE
+	weightMaxE     = quantityMaxE
+	weightBitE     = quantityBitE
+	weightEBitmask = quantityEBitmask
+	weightBitT     = quantityBitT
+	weightTBitmask = quantityTBitmask
 )
 
 var (
@@ -45,8 +51,10 @@ var (
 		{u: "ng", c: -12, v: 8 << weightBitT},
 		{u: "pg", c: -15, v: 9 << weightBitT},
 
-		{}, // 10 is reserved for future use
-		{}, // 11 is reserved for future use
+		// Imperial units, the hundredweight is the long (imperial) one of 112 lb, there is no unit code left
+		// for the tons (long ton of 2240 lb, short ton of 2000 lb) which are too easily confused with the metric "t"
+		{u: "st", c: 635029318 + 24<<decimalBitE /* 6.35029318 kg */, v: 10 << weightBitT},
+		{u: "cwt", c: 5080234544 + 24<<decimalBitE /* 50.80234544 kg */, v: 11 << weightBitT},
 
 		// International avoirdupois and troy
 		{u: "lb", c: 45359237 + 24<<decimalBitE /* 0.45359237 kg */, v: 12 << weightBitT},
@@ -58,110 +66,113 @@ var (
 		{u: "mcg", c: -9, v: 7 << weightBitT},
 		{u: " lb av", c: 45359237 + 24<<decimalBitE /* 0.45359237 kg */, v: 12 << weightBitT},
 		{u: " oz av", c: 28349523125 + 20<<decimalBitE /* 0.028349523125 kg */, v: 13 << weightBitT},
+		{u: "stone", c: 635029318 + 24<<decimalBitE /* 6.35029318 kg */, v: 10 << weightBitT},
+
+		// parse only aliases, there is no unit code left for them so their value is converted to another unit
+		{u: "gr", c: -6, v: 6 << weightBitT, s: 6479891 + 27<<decimalBitE /* 1 grain is 64.79891 mg */},
+		{u: "ct", c: -6, v: 6 << weightBitT, s: 200 /* 1 metric carat is 200 mg */},
+		{u: " long ton", c: 45359237 + 24<<decimalBitE, v: 12 << weightBitT, s: 2240 /* 1 UK long ton is 2240 lb */},
+		{u: " short ton", c: 45359237 + 24<<decimalBitE, v: 12 << weightBitT, s: 2000 /* 1 US short ton is 2000 lb */},
 	}
 )
 
-// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
-func (w Weight) vmet() (v, m uint64, e int64, t *unit) {
-	var u uint64
+// weightTable is the unit table of weights
+var weightTable = &UnitTable{units: weightUnits[:]}
 
-	if w < 0 {
-		u = uint64(-w)
-		v = (u & loss) | sign
-	} else {
-		u = uint64(w)
-		v = u & loss
+func init() {
+	hashUnits(weightUnits[:])
+}
+
+var (
+	// weightParseTable holds the *UnitTable of weightUnits followed by the aliases registered with RegisterWeightAlias,
+	// it is replaced as a whole (copy on write) so that parsing never sees a table being modified
+	weightParseTable atomic.Value
+	weightAliasMutex sync.Mutex
+)
+
+// parseWeightTable returns the unit table of the units and aliases recognized when parsing a weight
+func parseWeightTable() *UnitTable {
+	if table, ok := weightParseTable.Load().(*UnitTable); ok {
+		return table
 	}
 
-	e = int64((u&weightEBitmask)<<2) >> (2 + weightBitE) // e is now fully signed exponent
+	return weightTable
+}
 
-	m = u & WeightMaxInt
+// RegisterWeightAlias adds alias as another name of the canonical weight unit (or alias), e.g. "kilo" for "kg" or "gram" for "g",
+// so that "2 kilo" is parsed as 2kg. A weight parsed with an alias is output with the canonical unit.
+// It returns ErrUnitSyntax when canonical is not a known unit, or when alias is empty, already known or a magic word such as "nan" or "inf".
+//
+// RegisterWeightAlias is safe for concurrent use, with itself and with the parsing of weights, though aliases are meant to be registered at startup.
+func RegisterWeightAlias(alias, canonical string) error {
+	weightAliasMutex.Lock()
+	defer weightAliasMutex.Unlock()
 
-	t = &weightUnits[(u&weightTBitmask)>>weightBitT]
-	v |= u & weightTBitmask // v keep unit
+	units := parseWeightTable().units
 
-	// take care of special number
-	if m == 0 {
-		if e == weightMinE {
-			e = math.MinInt64
-		} else if e == weightMaxE {
-			e = math.MaxInt64
-		}
+	// alias must be neither a unit, nor a magic value, nor empty
+	if _, _, _, err := vmeUnitOrMagicFromBytes([]byte(alias), 0, 0, 0, units); err != ErrUnitSyntax {
+		return ErrUnitSyntax
 	}
 
-	return
-}
+	h := unitHash(canonical)
+	for i := range units {
+		if t := &units[i]; t.u != "" && t.h == h {
+			aliases := make([]unit, len(units)+1)
+			copy(aliases, units)
+			aliases[len(units)] = unit{u: alias, v: t.v, h: unitHash(alias), c: t.c, s: t.s, o: t.o}
 
-// internal function to define a decimal from a VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
-func vmeAsWeight(v, m uint64, e int64) Weight {
-	// handle special case for null and zero
-	if m == 0 && v&loss == 0 {
-		if v == 0 && e == 0 {
-			return Null
-		} else {
-			if v&weightTBitmask == 0 {
-				return Weight(math.MinInt64)
-			} else {
-				return Weight(v & weightTBitmask)
-			}
-		}
-	} else {
-		// FIXME: vmeNormalize does not try to change unit
-		v, m, e = vmeNormalize(v, m, e, WeightMaxInt, weightMinE, weightMaxE)
-
-		// FIXME: out-of-range cannot occurs as normalization has been done
-		v |= m | uint64(e<<weightBitE)&weightEBitmask
+			weightParseTable.Store(&UnitTable{units: aliases})
 
-		if v&sign != 0 {
-			return -Weight(v ^ sign)
-		} else {
-			return Weight(v)
+			return nil
 		}
 	}
+
+	return ErrUnitSyntax
 }
 
-// NewWeight returns a new fixed-point decimal weight, value * 10 ^ exp using unit.
-func NewWeight(value int64, exp int32, unit string) (w Weight, err error) {
-	var v, m uint64
-	var e int64
+// internal function to extract decimal into VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent
+func (w Weight) vmet() (v, m uint64, e int64, t *unit) {
+	return weightTable.vmet(int64(w))
+}
 
-	if value <= 0 {
-		v, m, e = sign, uint64(-value), int64(exp)
-	} else {
-		v, m, e = 0, uint64(value), int64(exp)
-	}
+// internal function to define a decimal from a VME tuple : Value of sign, loss and possibly type, Mantissa and Exponent,
+// an overflowing weight of a SI unit being expressed in a coarser SI unit rather than becoming infinite
+func vmeAsWeight(v, m uint64, e int64) Weight {
+	return Weight(weightTable.vmeAs(v, m, e))
+}
 
-	v, m, e, err = vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, weightUnits[:])
-	w = vmeAsWeight(v, m, e)
+// NewWeight returns a new fixed-point decimal weight, value * 10 ^ exp using unit.
+func NewWeight(value int64, exp int32, unit string) (Weight, error) {
+	w, err := parseWeightTable().fromInt(value, exp, unit)
 
-	return
+	return Weight(w), err
 }
 
 // NewWeightFromDecimal converts a Decimal to Weight using unit.
-func NewWeightFromDecimal(value Decimal, unit string) (w Weight, err error) {
-	v, m, e := value.vme()
+func NewWeightFromDecimal(value Decimal, unit string) (Weight, error) {
+	w, err := parseWeightTable().fromDecimal(value, unit)
 
-	v, m, e, err = vmeUnitOrMagicFromBytes([]byte(unit), v, m, e, weightUnits[:])
-	w = vmeAsWeight(v, m, e)
-
-	return
+	return Weight(w), err
 }
 
 // NewWeightFromBytes returns a new Weight from a slice of bytes representation.
 //
 // If no weight unit is given, 'kg' is assumed.
 func NewWeightFromBytes(value []byte) (Weight, error) {
-	if v, m, e, err := vmeFromBytes(value, weightUnits[:]); err == nil {
-		return vmeAsWeight(v, m, e), nil
-	} else {
-		return 0, err
-	}
+	w, err := parseWeightTable().fromBytes(value)
+
+	return Weight(w), err
 }
 
 // NewWeightFromString returns a new Weight from a string representation.
 //
 // If no weight unit is given, 'kg' is assumed.
 //
+// Note grains (gr) and metric carats (ct) are parse only units, converted to mg, and so are the UK long ton and the US short ton,
+// converted to lb: there is no unit code left for them, so that "1gr" gives 64.79891mg and "1 short ton" gives 2000lb.
+// A plain "ton" is ambiguous between them and is not recognized, it returns ErrUnitSyntax.
+//
 // Example:
 //
 //	w, err := NewFromString("-123.45")
@@ -194,6 +205,104 @@ func (w Weight) Unit() string {
 	return weightUnits[(u&weightTBitmask)>>weightBitT].u
 }
 
+// WeightUnits returns the canonical weight unit symbols, as returned by Unit, without aliases such as "mcg" or "lb av".
+func WeightUnits() []string {
+	units := make([]string, 0, weightTBitmask>>weightBitT+1)
+
+	// aliases are after the unit codes
+	for _, t := range weightUnits[:weightTBitmask>>weightBitT+1] {
+		if t.u != "" {
+			units = append(units, t.u)
+		}
+	}
+
+	return units
+}
+
+// WeightConversionFactor returns the number of kg in one unit, e.g. 0.001 for "g" or 0.45359237 for "lb".
+// It returns ErrUnitSyntax for an unknown unit, an empty unit is kg like for NewWeight.
+func WeightConversionFactor(unit string) (Decimal, error) {
+	w, err := NewWeight(1, 0, unit)
+	if err != nil {
+		return NaN, err
+	}
+
+	// a parse only alias such as "gr" is converted to its unit code, so convert w rather than reading the factor of its unit
+	return w.ToDecimal("kg")
+}
+
+// Columns splits w into its unit string (as returned by Unit) and its numeric value expressed in that unit,
+// suitable to store a weight in a columnar store as a small unit enum plus a NUMERIC column.
+// Use WeightFromColumns to rebuild the weight.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("1.5g")
+//	unit, value := w.Columns() // unit = "g", value = 1.5
+func (w Weight) Columns() (unit string, value Decimal) {
+	v, m, e, t := w.vmet()
+
+	return t.u, vmeAsDecimal(v&^weightTBitmask, m, e)
+}
+
+// WeightFromColumns rebuilds a Weight from the unit and value returned by Columns.
+func WeightFromColumns(unit string, value Decimal) (Weight, error) {
+	return NewWeightFromDecimal(value, unit)
+}
+
+// Picograms returns the weight as a whole number of picograms, the smallest unit of the table, which is a canonical
+// exact integer key for a weight whatever its unit. ok is false when the weight is not finite, not exact,
+// not a whole number of picograms or does not fit in an int64 (more than about 9.2t).
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("1.5mg")
+//	pg, ok := w.Picograms() // pg = 1500000000, ok = true
+func (w Weight) Picograms() (pg int64, ok bool) {
+	v, m, e, t := w.vmet()
+
+	// Null and Zero are 0pg, other magic values are not finite or not exact
+	if m == 0 {
+		return 0, v&loss == 0
+	}
+
+	v &^= weightTBitmask
+	if t.c.IsInteger() {
+		e += t.c.Int64()
+	} else {
+		vc, mc, ec := t.c.vme()
+		v, m, e = vmeMul(v, m, e, vc, mc, ec)
+	}
+
+	// normalization strips trailing zeros so that a whole number of picograms has a non negative exponent
+	v, m, e = vmeNormalize(v, m, e+15, MaxInt, decimalMinE, decimalMaxE)
+	if v&loss != 0 || e < 0 {
+		return 0, false
+	}
+
+	hi, lo := bits.Mul64(m, tenPow[e])
+	if hi != 0 || lo > math.MaxInt64 && (v&sign == 0 || lo > 1<<63) {
+		return 0, false
+	}
+
+	if v&sign != 0 {
+		return -int64(lo), true
+	}
+
+	return int64(lo), true
+}
+
+// WeightFromPicograms returns a weight of pg picograms using pg unit, the loss bit being set
+// if pg has more significant digits than a Weight mantissa can hold.
+func WeightFromPicograms(pg int64) Weight {
+	v, m := weightUnits[9].v, uint64(pg) // pg
+	if pg < 0 {
+		v, m = v|sign, uint64(-pg)
+	}
+
+	return vmeAsWeight(v, m, 0)
+}
+
 // Abs returns the absolute value of the weight.
 func (w Weight) Abs() Weight {
 	if w < 0 {
@@ -203,6 +312,12 @@ func (w Weight) Abs() Weight {
 	}
 }
 
+// Neg returns -w keeping w unit, zero (whatever its unit) and near zero weights are returned unchanged like Decimal.Neg does.
+// As a negative weight is stored as the negation of its positive counterpart, the unit bits are preserved by both Abs and Neg.
+func (w Weight) Neg() Weight {
+	return Weight(quantityNeg(int64(w)))
+}
+
 // Add returns w1 + w2 using w1 unit.
 //
 // Example:
@@ -218,67 +333,314 @@ func (w Weight) Abs() Weight {
 //	124kg
 //	124000g
 func (w1 Weight) Add(w2 Weight) Weight {
-	v1, m1, e1, t1 := w1.vmet()
-	v2, m2, e2, t2 := w2.vmet()
+	return Weight(weightTable.add(int64(w1), int64(w2)))
+}
 
-	if t2.c.IsInteger() {
-		e2 += t2.c.Int64()
-	} else {
-		vc, mc, ec := t2.c.vme()
-		v2, m2, e2 = vmeMul(v2, m2, e2, vc, mc, ec)
+// AddFine returns w1 + w2 using the finer of w1 and w2 units (w1 unit if they are the same size),
+// which avoids the loss of adding a weight in a finer unit to a weight in a coarser unit that is not a power of ten of it.
+//
+// Example:
+//
+//	w1, _ := NewWeightFromString("1lb")
+//	w2, _ := NewWeightFromString("1g")
+//	println(w1.Add(w2))
+//	println(w1.AddFine(w2))
+//
+// Output:
+//
+//	~1.00220462262185lb
+//	454.59237g
+func (w1 Weight) AddFine(w2 Weight) Weight {
+	_, _, _, t1 := w1.vmet()
+	_, _, _, t2 := w2.vmet()
+
+	if t2.factor().LessThan(t1.factor()) {
+		return w2.Add(w1)
 	}
-	if t1.c.IsInteger() {
-		e2 -= t1.c.Int64()
-	} else {
-		vc, mc, ec := t1.c.vme()
 
-		var rem uint64
-		v2, m2, e2, rem, _ = vmeDivRem(v2, m2, e2, vc, mc, ec, int32(DivisionPrecision))
+	return w1.Add(w2)
+}
+
+// Sub returns w1 - w2 using w1 unit.
+func (w1 Weight) Sub(w2 Weight) Weight {
+	return w1.Add(-w2)
+}
+
+// Mul returns w * d using w unit.
+func (w Weight) Mul(d Decimal) Weight {
+	return Weight(weightTable.mul(int64(w), d))
+}
+
+// Div returns w / d using w unit. If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
+func (w Weight) Div(d Decimal) Weight {
+	return Weight(weightTable.div(int64(w), d))
+}
+
+// Ratio returns the dimensionless ratio w1 / w2, w2 being first converted to w1 unit, e.g. 0.5 for 500g / 1kg.
+// If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set,
+// the ratio of w1 to a zero weight is NaN.
+func (w1 Weight) Ratio(w2 Weight) Decimal {
+	_, _, _, t1 := w1.vmet()
 
-		if rem != 0 {
-			v2 |= loss
+	// adding w2 to a zero weight in w1 unit converts w2 to that unit
+	_, d1 := w1.Columns()
+	_, d2 := vmeAsWeight(t1.v, 0, 0).Add(w2).Columns()
 
-			// FIXME: fix m so that the result is the nearest, like shopspring/decimal
-			if (rem << 1) >= mc {
-				m2++
+	return d1.Div(d2)
+}
+
+// PortionRemainder returns how many whole portion weights fit into w and the weight left over,
+// both weights being first expressed in the finer of their units which is the unit of the remainder.
+// Like QuoRem, the count is truncated toward zero and the remainder has the sign of w.
+// It returns ErrDivisionByZero when portion is zero and ErrOutOfRange for a non finite weight or a count which does not fit in an int64.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("1kg")
+//	bag, _ := NewWeightFromString("300g")
+//	count, left, _ := w.PortionRemainder(bag) // count = 3, left = 100g
+func (w Weight) PortionRemainder(portion Weight) (count int64, remainder Weight, err error) {
+	if w.IsNaN() || w.IsInfinite() || portion.IsNaN() || portion.IsInfinite() {
+		return 0, Weight(NaN), ErrOutOfRange
+	}
+	if portion.IsZero() {
+		return 0, Weight(NaN), ErrDivisionByZero
+	}
+
+	_, _, _, t1 := w.vmet()
+	_, _, _, t2 := portion.vmet()
+
+	// adding a weight to a zero weight in the finer unit converts it to that unit
+	z := vmeAsWeight(t1.v, 0, 0)
+	if t2.factor().LessThan(t1.factor()) {
+		z = vmeAsWeight(t2.v, 0, 0)
+	}
+	w, portion = z.Add(w), z.Add(portion)
+
+	_, d1 := w.Columns()
+	_, d2 := portion.Columns()
+	q, _ := d1.QuoRem(d2, 0)
+
+	if count, err = q.IntPartErr(); err != nil {
+		return 0, Weight(NaN), err
+	}
+
+	return count, w.Sub(portion.Mul(q)), nil
+}
+
+// In returns w expressed in unit, the same physical weight using the conversion factors of the units
+// (exact for powers of ten, DivisionPrecision digits with loss bit set when the factor does not divide exactly).
+// It returns ErrUnitSyntax for an unknown unit like NewWeightFromBytes.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("1kg")
+//	g, _ := w.In("g")   // 1000g
+//	lb, _ := w.In("lb") // ~2.2046226218487757lb
+func (w Weight) In(unit string) (Weight, error) {
+	r, err := parseWeightTable().in(int64(w), unit)
+
+	return Weight(r), err
+}
+
+// ToDecimal returns the value of w expressed in unit as a plain Decimal, 1.5 for 1500g in "kg".
+// It returns ErrUnitSyntax for an unknown unit.
+func (w Weight) ToDecimal(unit string) (Decimal, error) {
+	w, err := w.In(unit)
+	if err != nil {
+		return NaN, err
+	}
+	_, value := w.Columns()
+
+	return value, nil
+}
+
+// Humanize returns w expressed in the unit giving the most readable value, without switching between the metric,
+// avoirdupois and troy systems: a metric weight is expressed from pg to Gt with a value between 1 and 1000,
+// an avoirdupois weight in lb, or in oz below 1 lb (st and cwt are kept from 1 st or 1 cwt), a troy weight in lb t, or in oz t below 1 lb t.
+// Zero, infinite and NaN weights are returned unchanged.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("1500000g")
+//	println(w.Humanize()) // 1.5t
+//	w, _ = NewWeightFromString("0.0005kg")
+//	println(w.Humanize()) // 500mg
+func (w Weight) Humanize() Weight {
+	_, m, _, t := w.vmet()
+	if m == 0 {
+		return w
+	}
+
+	var ladder []string
+
+	switch t.u {
+	case "st", "cwt":
+		if one, _ := NewWeight(1, 0, t.u); w.Abs().GreaterThanOrEqual(one) {
+			return w
+		}
+		ladder = []string{"lb", "oz"}
+	case "lb", "oz":
+		ladder = []string{"lb", "oz"}
+	case " lb t", " oz t":
+		ladder = []string{" lb t", " oz t"}
+	default:
+		// metric units are powers of ten of kg, from pg (10^-15) to Gt (10^12) every 10^3
+		_, value := w.Columns()
+
+		p := int64(value.NumDigits()) - 1 + int64(value.Exponent()) + t.c.Int64()
+		if p < 0 {
+			p -= 2
+		}
+		p = p / 3 * 3
+		if p < -15 {
+			p = -15
+		} else if p > 12 {
+			p = 12
+		}
+
+		for i := range weightUnits[:weightTBitmask>>weightBitT+1] {
+			if u := &weightUnits[i]; u.u != "" && u.c.IsInteger() && u.c.Int64() == p {
+				r, _ := w.In(u.u)
+
+				return r
 			}
 		}
+
+		return w
 	}
 
-	v, m, e := vmeAdd(v1, m1, e1, v2, m2, e2)
+	// the largest unit of the ladder with a value of at least 1, the smallest one otherwise
+	for _, u := range ladder {
+		r, _ := w.In(u)
+
+		if _, value := r.Columns(); value.Abs().GreaterThanOrEqual(1) || u == ladder[len(ladder)-1] {
+			return r
+		}
+	}
 
-	return vmeAsWeight(v, m, e)
+	return w
 }
 
-// Sub returns w1 - w2 using w1 unit.
-func (w1 Weight) Sub(w2 Weight) Weight {
-	return w1.Add(-w2)
+// retailSteps is the ladder of retail increments within a decade, 1, 2, 2.5 and 5 scaled by 10 to stay integers,
+// that is 100g, 200g, 250g, 500g and 1kg for weights between 100g and 1kg
+var retailSteps = [...]int64{10, 20, 25, 50, 100}
+
+// RoundToRetail returns w snapped to the nearest standard retail quantity, keeping the unit of w.
+// The ladder of quantities is 1, 2, 2.5 and 5 times a power of ten in the unit of w (..., 100g, 200g, 250g, 500g, 1kg, 2kg, 2.5kg, 5kg, 10kg, ...),
+// a weight half-way between two quantities is rounded to the larger one. Zero, infinite and NaN weights are returned unchanged.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("0.23kg")
+//	println(w.RoundToRetail()) // 0.25kg
+//	w, _ = NewWeightFromString("1.1kg")
+//	println(w.RoundToRetail()) // 1kg
+func (w Weight) RoundToRetail() Weight {
+	unit, value := w.Columns()
+	if value.IsZero() || value.IsNaN() || value.IsInfinite() {
+		return w
+	}
+
+	// a is between 10^p included and 10^(p+1) excluded
+	a := value.Abs()
+	p := int32(a.NumDigits()) - 1 + a.Exponent()
+
+	var best, dist Decimal
+	for i, step := range retailSteps {
+		q := New(step, p-1)
+
+		if d := a.Sub(q).Abs(); i == 0 || d.LessThanOrEqual(dist) {
+			best, dist = q, d
+		}
+	}
+	if value.IsNegative() {
+		best = best.Neg()
+	}
+
+	r, _ := NewWeightFromDecimal(best, unit)
+
+	return r
 }
 
-// Mul returns w * d using w unit.
-func (w Weight) Mul(d Decimal) Weight {
-	v1, m1, e1, _ := w.vmet()
-	v2, m2, e2 := d.vme()
+// Per returns w expressed in unit divided by d, as a plain Decimal, e.g. a density in g/mL when d is a volume in mL.
+// It returns ErrUnitSyntax for an unknown unit and ErrDivisionByZero when d is zero.
+//
+// Example:
+//
+//	w, _ := NewWeightFromString("2.5kg")
+//	liters := Decimal(2)
+//	density, _ := w.Per(liters.Mul(1000), "g") // 1.25 (g/mL)
+func (w Weight) Per(d Decimal, unit string) (Decimal, error) {
+	if d.IsZero() {
+		return NaN, ErrDivisionByZero
+	}
+
+	value, err := w.ToDecimal(unit)
+	if err != nil {
+		return NaN, err
+	}
 
-	return vmeAsWeight(vmeMul(v1, m1, e1, v2, m2, e2))
+	return value.Div(d), nil
 }
 
-// Div returns w / d using w unit. If it doesn't divide exactly, the result will have DivisionPrecision digits after the decimal point and loss bit will be set.
-func (w Weight) Div(d Decimal) Weight {
-	v1, m1, e1, _ := w.vmet()
-	v2, m2, e2 := d.vme()
+// SumWeights returns the combined total of the provided first and rest Weights using the unit of first,
+// every weight being converted to that unit then summed with the compensated algorithm of Sum.
+//
+// Example:
+//
+//	w1, _ := NewWeightFromString("1kg")
+//	w2, _ := NewWeightFromString("500g")
+//	w3, _ := NewWeightFromString("0.3kg")
+//	println(SumWeights(w1, w2, w3)) // 1.8kg
+func SumWeights(first Weight, rest ...Weight) Weight {
+	if len(rest) == 0 {
+		return first
+	}
 
-	v, m, e, rem, _ := vmeDivRem(v1, m1, e1, v2, m2, e2, int32(DivisionPrecision))
+	// adding a weight to a zero weight in unit converts it to unit
+	unit, value := first.Columns()
+	z, _ := NewWeight(0, 0, unit)
 
-	if rem != 0 {
-		v |= loss
+	values := make([]Decimal, len(rest))
+	for i, w := range rest {
+		_, values[i] = z.Add(w).Columns()
+	}
+
+	w, _ := NewWeightFromDecimal(Sum(value, values...), unit)
+
+	return w
+}
+
+// AvgWeights returns the average value of the provided first and rest Weights using the unit of first.
+func AvgWeights(first Weight, rest ...Weight) Weight {
+	return SumWeights(first, rest...).Div(Decimal(len(rest) + 1))
+}
+
+// MinWeights returns the smallest Weight that was passed in the arguments whatever their units, as it was passed.
+func MinWeights(first Weight, rest ...Weight) Weight {
+	min := first
+
+	for _, item := range rest {
+		if min.GreaterThanOrEqual(item) {
+			min = item
+		}
+	}
+
+	return min
+}
 
-		// fix m so that the result is the nearest, like in shopspring/decimal
-		if (rem << 1) >= m2 {
-			m++
+// MaxWeights returns the largest Weight that was passed in the arguments whatever their units, as it was passed.
+func MaxWeights(first Weight, rest ...Weight) Weight {
+	max := first
+
+	for _, item := range rest {
+		if item.GreaterThanOrEqual(max) {
+			max = item
 		}
 	}
-	return vmeAsWeight(v, m, e)
+
+	return max
 }
 
 // String returns the string representation of the weight with the fixed point and unit.
@@ -297,23 +659,19 @@ func (w Weight) String() string {
 
 // BytesTo appends the string representation of the decimal to a slice of byte, if the decimal is Null it appends 0.
 func (w Weight) BytesTo(b []byte) []byte {
-	v, m, e, t := w.vmet()
-
 	// the maximal length of decimal representation in bytes in such conditions is 20
-	return vmetBytesTo(b, v, m, e, 0, t, true, false)
+	return weightTable.bytesTo(b, int64(w), true)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
 func (w Weight) MarshalJSON() ([]byte, error) {
-	v, m, e, t := w.vmet()
-
-	return vmetBytesTo(nil, v, m, e, 0, t, false, false), nil
+	return weightTable.bytesTo(nil, int64(w), false), nil
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (w *Weight) UnmarshalJSON(b []byte) error {
-	if v, m, e, err := vmeFromBytes(b, weightUnits[:]); err == nil {
-		*w = vmeAsWeight(v, m, e)
+	if _w, err := parseWeightTable().fromBytes(b); err == nil {
+		*w = Weight(_w)
 
 		return nil
 	} else {
@@ -321,6 +679,45 @@ func (w *Weight) UnmarshalJSON(b []byte) error {
 	}
 }
 
+// weightJSONObject is the JSON object form of a weight, see MarshalJSONObject
+type weightJSONObject struct {
+	Value Decimal `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// MarshalJSONObject returns the JSON object form of the weight, {"value":1.5,"unit":"kg"}, more self-describing
+// for API consumers than the MarshalJSON form. The value is output like Decimal.MarshalJSON.
+func (w Weight) MarshalJSONObject() ([]byte, error) {
+	unit, value := w.Columns()
+
+	for len(unit) > 0 && unit[0] == ' ' {
+		unit = unit[1:]
+	}
+
+	return json.Marshal(weightJSONObject{Value: value, Unit: unit})
+}
+
+// UnmarshalJSONObject decodes a weight from either its JSON object form {"value":1.5,"unit":"kg"}, as written by MarshalJSONObject,
+// or any form accepted by UnmarshalJSON such as "1.5kg". A missing unit is kg.
+func (w *Weight) UnmarshalJSONObject(b []byte) error {
+	if b = bytes.TrimSpace(b); len(b) == 0 || b[0] != '{' {
+		return w.UnmarshalJSON(b)
+	}
+
+	var o weightJSONObject
+	if err := json.Unmarshal(b, &o); err != nil {
+		return err
+	}
+
+	if _w, err := NewWeightFromDecimal(o.Value, o.Unit); err != nil {
+		return err
+	} else {
+		*w = _w
+
+		return nil
+	}
+}
+
 // UnmarshalText implements the encoding.TextUnmarshaler interface for XML deserialization.
 func (w *Weight) UnmarshalText(text []byte) error {
 	if _w, err := NewWeightFromBytes(text); err != nil {
@@ -337,6 +734,14 @@ func (w Weight) MarshalText() (text []byte, err error) {
 	return w.BytesTo(nil), nil
 }
 
+// MarshalTextNoUnit is like MarshalText but omits the unit, "550" for 550g,
+// suitable when the unit is stored apart, see Columns.
+func (w Weight) MarshalTextNoUnit() (text []byte, err error) {
+	v, m, e, _ := w.vmet()
+
+	return vmetBytesTo(nil, v, m, e, 0, nil, true, false), nil
+}
+
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 //
 // When the unit is kg (the default unit code 0) the encoding is identical to a Decimal of the same
@@ -347,7 +752,8 @@ func (w Weight) MarshalBinary() (data []byte, err error) {
 	v, m, e, _ := w.vmet()
 	unit := (v & weightTBitmask) >> weightBitT
 
-	if m == 0 || unit == 0 {
+	// a zero keeps its unit (0g is not 0kg), only the magic values have no unit
+	if unit == 0 || m == 0 && v&loss != 0 {
 		return marshalBinaryV1(v, m, e), nil
 	}
 
@@ -410,7 +816,7 @@ func (w *Weight) UnmarshalBinary(data []byte) error {
 		if n <= 0 {
 			return ErrFormat
 		}
-		if unit >= uint64(len(weightUnits)) || weightUnits[unit].u == "" {
+		if unit > weightTBitmask>>weightBitT || weightUnits[unit].u == "" {
 			return ErrUnitSyntax
 		}
 		rest = rest[n:]
@@ -448,6 +854,58 @@ func (w *Weight) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// GobEncode implements the gob.GobEncoder interface for gob serialization, using the MarshalBinary format.
+func (w Weight) GobEncode() ([]byte, error) {
+	return w.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface for gob serialization.
+func (w *Weight) GobDecode(data []byte) error {
+	return w.UnmarshalBinary(data)
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+// A string or a slice of bytes is parsed like NewWeightFromBytes, a bare numeric value is a weight in kg.
+func (w *Weight) Scan(value interface{}) error {
+	var _w Weight
+	var err error
+
+	switch v := value.(type) {
+	case string:
+		_w, err = NewWeightFromString(v)
+
+	case []byte:
+		_w, err = NewWeightFromBytes(v)
+
+	case float32:
+		_w, err = NewWeightFromDecimal(NewFromFloat(float64(v)), "kg")
+
+	case float64:
+		_w, err = NewWeightFromDecimal(NewFromFloat(v), "kg")
+
+	case int64:
+		_w, err = NewWeightFromDecimal(New(v, 0), "kg")
+
+	case uint64:
+		_w, err = NewWeightFromDecimal(NewFromUint64(v), "kg")
+
+	default:
+		return ErrFormat
+	}
+
+	if err != nil {
+		return err
+	}
+	*w = _w
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization, the weight is stored as its String representation with its unit.
+func (w Weight) Value() (driver.Value, error) {
+	return w.String(), nil
+}
+
 // IsNull return
 //
 //	true if w == Null
@@ -499,10 +957,7 @@ func (w Weight) IsExactlyZero() bool {
 //	false if w < 0
 //	false if w > 0
 func (w Weight) IsZero() bool {
-	// Check for NearZero equivalents in Weight context if they exist.
-	// Assuming Weight uses same loss bit mechanism.
-	// NearZero is Zero | loss.
-	return w.IsExactlyZero() || Weight(uint64(w)&^sign&^weightTBitmask) == Weight(loss)
+	return quantityIsZero(int64(w))
 }
 
 // IsExact return true if a weight has its loss bit not set, ie it has not lost its precision during computation or conversion.
@@ -544,31 +999,7 @@ func (w Weight) IsInfinite() bool {
 //	true if w is not a a number (NaN)
 //	false in any other case
 func (w Weight) IsNaN() bool {
-	// Check if exponent is special (NaN range)
-	// Weight has 53 bits mantissa, 4 bits unit, 57 bits total for value part?
-	// Actually vmet() extracts e.
-	// Let's use vmet() to check for NaN condition which is usually e=1 and v=loss?
-	// Or check raw bits like Decimal.IsNaN.
-
-	// Decimal IsNaN checks:
-	// u >= 0x42 && u < 0x5c || u >= 0x62 && u <= 0x7e (after shifting)
-
-	// Weight layout:
-	// e = int64((u&weightEBitmask)<<2) >> (2 + weightBitE)
-	// weightBitE = 57
-
-	// Let's rely on checking if it's not a valid number via properties if possible,
-	// or replicate bit check.
-	// Simpler: check if e is in NaN range?
-	// In core.go, NaN has e=1, v=loss.
-
-	v, m, e, _ := w.vmet()
-	if m == 0 && v&loss != 0 {
-		if e != 0 && e != math.MinInt64 && e != math.MaxInt64 {
-			return true
-		}
-	}
-	return false
+	return quantityIsNaN(int64(w))
 }
 
 // Sign return
@@ -585,6 +1016,33 @@ func (w Weight) Sign() int {
 	}
 }
 
+// Equal returns whether w1 == w2 whatever their units and without taking care of loss bit, 1kg and 1000g are equal
+// though their representations differ. The zero and near zero weights of any unit are equals.
+func (w1 Weight) Equal(w2 Weight) bool {
+	w := w1.Sub(w2)
+
+	return w.IsZero()
+}
+
+// EqualWithin returns whether w1 and w2 differ by no more than the tolerance tol whatever their units, which reconciles
+// weights entered in different unit systems whose conversions are inexact, e.g. 1lb and a weight computed as ~453.59237g.
+// The sign of tol is ignored and NaN weights are never equal.
+//
+// Example:
+//
+//	lb, _ := NewWeightFromString("1lb")
+//	g, _ := NewWeightFromString("453.6g")
+//	tol, _ := NewWeightFromString("0.1g")
+//	lb.EqualWithin(g, tol) // true
+func (w1 Weight) EqualWithin(w2 Weight, tol Weight) bool {
+	w := w1.Sub(w2)
+	if w.IsNaN() || tol.IsNaN() {
+		return false
+	}
+
+	return w.Abs().Compare(tol.Abs()) <= 0
+}
+
 // Compare compares the numbers represented by w1 and w2 without taking into account lost precision and returns:
 //
 //	-1 if w1 <  w2
@@ -602,6 +1060,45 @@ func (w1 Weight) Compare(w2 Weight) int {
 	}
 }
 
+// CompareTotal compares w1 and w2 like Compare but defines a total order, suitable for a deterministic sort or deduplication:
+// physically equal weights are ordered by unit, the finer first (1000g before 1kg), then by their internal representation,
+// and NaN are placed after any other weight, +Inf included.
+//
+//	-1 if w1 <  w2
+//	 0 if w1 == w2 (same value, same unit and same representation)
+//	+1 if w1 >  w2
+func (w1 Weight) CompareTotal(w2 Weight) int {
+	if n1, n2 := w1.IsNaN(), w2.IsNaN(); n1 != n2 {
+		if n1 {
+			return 1
+		} else {
+			return -1
+		}
+	} else if !n1 {
+		// infinities of the same sign are equal, their difference would be NaN
+		if !w1.IsInfinite() || !w2.IsInfinite() || w1.Sign() != w2.Sign() {
+			if c := w1.Compare(w2); c != 0 {
+				return c
+			}
+		}
+
+		_, _, _, t1 := w1.vmet()
+		_, _, _, t2 := w2.vmet()
+		if c := t1.factor().Compare(t2.factor()); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case w1 < w2:
+		return -1
+	case w1 > w2:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // GreaterThan returns true when w1 is greater than w2 (w1 > w2).
 func (w1 Weight) GreaterThan(w2 Weight) bool {
 	w := w1.Sub(w2)
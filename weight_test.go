@@ -2,6 +2,12 @@ package decimal
 
 import (
 	"testing"
+
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"strings"
 )
 
 func TestWeightConversions(t *testing.T) {
@@ -51,6 +57,114 @@ func TestWeightConversions(t *testing.T) {
 	}
 }
 
+func TestWeightAbsNeg(t *testing.T) {
+	cases := []struct {
+		s, abs, neg string
+	}{
+		{"-1 oz t", "1 oz t", "1 oz t"},
+		{"1 oz t", "1 oz t", "-1 oz t"},
+		{"-12.5g", "12.5g", "12.5g"},
+		{"3lb", "3lb", "-3lb"},
+		{"~-1.5mg", "~1.5mg", "~1.5mg"},
+		{"0g", "0g", "0g"},
+		{"0kg", "0kg", "0kg"},
+		{"+Inf", "+Inf", "-Inf"},
+		{"-Inf", "+Inf", "+Inf"},
+	}
+
+	for _, c := range cases {
+		w, err := NewWeightFromString(c.s)
+		if err != nil {
+			t.Fatalf(`NewWeightFromString(%q) has error = %v`, c.s, err)
+		}
+
+		if a := w.Abs(); a.String() != c.abs {
+			t.Errorf(`%v.Abs() should be %s and not %v`, w, c.abs, a)
+		}
+		if n := w.Neg(); n.String() != c.neg {
+			t.Errorf(`%v.Neg() should be %s and not %v`, w, c.neg, n)
+		} else if n.Neg() != w && !w.IsZero() {
+			t.Errorf(`%v.Neg().Neg() should be %v and not %v`, w, w, n.Neg())
+		}
+	}
+
+	w, _ := NewWeightFromString("-1 oz t")
+	if u := w.Abs().Unit(); u != " oz t" {
+		t.Errorf(`-1 oz t.Abs().Unit() should be " oz t" and not %q`, u)
+	}
+	if u := w.Neg().Unit(); u != " oz t" {
+		t.Errorf(`-1 oz t.Neg().Unit() should be " oz t" and not %q`, u)
+	}
+
+	// zero and near zero magic values are left unchanged
+	for _, w := range []Weight{Weight(Null), Weight(Zero), Weight(NearZero), WeightFromPicograms(0)} {
+		if n := w.Neg(); n != w {
+			t.Errorf(`%v.Neg() should be unchanged and not %v (%016x)`, w, n, uint64(n))
+		}
+	}
+	if n := Weight(NaN).Neg(); !n.IsNaN() {
+		t.Errorf(`NaN.Neg() should be NaN and not %v`, n)
+	}
+}
+
+func TestWeightDecimalConsistency(t *testing.T) {
+	values := []string{
+		"0", "1", "-1", "2", "3", "7", "0.1", "0.2", "-0.3", "1.5", "123.45", "-123.45", "0.001", "1e10", "-2.5e-7",
+		"~1.000000000000001", "~0", "+Inf", "-Inf", "NaN",
+	}
+
+	// kg weights have no unit bits, any operation must give the Decimal result rounded to the Weight precision,
+	// values are small enough so that rounding the Decimal result does not round twice
+	same := func(op string, d Decimal, w Weight) {
+		if dw := vmeAsWeight(d.vme()); dw != w && !(dw.IsNaN() && w.IsNaN()) && !(dw.IsZero() && w.IsZero() && dw.IsExact() == w.IsExact()) {
+			t.Errorf(`Weight %s should be %v (%016x) like Decimal %v and not %v (%016x)`, op, dw, uint64(dw), d, w, uint64(w))
+		}
+	}
+
+	// except for a quotient, which is rounded once to the Weight precision: when the Decimal quotient ends with a 5
+	// one digit beyond the Weight precision, rounding it again may give the other neighbour
+	sameDiv := func(op string, d Decimal, w Weight) {
+		if dw := vmeAsWeight(d.vme()); dw != w && strings.HasSuffix(d.String(), "5") {
+			_, _, e, _ := dw.vmet()
+			if _, diff := w.Sub(dw).Columns(); diff.Abs().Equal(New(1, int32(e))) {
+				return
+			}
+		}
+
+		same(op, d, w)
+	}
+
+	for _, s1 := range values {
+		d1, _ := NewFromString(s1)
+		w1, _ := NewWeightFromString(s1)
+
+		same("Neg "+s1, d1.Neg(), w1.Neg())
+		same("Abs "+s1, d1.Abs(), w1.Abs())
+		if d1.Sign() != w1.Sign() || d1.IsZero() != w1.IsZero() || d1.IsNaN() != w1.IsNaN() || d1.IsInfinite() != w1.IsInfinite() {
+			t.Errorf(`Weight %s should have the same sign, zero, NaN and infinity flags than Decimal %v`, w1, d1)
+		}
+
+		for _, s2 := range values {
+			d2, _ := NewFromString(s2)
+			w2, _ := NewWeightFromString(s2)
+
+			same(s1+" + "+s2, d1.Add(d2), w1.Add(w2))
+			same(s1+" - "+s2, d1.Sub(d2), w1.Sub(w2))
+			same(s1+" * "+s2, d1.Mul(d2), w1.Mul(d2))
+			sameDiv(s1+" / "+s2, d1.Div(d2), w1.Div(d2))
+
+			if !d1.IsNaN() && !d2.IsNaN() && !(d1.IsInfinite() && d2.IsInfinite()) {
+				if c1, c2 := d1.Compare(d2), w1.Compare(w2); c1 != c2 {
+					t.Errorf(`%v.Compare(%v) should be %d like Decimal and not %d`, w1, w2, c1, c2)
+				}
+				if e1, e2 := d1.Equal(d2), w1.Equal(w2); e1 != e2 {
+					t.Errorf(`%v.Equal(%v) should be %v like Decimal and not %v`, w1, w2, e1, e2)
+				}
+			}
+		}
+	}
+}
+
 func TestWeightAdd(t *testing.T) {
 	w1, err := NewWeightFromString(".00123")
 	if !w1.IsExact() {
@@ -107,6 +221,131 @@ func TestWeightAdd(t *testing.T) {
 	}
 }
 
+func TestWeightPortionRemainder(t *testing.T) {
+	cases := []struct {
+		w, portion string
+		count      int64
+		remainder  string
+	}{
+		{"1kg", "250g", 4, "0g"},
+		{"1kg", "300g", 3, "100g"},
+		{"1000g", "0.3kg", 3, "100g"},
+		{"1kg", "1.5kg", 0, "1kg"},
+		{"2.5t", "400kg", 6, "100kg"},
+		{"-1kg", "300g", -3, "-100g"},
+		{"1kg", "-300g", -3, "100g"},
+		{"1lb", "1oz", 16, "0oz"},
+		{"1lb", "100g", 4, "53.59237g"},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+		p, _ := NewWeightFromString(c.portion)
+
+		if count, r, err := w.PortionRemainder(p); err != nil || count != c.count || r.String() != c.remainder {
+			t.Errorf(`%v.PortionRemainder(%v) should be (%d, %s) and not (%d, %v) (err = %v)`, w, p, c.count, c.remainder, count, r, err)
+		}
+	}
+
+	kg, _ := NewWeightFromString("1kg")
+	for _, p := range []Weight{Weight(Zero), Weight(Null), Weight(NearZero)} {
+		if _, _, err := kg.PortionRemainder(p); err != ErrDivisionByZero {
+			t.Errorf(`1kg.PortionRemainder(%v) should return ErrDivisionByZero and not %v`, p, err)
+		}
+	}
+	for _, c := range [][2]Weight{{Weight(NaN), kg}, {Weight(PositiveInfinity), kg}, {kg, Weight(NegativeInfinity)}, {kg, Weight(NaN)}} {
+		if _, _, err := c[0].PortionRemainder(c[1]); err != ErrOutOfRange {
+			t.Errorf(`%v.PortionRemainder(%v) should return ErrOutOfRange and not %v`, c[0], c[1], err)
+		}
+	}
+}
+
+func TestWeightPicograms(t *testing.T) {
+	cases := []struct {
+		s  string
+		pg int64
+	}{
+		{"1kg", 1000000000000000},
+		{"-1kg", -1000000000000000},
+		{"1.5g", 1500000000000},
+		{"1.5mg", 1500000000},
+		{"2.25µg", 2250000},
+		{"7ng", 7000},
+		{"42pg", 42},
+		{"9t", 9000000000000000000},
+		{"1lb", 453592370000000},
+		{"1oz", 28349523125000},
+		{"0g", 0},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.s)
+
+		pg, ok := w.Picograms()
+		if !ok || pg != c.pg {
+			t.Errorf(`%v.Picograms() should be (%d, true) and not (%d, %t)`, w, c.pg, pg, ok)
+		}
+
+		// round-trip through picograms gives the same weight, in pg
+		if r := WeightFromPicograms(pg); r.Compare(w) != 0 {
+			t.Errorf(`WeightFromPicograms(%d) should be equal to %v and not %v`, pg, w, r)
+		} else if pg, ok := r.Picograms(); !ok || pg != c.pg {
+			t.Errorf(`%v.Picograms() should be (%d, true) and not (%d, %t)`, r, c.pg, pg, ok)
+		}
+	}
+
+	for _, s := range []string{"10t", "1Gt", "-10t", "0.5pg", "~1g", "~0", "NaN", "+Inf", "-Inf"} {
+		w, _ := NewWeightFromString(s)
+		if pg, ok := w.Picograms(); ok {
+			t.Errorf(`%v.Picograms() should not be ok and not %d`, w, pg)
+		}
+	}
+
+	if w := WeightFromPicograms(0); w.String() != "0pg" {
+		t.Errorf(`WeightFromPicograms(0) should be 0pg and not %v`, w)
+	}
+	if w := WeightFromPicograms(math.MaxInt64); w.IsExact() {
+		t.Errorf(`WeightFromPicograms(MaxInt64) should not be exact and not %v`, w)
+	}
+}
+
+func TestWeightAddFine(t *testing.T) {
+	cases := []struct {
+		w1, w2       string
+		add, addFine string
+	}{
+		{"1lb", "1g", "~1.002204622621849lb", "454.59237g"},
+		{"1g", "1lb", "454.59237g", "454.59237g"},
+		{"1kg", "1g", "1.001kg", "1001g"},
+		{"1oz", "1lb", "17oz", "17oz"},
+		{"1lb", "1oz", "1.0625lb", "17oz"},
+		{"2g", "3g", "5g", "5g"},
+		{"1mcg", "1µg", "2µg", "2µg"},
+	}
+
+	for _, c := range cases {
+		w1, _ := NewWeightFromString(c.w1)
+		w2, _ := NewWeightFromString(c.w2)
+
+		if w := w1.Add(w2); w.String() != c.add {
+			t.Errorf(`%v.Add(%v) should be %s and not %v`, w1, w2, c.add, w)
+		}
+		if w := w1.AddFine(w2); w.String() != c.addFine {
+			t.Errorf(`%v.AddFine(%v) should be %s and not %v`, w1, w2, c.addFine, w)
+		}
+	}
+
+	// Add sets the loss bit where AddFine stays exact
+	w1, _ := NewWeightFromString("1lb")
+	w2, _ := NewWeightFromString("1g")
+	if w := w1.Add(w2); w.IsExact() {
+		t.Errorf(`%v.Add(%v) should be inexact and not %v`, w1, w2, w)
+	}
+	if w := w1.AddFine(w2); !w.IsExact() || w.Unit() != "g" {
+		t.Errorf(`%v.AddFine(%v) should be exact in g and not %v`, w1, w2, w)
+	}
+}
+
 func TestWeightMul(t *testing.T) {
 	w1, err := NewWeightFromString("11mg")
 	if err != nil {
@@ -121,7 +360,14 @@ func TestWeightMul(t *testing.T) {
 		t.Errorf(`w2 should be equal to 121mg but w2 = %v`, w2)
 	}
 
+	// overflowing mg is promoted to a coarser SI unit
 	w3 := w2.Mul(100000000000000000).Mul(100000000000000000)
+	if w3.IsInfinite() || w3.String() != "1210000000000000000000000000000kg" {
+		t.Errorf(`w3 should be 1210000000000000000000000000000kg but w3 = %v`, w3)
+	}
+
+	// until even Gt overflows
+	w3 = w3.Mul(100000000000000000)
 	if !w3.IsInfinite() {
 		t.Errorf(`w3 should be infinite but w3 = %v`, w3)
 	}
@@ -130,6 +376,43 @@ func TestWeightMul(t *testing.T) {
 	}
 }
 
+func TestWeightPromotion(t *testing.T) {
+	cases := []struct {
+		w    string
+		mul  Decimal
+		want string
+	}{
+		{"9e15g", 1000, "9000000000000000000g"},
+		{"9e15g", New(1, 15), "9000000000000000000000000000000g"},
+		{"9e15g", New(1, 16), "90000000000000000000000000000kg"},
+		{"9e15g", New(-1, 30), "-9000000000000000000000000000000Gt"},
+		{"9e30pg", New(1, 20), "900000000000000000000000000000kt"},
+		{"1.5e30t", 100, "150000000000000000000000000000kt"},
+		{"9e30Gt", 10, "+Inf"},
+		{"9e30lb", 10, "+Inf"}, // no exact conversion to a coarser unit
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+
+		if r := w.Mul(c.mul); r.String() != c.want {
+			t.Errorf(`%v.Mul(%v) should be %s and not %v`, w, c.mul, c.want, r)
+		} else if !r.IsInfinite() && !r.IsExact() {
+			t.Errorf(`%v.Mul(%v) should be exact and not %v`, w, c.mul, r)
+		}
+	}
+
+	// the sum of two weights is promoted as well
+	w, _ := NewWeightFromString("9e30g")
+	if r := w.Add(w); r.String() != "18000000000000000000000000000kg" {
+		t.Errorf(`%v + %v should be 18000000000000000000000000000kg and not %v`, w, w, r)
+	}
+	w, _ = NewWeightFromString("9e30pg")
+	if r := w.Sub(w.Neg()); r.String() != "18000000000000000000000000000ng" {
+		t.Errorf(`%v - %v should be 18000000000000000000000000000ng and not %v`, w, w.Neg(), r)
+	}
+}
+
 func TestWeightDiv(t *testing.T) {
 	w1, err := NewWeightFromString("121mg")
 	if err != nil {
@@ -160,6 +443,39 @@ func TestWeightDiv(t *testing.T) {
 	}
 }
 
+func TestWeightRatio(t *testing.T) {
+	cases := []struct {
+		w1, w2 string
+		want   string
+	}{
+		{"500g", "1kg", "0.5"},
+		{"1kg", "500g", "2"},
+		{"1t", "250kg", "4"},
+		{"-3mg", "1.5mg", "-2"},
+		{"1lb", "1oz", "16"},
+		{"1oz", "1lb", "0.0625"},
+		{"1g", "3g", "~0.3333333333333333"},
+		{"1kg", "1lb", "~2.2046226218487758"},
+		{"0g", "1kg", "0"},
+	}
+
+	for _, c := range cases {
+		w1, _ := NewWeightFromString(c.w1)
+		w2, _ := NewWeightFromString(c.w2)
+
+		if r := w1.Ratio(w2); r.String() != c.want {
+			t.Errorf(`%v.Ratio(%v) should be %s and not %v`, w1, w2, c.want, r)
+		}
+	}
+
+	kg, _ := NewWeightFromString("1kg")
+	for _, w := range []Weight{Weight(Zero), Weight(Null), WeightFromPicograms(0)} {
+		if r := kg.Ratio(w); !r.IsNaN() {
+			t.Errorf(`1kg.Ratio(%v) should be NaN and not %v`, w, r)
+		}
+	}
+}
+
 func TestWeightJSONMarshaling(t *testing.T) {
 	w, err := NewWeightFromString("11lb")
 	if err != nil {
@@ -349,6 +665,162 @@ func TestWeightCompare(t *testing.T) {
 	}
 }
 
+func TestWeightEqual(t *testing.T) {
+	cases := []struct {
+		w1, w2 string
+		want   bool
+	}{
+		{"1kg", "1000g", true},
+		{"1kg", "999g", false},
+		{"1000g", "1kg", true},
+		{"1t", "1000000g", true},
+		{"1lb", "16oz", true},
+		{"1lb", "453.59237g", true},
+		{"1lb", "453.59g", false},
+		{"1st", "14lb", true},
+		{"~1kg", "1000g", true},
+		{"0kg", "0g", true},
+		{"0g", "~0mg", true},
+		{"-1kg", "-1000g", true},
+		{"-1kg", "1000g", false},
+		{"NaN", "NaN", false},
+		{"1000g", "+Inf", false},
+		{"1000g", "-Inf", false},
+	}
+
+	for _, c := range cases {
+		w1, _ := NewWeightFromString(c.w1)
+		w2, _ := NewWeightFromString(c.w2)
+
+		if eq := w1.Equal(w2); eq != c.want {
+			t.Errorf(`%v.Equal(%v) should be %v and not %v`, w1, w2, c.want, eq)
+		}
+		if eq := w2.Equal(w1); eq != c.want {
+			t.Errorf(`%v.Equal(%v) should be %v and not %v`, w2, w1, c.want, eq)
+		}
+	}
+
+	// == on the raw representation is not a weight equality
+	w1, _ := NewWeightFromString("1kg")
+	w2, _ := NewWeightFromString("1000g")
+	if w1 == w2 || !w1.Equal(w2) {
+		t.Errorf(`1kg and 1000g should have different representations but be Equal`)
+	}
+
+	// a negative near zero keeps its unit bits mixed with its loss bit
+	for _, s := range []string{"~0mg", "~-0g", "~+0lb"} {
+		if w, _ := NewWeightFromString(s); !w.IsZero() || !w.Neg().IsZero() {
+			t.Errorf(`%v and its negation should be zero`, w)
+		}
+	}
+}
+
+func TestWeightEqualWithin(t *testing.T) {
+	cases := []struct {
+		w1, w2, tol string
+		want        bool
+	}{
+		{"1lb", "453.59237g", "0.001g", true},
+		{"1lb", "453.6g", "0.01g", true},
+		{"1lb", "453.6g", "1mg", false},
+		{"1lb", "453.6g", "-0.01g", true},
+		{"1lb", "500g", "1g", false},
+		{"1lb", "0.45359237kg", "0g", true},
+		{"1oz", "28.35g", "1mg", true},
+		{"1kg", "1000g", "0kg", true},
+		{"1kg", "1001g", "1g", true},
+		{"1kg", "1001g", "999mg", false},
+		{"NaN", "1kg", "1t", false},
+		{"1kg", "NaN", "1t", false},
+		{"1kg", "1kg", "NaN", false},
+		{"+Inf", "1kg", "1t", false},
+	}
+
+	for _, c := range cases {
+		w1, _ := NewWeightFromString(c.w1)
+		w2, _ := NewWeightFromString(c.w2)
+		tol, _ := NewWeightFromString(c.tol)
+
+		if eq := w1.EqualWithin(w2, tol); eq != c.want {
+			t.Errorf(`%v.EqualWithin(%v, %v) should be %v and not %v`, w1, w2, tol, c.want, eq)
+		}
+		if eq := w2.EqualWithin(w1, tol); eq != c.want {
+			t.Errorf(`%v.EqualWithin(%v, %v) should be %v and not %v`, w2, w1, tol, c.want, eq)
+		}
+	}
+
+	// the gram equivalent computed through a conversion is equal to 1lb within a small tolerance
+	lb, _ := NewWeightFromString("1lb")
+	g, _ := lb.In("g")
+	tol, _ := NewWeightFromString("0.000001g")
+	if !g.EqualWithin(lb, tol) {
+		t.Errorf(`%v.EqualWithin(%v, %v) should be true`, g, lb, tol)
+	}
+}
+
+func TestWeightCompareTotal(t *testing.T) {
+	w := func(s string) Weight {
+		w, err := NewWeightFromString(s)
+		if err != nil {
+			t.Fatalf(`NewWeightFromString(%q) returns error %v`, s, err)
+		}
+		return w
+	}
+
+	cases := []struct {
+		w1, w2 Weight
+		want   int
+	}{
+		{w("1kg"), w("1000g"), 1},
+		{w("1000g"), w("1kg"), -1},
+		{w("1kg"), w("1kg"), 0},
+		{w("1t"), w("1000kg"), 1},
+		{w("1000000mg"), w("1000g"), -1},
+		{w("1kg"), w("1.5g"), 1},
+		{w("1g"), w("1kg"), -1},
+		{w("-1kg"), w("-1000g"), 1},
+		{w("16oz"), w("1lb"), -1},
+		{w("1lb"), w("16oz"), 1},
+		{w("1mcg"), w("1µg"), 0},
+		{Weight(NaN), w("1kg"), 1},
+		{w("1kg"), Weight(NaN), -1},
+		{Weight(NaN), Weight(PositiveInfinity), 1},
+		{Weight(PositiveInfinity), Weight(NaN), -1},
+		{Weight(NaN), Weight(NaN), 0},
+		{Weight(PositiveInfinity), Weight(PositiveInfinity), 0},
+		{Weight(NegativeInfinity), Weight(PositiveInfinity), -1},
+		{Weight(PositiveInfinity), w("1Gt"), 1},
+	}
+
+	for _, c := range cases {
+		if r := c.w1.CompareTotal(c.w2); r != c.want {
+			t.Errorf(`%v.CompareTotal(%v) should be %d and not %d`, c.w1, c.w2, c.want, r)
+		}
+	}
+
+	// sorting with CompareTotal is deterministic whatever the input order
+	ws := []Weight{Weight(NaN), w("1kg"), w("1000g"), w("0.5kg"), w("1000000mg"), w("1t"), Weight(NegativeInfinity)}
+	want := "-Inf 0.5kg 1000000mg 1000g 1kg 1t NaN"
+	for k := 0; k < len(ws); k++ {
+		// rotate the input and insertion sort it
+		ws = append(ws[1:], ws[0])
+		sorted := append([]Weight(nil), ws...)
+		for i := 1; i < len(sorted); i++ {
+			for j := i; j > 0 && sorted[j-1].CompareTotal(sorted[j]) > 0; j-- {
+				sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+			}
+		}
+
+		var got []string
+		for _, x := range sorted {
+			got = append(got, x.String())
+		}
+		if s := strings.Join(got, " "); s != want {
+			t.Errorf(`weights sorted with CompareTotal should be %s and not %s`, want, s)
+		}
+	}
+}
+
 func TestNewWeightPositive(t *testing.T) {
 	// NewWeight with strictly positive value goes through the v=0 branch (the negative branch is already covered by NewWeight(0,...))
 	w, err := NewWeight(101, 0, "kg")
@@ -418,3 +890,695 @@ func TestWeightUnmarshalErrors(t *testing.T) {
 		t.Errorf(`UnmarshalText("not-a-weight") should error`)
 	}
 }
+
+func TestWeightUnits(t *testing.T) {
+	want := []string{"kg", "t", "kt", "Mt", "Gt", "g", "mg", "µg", "ng", "pg", "st", "cwt", "lb", "oz", " lb t", " oz t"}
+
+	if units := WeightUnits(); len(units) != len(want) {
+		t.Errorf(`WeightUnits() should be %q and not %q`, want, units)
+	} else {
+		for i := range units {
+			if units[i] != want[i] {
+				t.Errorf(`WeightUnits() should be %q and not %q`, want, units)
+				break
+			}
+
+			// every unit can be parsed back and is kept as is
+			if w, err := NewWeight(1, 0, units[i]); err != nil || w.Unit() != units[i] {
+				t.Errorf(`NewWeight(1, 0, %q) should have unit %q and not %v (err = %v)`, units[i], units[i], w, err)
+			}
+		}
+	}
+}
+
+func TestUnitHashes(t *testing.T) {
+	// the hashes are computed at init, parsing never writes to the shared unit tables
+	for _, units := range [][]unit{weightUnits[:], lengthUnits[:], dataSizeUnits[:], timeSpanUnits[:], temperatureUnits[:]} {
+		for _, u := range units {
+			if u.h != unitHash(u.u) || (u.u != "") != (u.h != 0) {
+				t.Errorf(`unit %q should have hash %d and not %d`, u.u, unitHash(u.u), u.h)
+			}
+		}
+	}
+}
+
+func TestRegisterWeightAlias(t *testing.T) {
+	if err := RegisterWeightAlias("kilo", "kg"); err != nil {
+		t.Errorf(`RegisterWeightAlias("kilo", "kg") should be ok, error = %v`, err)
+	}
+	if err := RegisterWeightAlias("gram", "g"); err != nil {
+		t.Errorf(`RegisterWeightAlias("gram", "g") should be ok, error = %v`, err)
+	}
+	// an alias may refer to another alias
+	if err := RegisterWeightAlias("kilogram", "kilo"); err != nil {
+		t.Errorf(`RegisterWeightAlias("kilogram", "kilo") should be ok, error = %v`, err)
+	}
+
+	cases := []struct {
+		s, want string
+	}{
+		{"2 kilo", "2kg"},
+		{"2kilo", "2kg"},
+		{"1.5 KILO", "1.5kg"},
+		{"250 gram", "250g"},
+		{"3 kilogram", "3kg"},
+		{"2 kg", "2kg"},
+	}
+
+	for _, c := range cases {
+		if w, err := NewWeightFromString(c.s); err != nil || w.String() != c.want {
+			t.Errorf(`NewWeightFromString(%q) should be %s and not %v (err = %v)`, c.s, c.want, w, err)
+		}
+	}
+
+	if w, err := NewWeight(5, 0, "gram"); err != nil || w.String() != "5g" {
+		t.Errorf(`NewWeight(5, 0, "gram") should be 5g and not %v (err = %v)`, w, err)
+	}
+	w, _ := NewWeightFromString("1500g")
+	if r, err := w.In("kilo"); err != nil || r.String() != "1.5kg" {
+		t.Errorf(`%v.In("kilo") should be 1.5kg and not %v (err = %v)`, w, r, err)
+	}
+
+	for _, c := range []struct{ alias, canonical string }{
+		{"pound", "livre"}, // unknown canonical unit
+		{"kg", "g"},        // existing unit
+		{"kilo", "g"},      // existing alias
+		{"nan", "kg"},      // magic value
+		{"", "kg"},
+	} {
+		if err := RegisterWeightAlias(c.alias, c.canonical); err != ErrUnitSyntax {
+			t.Errorf(`RegisterWeightAlias(%q, %q) should return ErrUnitSyntax and not %v`, c.alias, c.canonical, err)
+		}
+	}
+
+	// aliases are not canonical units
+	for _, u := range WeightUnits() {
+		if u == "kilo" || u == "gram" {
+			t.Errorf(`WeightUnits() should not return alias %q`, u)
+		}
+	}
+
+	// registering while parsing is safe
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			_ = RegisterWeightAlias(fmt.Sprintf("unit%d", i), "g")
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		if w, err := NewWeightFromString("2 kilo"); err != nil || w.String() != "2kg" {
+			t.Errorf(`NewWeightFromString("2 kilo") should be 2kg and not %v (err = %v)`, w, err)
+		}
+	}
+	<-done
+}
+
+func TestWeightImperial(t *testing.T) {
+	w, err := NewWeightFromString("11st")
+	if err != nil || w.Unit() != "st" || w.String() != "11st" {
+		t.Fatalf(`NewWeightFromString("11st") should be 11st and not %v (err = %v)`, w, err)
+	}
+
+	// stones to kg and back
+	kg, _ := NewWeight(0, 0, "kg")
+	if k := kg.Add(w); k.String() != "69.85322498kg" {
+		t.Errorf(`0kg + 11st should be 69.85322498kg and not %v`, k)
+	} else if st, _ := NewWeight(0, 0, "st"); st.Add(k) != w {
+		t.Errorf(`0st + %v should be 11st and not %v`, k, st.Add(k))
+	}
+
+	// 1 cwt is 8 st or 112 lb
+	cwt, _ := NewWeightFromString("1cwt")
+	if st, _ := NewWeight(0, 0, "st"); st.Add(cwt).String() != "8st" {
+		t.Errorf(`0st + 1cwt should be 8st and not %v`, st.Add(cwt))
+	}
+	if lb, _ := NewWeight(0, 0, "lb"); lb.Add(cwt).String() != "112lb" {
+		t.Errorf(`0lb + 1cwt should be 112lb and not %v`, lb.Add(cwt))
+	}
+
+	// "stone" is an alias of "st"
+	if a, err := NewWeightFromString("11 stone"); err != nil || a != w {
+		t.Errorf(`NewWeightFromString("11 stone") should be 11st and not %v (err = %v)`, a, err)
+	}
+
+	for _, s := range []string{"11st", "-2.5st", "0.75st", "1cwt", "-20cwt"} {
+		w, _ := NewWeightFromString(s)
+
+		var r Weight
+		if b, err := w.MarshalText(); err != nil || string(b) != s {
+			t.Errorf(`%v.MarshalText() should be %s and not %s (err = %v)`, w, s, b, err)
+		} else if err := r.UnmarshalText(b); err != nil || r != w {
+			t.Errorf(`UnmarshalText(%s) should be %v and not %v (err = %v)`, b, w, r, err)
+		}
+
+		r = 0
+		if b, err := w.MarshalJSON(); err != nil {
+			t.Errorf(`%v.MarshalJSON() should be ok, error = %v`, w, err)
+		} else if err := r.UnmarshalJSON(b); err != nil || r != w {
+			t.Errorf(`UnmarshalJSON(%s) should be %v and not %v (err = %v)`, b, w, r, err)
+		}
+
+		r = 0
+		if b, err := w.MarshalBinary(); err != nil {
+			t.Errorf(`%v.MarshalBinary() should be ok, error = %v`, w, err)
+		} else if err := r.UnmarshalBinary(b); err != nil || r != w {
+			t.Errorf(`UnmarshalBinary(%#v) should be %v and not %v (err = %v)`, b, w, r, err)
+		}
+	}
+}
+
+func TestWeightRegionalUnits(t *testing.T) {
+	cases := []struct {
+		s, str, kg string
+	}{
+		{"1st", "1st", "6.35029318kg"},
+		{"1cwt", "1cwt", "50.80234544kg"},
+		{"1gr", "64.79891mg", "0.00006479891kg"},
+		{"7000gr", "453592.37mg", "0.45359237kg"},
+		{"1ct", "200mg", "0.0002kg"},
+		{"2.5 ct", "500mg", "0.0005kg"},
+		{"1 long ton", "2240lb", "1016.0469088kg"},
+		{"1 short ton", "2000lb", "907.18474kg"},
+		{"-0.5 short ton", "-1000lb", "-453.59237kg"},
+	}
+
+	for _, c := range cases {
+		w, err := NewWeightFromString(c.s)
+		if err != nil || w.String() != c.str {
+			t.Errorf(`NewWeightFromString(%q) should be %s and not %v (err = %v)`, c.s, c.str, w, err)
+			continue
+		}
+
+		if kg, err := w.In("kg"); err != nil || kg.String() != c.kg {
+			t.Errorf(`%v.In("kg") should be %s and not %v (err = %v)`, w, c.kg, kg, err)
+		}
+
+		// the string representation uses a unit code and round-trips
+		if r, err := NewWeightFromString(w.String()); err != nil || r != w {
+			t.Errorf(`NewWeightFromString(%q) should be %v and not %v (err = %v)`, w.String(), w, r, err)
+		}
+	}
+
+	// parse only units are converted, their weight has the unit they are converted to
+	if w, _ := NewWeightFromString("1gr"); w.Unit() != "mg" || w.String() != "64.79891mg" {
+		t.Errorf(`NewWeightFromString("1gr") should be 64.79891mg and not %v`, w)
+	}
+	if w, _ := NewWeightFromString("3 short ton"); w.Unit() != "lb" || w.String() != "6000lb" {
+		t.Errorf(`NewWeightFromString("3 short ton") should be 6000lb and not %v`, w)
+	}
+
+	// a plain ton is either a long or a short ton, it is not recognized
+	for _, s := range []string{"1ton", "1 ton", "2 tons"} {
+		if w, err := NewWeightFromString(s); err != ErrUnitSyntax {
+			t.Errorf(`NewWeightFromString(%q) should return ErrUnitSyntax and not %v (err = %v)`, s, w, err)
+		}
+	}
+
+	long, _ := NewWeightFromString("1 long ton")
+	cwt, _ := NewWeightFromString("20cwt")
+	if !long.Equal(cwt) {
+		t.Errorf(`1 long ton should be equal to 20cwt`)
+	}
+
+	factors := []struct {
+		unit string
+		want Decimal
+	}{
+		{"gr", New(6479891, -11)},
+		{"ct", New(2, -4)},
+		{"long ton", New(10160469088, -7)},
+		{"short ton", New(90718474, -5)},
+	}
+
+	for _, c := range factors {
+		if f, err := WeightConversionFactor(c.unit); err != nil || f != c.want {
+			t.Errorf(`WeightConversionFactor(%q) should be %v and not %v (err = %v)`, c.unit, c.want, f, err)
+		}
+	}
+
+	// parse only aliases are not unit codes
+	for _, u := range WeightUnits() {
+		if u == "gr" || u == "ct" || strings.HasSuffix(u, "ton") {
+			t.Errorf(`WeightUnits() should not list the parse only alias %q`, u)
+		}
+	}
+}
+
+func TestWeightConversionFactor(t *testing.T) {
+	cases := []struct {
+		unit string
+		want Decimal
+	}{
+		{"kg", 1},
+		{"", 1},
+		{"g", New(1, -3)},
+		{"t", 1000},
+		{"pg", New(1, -15)},
+		{"Gt", New(1, 12)},
+		{"mcg", New(1, -9)},
+		{"lb", New(45359237, -8)},
+		{"lb av", New(45359237, -8)},
+		{"oz", New(28349523125, -12)},
+		{"oz t", New(311034768, -10)},
+		{"st", New(635029318, -8)},
+		{"stone", New(635029318, -8)},
+		{"cwt", New(5080234544, -8)},
+	}
+
+	for _, c := range cases {
+		if f, err := WeightConversionFactor(c.unit); err != nil || f != c.want {
+			t.Errorf(`WeightConversionFactor(%q) should be %v and not %v (err = %v)`, c.unit, c.want, f, err)
+		}
+	}
+
+	if f, err := WeightConversionFactor("m"); err != ErrUnitSyntax || !f.IsNaN() {
+		t.Errorf(`WeightConversionFactor("m") should be NaN with ErrUnitSyntax and not %v (err = %v)`, f, err)
+	}
+
+	// converting one unit to kg gives its conversion factor
+	for _, unit := range WeightUnits() {
+		w, _ := NewWeight(1, 0, unit)
+		kg, _ := NewWeight(0, 0, "kg")
+		_, value := kg.Add(w).Columns()
+
+		if f, _ := WeightConversionFactor(unit); !f.Equal(value) {
+			t.Errorf(`WeightConversionFactor(%q) should be %v and not %v`, unit, value, f)
+		}
+	}
+}
+
+func TestWeightMarshalTextNoUnit(t *testing.T) {
+	cases := []struct {
+		s, text, noUnit string
+	}{
+		{"550g", "550g", "550"},
+		{"-12.345kg", "-12.345kg", "-12.345"},
+		{"3t", "3t", "3"},
+		{"1 lb t", "1 lb t", "1"},
+		{"~1.2oz", "~1.2oz", "~1.2"},
+		{"0kg", "0kg", "0"},
+	}
+
+	for _, c := range cases {
+		w, err := NewWeightFromString(c.s)
+		if err != nil {
+			t.Fatalf(`NewWeightFromString(%q) has error = %v`, c.s, err)
+		}
+
+		if b, err := w.MarshalTextNoUnit(); err != nil || string(b) != c.noUnit {
+			t.Errorf(`%v.MarshalTextNoUnit() should be %q and not %q (err = %v)`, w, c.noUnit, b, err)
+		}
+		if b, err := w.MarshalText(); err != nil || string(b) != c.text {
+			t.Errorf(`%v.MarshalText() should be %q and not %q (err = %v)`, w, c.text, b, err)
+		}
+	}
+}
+
+func TestWeightColumns(t *testing.T) {
+	for _, s := range []string{"0kg", "1.5g", "-12.345kg", "3t", "250mg", "10µg", "2lb", "-0.5oz", "1 lb t", "7 oz t", "~1.2g"} {
+		w, err := NewWeightFromString(s)
+		if err != nil {
+			t.Fatalf(`NewWeightFromString(%q) has error = %v`, s, err)
+		}
+
+		unit, value := w.Columns()
+		if unit != w.Unit() {
+			t.Errorf(`%v.Columns() unit should be %q and not %q`, w, w.Unit(), unit)
+		}
+
+		r, err := WeightFromColumns(unit, value)
+		if err != nil || r != w {
+			t.Errorf(`WeightFromColumns(%q, %v) should be %v and not %v (err = %v)`, unit, value, w, r, err)
+		}
+	}
+
+	w, _ := NewWeightFromString("-12.345g")
+	if unit, value := w.Columns(); unit != "g" || value != New(-12345, -3) {
+		t.Errorf(`-12.345g.Columns() should be ("g", -12.345) and not (%q, %v)`, unit, value)
+	}
+
+	var null Weight
+	if unit, value := null.Columns(); unit != "kg" || value != Null {
+		t.Errorf(`Null.Columns() should be ("kg", Null) and not (%q, %v)`, unit, value)
+	}
+	if r, err := WeightFromColumns("kg", Null); err != nil || r != Null {
+		t.Errorf(`WeightFromColumns("kg", Null) should be Null and not %v (err = %v)`, r, err)
+	}
+
+	if _, err := WeightFromColumns("furlong", 1); err != ErrUnitSyntax {
+		t.Errorf(`WeightFromColumns("furlong", 1) should return ErrUnitSyntax and not %v`, err)
+	}
+}
+
+func TestWeightIn(t *testing.T) {
+	cases := []struct {
+		w, unit, want string
+		value         Decimal
+	}{
+		{"1kg", "g", "1000g", 1000},
+		{"1kg", "kg", "1kg", 1},
+		{"1000g", "kg", "1kg", 1},
+		{"1500g", "kg", "1.5kg", New(15, -1)},
+		{"2.5t", "kg", "2500kg", 2500},
+		{"1mg", "µg", "1000µg", 1000},
+		{"1mg", "mcg", "1000µg", 1000},
+		{"1lb", "oz", "16oz", 16},
+		{"1lb", "g", "453.59237g", New(45359237, -5)},
+		{"16oz", "lb", "1lb", 1},
+		{"1st", "lb", "14lb", 14},
+		{"-3oz", "g", "-85.048569375g", New(-85048569375, -9)},
+		{"0kg", "g", "0g", Zero},
+		{"+Inf", "g", "+Inf", PositiveInfinity},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+
+		if r, err := w.In(c.unit); err != nil || r.String() != c.want {
+			t.Errorf(`%v.In(%q) should be %s and not %v (err = %v)`, w, c.unit, c.want, r, err)
+		} else if !r.Equal(w) && !w.IsInfinite() {
+			t.Errorf(`%v.In(%q) should be Equal to %v`, w, c.unit, w)
+		}
+
+		if d, err := w.ToDecimal(c.unit); err != nil || d.String() != c.value.String() {
+			t.Errorf(`%v.ToDecimal(%q) should be %v and not %v (err = %v)`, w, c.unit, c.value, d, err)
+		}
+	}
+
+	w, _ := NewWeightFromString("1kg")
+	if r, err := w.In("m"); err != ErrUnitSyntax {
+		t.Errorf(`1kg.In("m") should return ErrUnitSyntax and not %v (err = %v)`, r, err)
+	}
+	if d, err := w.ToDecimal("m"); err != ErrUnitSyntax || !d.IsNaN() {
+		t.Errorf(`1kg.ToDecimal("m") should be NaN with ErrUnitSyntax and not %v (err = %v)`, d, err)
+	}
+}
+
+func TestWeightPer(t *testing.T) {
+	w, _ := NewWeightFromString("2.5kg")
+	liters := Decimal(2)
+
+	// 2.5kg in 2L is 1.25 g/mL or 1.25 kg/L
+	if d, err := w.Per(liters.Mul(1000), "g"); err != nil || d != New(125, -2) {
+		t.Errorf(`2.5kg.Per(2000, "g") should be 1.25 and not %v (err = %v)`, d, err)
+	}
+	if d, err := w.Per(liters, "kg"); err != nil || d != New(125, -2) {
+		t.Errorf(`2.5kg.Per(2, "kg") should be 1.25 and not %v (err = %v)`, d, err)
+	}
+
+	// the unit of w does not matter
+	w, _ = NewWeightFromString("2500g")
+	if d, err := w.Per(liters.Mul(1000), "g"); err != nil || d != New(125, -2) {
+		t.Errorf(`2500g.Per(2000, "g") should be 1.25 and not %v (err = %v)`, d, err)
+	}
+	if d, err := w.Per(3, "kg"); err != nil || d.String() != "~0.8333333333333333" {
+		t.Errorf(`2500g.Per(3, "kg") should be ~0.8333333333333333 and not %v (err = %v)`, d, err)
+	}
+
+	w, _ = NewWeightFromString("1lb")
+	if d, err := w.Per(1, "g"); err != nil || d != New(45359237, -5) {
+		t.Errorf(`1lb.Per(1, "g") should be 453.59237 and not %v (err = %v)`, d, err)
+	}
+
+	for _, zero := range []Decimal{Null, Zero, NearZero} {
+		if d, err := w.Per(zero, "g"); err != ErrDivisionByZero || !d.IsNaN() {
+			t.Errorf(`1lb.Per(%v, "g") should return NaN and ErrDivisionByZero and not %v (err = %v)`, zero, d, err)
+		}
+	}
+	if _, err := w.Per(1, "furlong"); err != ErrUnitSyntax {
+		t.Errorf(`1lb.Per(1, "furlong") should return ErrUnitSyntax and not %v`, err)
+	}
+}
+
+func TestWeightGobEncode(t *testing.T) {
+	w := func(s string) Weight {
+		w, _ := NewWeightFromString(s)
+
+		return w
+	}
+
+	// magic values are stored as v1 magic bytes, in kg
+	ws := []Weight{
+		Weight(Null), Weight(Zero), Weight(NearZero), Weight(NearPositiveZero), Weight(NearNegativeZero),
+		Weight(PositiveInfinity), Weight(NegativeInfinity),
+		101, w("-12.345kg"), w("11mg"), w("1 oz t"), w("-2.5 lb t"), w("~1.5lb"), w("11st"), w("3.14e15t"),
+	}
+
+	for _, x := range ws {
+		b, err := x.GobEncode()
+		if err != nil {
+			t.Errorf(`%v.GobEncode() should be ok, error = %v`, x, err)
+			continue
+		}
+
+		var r Weight = 99
+		if err := r.GobDecode(b); err != nil || r != x {
+			t.Errorf(`GobDecode(%#v) should be %v and not %v (err = %v)`, b, x, r, err)
+		}
+	}
+
+	// weights survive a gob stream as struct fields
+	type parcel struct {
+		Name   string
+		Weight Weight
+		Tare   Weight
+	}
+
+	var buf bytes.Buffer
+	in := []parcel{{"gold", w("1 oz t"), w("12g")}, {"flour", w("25lb"), Weight(Null)}, {"void", Weight(NaN), Weight(NegativeInfinity)}}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf(`gob Encode should be ok, error = %v`, err)
+	}
+
+	var out []parcel
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf(`gob Decode should be ok, error = %v`, err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf(`gob Decode should return %d parcels and not %d`, len(in), len(out))
+	}
+	for i := range in {
+		if out[i].Name != in[i].Name || out[i].Weight.String() != in[i].Weight.String() || out[i].Tare.String() != in[i].Tare.String() {
+			t.Errorf(`gob round-trip should be %v and not %v`, in[i], out[i])
+		}
+	}
+	if u := out[0].Weight.Unit(); u != " oz t" {
+		t.Errorf(`gob round-trip of 1 oz t should keep unit " oz t" and not %q`, u)
+	}
+}
+
+func TestWeightScanValue(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  string
+	}{
+		{"1 oz t", "1 oz t"},
+		{[]byte("11mg"), "11mg"},
+		{"-2.5lb", "-2.5lb"},
+		{"12.5", "12.5kg"},
+		{[]byte("3"), "3kg"},
+		{int64(42), "42kg"},
+		{uint64(7), "7kg"},
+		{float64(1.25), "1.25kg"},
+		{float32(0.5), "0.5kg"},
+	}
+
+	for _, c := range cases {
+		var w Weight
+		if err := w.Scan(c.value); err != nil || w.String() != c.want {
+			t.Errorf(`Scan(%#v) should be %s and not %v (err = %v)`, c.value, c.want, w, err)
+		}
+	}
+
+	for _, value := range []interface{}{"1 furlong", []byte("1..5g"), true, nil} {
+		var w Weight = 99
+		if err := w.Scan(value); err == nil || w != 99 {
+			t.Errorf(`Scan(%#v) should return an error and leave the weight unchanged, not %v`, value, w)
+		}
+	}
+
+	// a database round-trip keeps the unit
+	for _, s := range []string{"1 oz t", "11mg", "-3.5st", "~0.1g", "0g"} {
+		w, _ := NewWeightFromString(s)
+
+		v, err := w.Value()
+		if err != nil || v != w.String() {
+			t.Errorf(`%v.Value() should be %q and not %v (err = %v)`, w, w.String(), v, err)
+			continue
+		}
+
+		var r Weight
+		if err := r.Scan(v); err != nil || r != w {
+			t.Errorf(`Scan(%#v) should be %v and not %v (err = %v)`, v, w, r, err)
+		}
+	}
+}
+
+func TestWeightRoundToRetail(t *testing.T) {
+	cases := []struct {
+		w, want string
+	}{
+		{"0.23kg", "0.25kg"},
+		{"230g", "250g"},
+		{"1.1kg", "1kg"},
+		{"0.14kg", "0.1kg"},
+		{"0.16kg", "0.2kg"},
+		{"0.15kg", "0.2kg"},
+		{"0.375kg", "0.5kg"},
+		{"0.7kg", "0.5kg"},
+		{"0.75kg", "1kg"},
+		{"0.9kg", "1kg"},
+		{"1kg", "1kg"},
+		{"1.6kg", "2kg"},
+		{"2.3kg", "2.5kg"},
+		{"4kg", "5kg"},
+		{"8kg", "10kg"},
+		{"12kg", "10kg"},
+		{"-0.23kg", "-0.25kg"},
+		{"~0.23kg", "0.25kg"},
+		{"7g", "5g"},
+		{"0.3lb", "0.25lb"},
+		{"0kg", "0kg"},
+		{"0g", "0g"},
+		{"+Inf", "+Inf"},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+
+		if r := w.RoundToRetail(); r.String() != c.want {
+			t.Errorf(`%v.RoundToRetail() should be %s and not %v`, w, c.want, r)
+		} else if r.Unit() != w.Unit() {
+			t.Errorf(`%v.RoundToRetail() should keep unit %q and not %q`, w, w.Unit(), r.Unit())
+		}
+	}
+}
+
+func TestWeightAggregates(t *testing.T) {
+	w := func(s string) Weight {
+		w, _ := NewWeightFromString(s)
+
+		return w
+	}
+
+	cases := []struct {
+		ws       []Weight
+		sum, avg string
+		min, max string
+	}{
+		{[]Weight{w("1kg"), w("500g"), w("0.3kg")}, "1.8kg", "0.6kg", "0.3kg", "1kg"},
+		{[]Weight{w("500g"), w("1kg"), w("0.3kg")}, "1800g", "600g", "0.3kg", "1kg"},
+		{[]Weight{w("1lb"), w("8oz")}, "1.5lb", "0.75lb", "8oz", "1lb"},
+		{[]Weight{w("2kg")}, "2kg", "2kg", "2kg", "2kg"},
+		{[]Weight{w("1e30t"), w("1g"), w("-1e30t"), w("1g")}, "~0.000002t", "~0.0000005t", "-1000000000000000000000000000000t", "1000000000000000000000000000000t"},
+		{[]Weight{w("1kg"), w("-1kg")}, "0kg", "0kg", "-1kg", "1kg"},
+	}
+
+	for _, c := range cases {
+		if r := SumWeights(c.ws[0], c.ws[1:]...); r.String() != c.sum {
+			t.Errorf(`SumWeights(%v) should be %s and not %v`, c.ws, c.sum, r)
+		}
+		if r := AvgWeights(c.ws[0], c.ws[1:]...); r.String() != c.avg {
+			t.Errorf(`AvgWeights(%v) should be %s and not %v`, c.ws, c.avg, r)
+		}
+		if r := MinWeights(c.ws[0], c.ws[1:]...); r.String() != c.min {
+			t.Errorf(`MinWeights(%v) should be %s and not %v`, c.ws, c.min, r)
+		}
+		if r := MaxWeights(c.ws[0], c.ws[1:]...); r.String() != c.max {
+			t.Errorf(`MaxWeights(%v) should be %s and not %v`, c.ws, c.max, r)
+		}
+	}
+
+	// NaN is propagated by the sum like Sum does
+	if r := SumWeights(w("1kg"), Weight(NaN), w("1g")); !r.IsNaN() {
+		t.Errorf(`SumWeights(1kg, NaN, 1g) should be NaN and not %v`, r)
+	}
+	if r, d := SumWeights(w("1kg"), Weight(NaN)), Sum(1, NaN); r.IsNaN() != d.IsNaN() {
+		t.Errorf(`SumWeights(1kg, NaN) should be NaN like Sum(1, NaN) and not %v`, r)
+	}
+}
+
+func TestWeightHumanize(t *testing.T) {
+	cases := []struct {
+		w, want string
+	}{
+		{"1500000g", "1.5t"},
+		{"0.0005kg", "500mg"},
+		{"1000g", "1kg"},
+		{"999g", "999g"},
+		{"1kg", "1kg"},
+		{"0.25kg", "250g"},
+		{"-0.25kg", "-250g"},
+		{"12345mg", "12.345g"},
+		{"0.001mg", "1µg"},
+		{"0.0000001pg", "0.0000001pg"},
+		{"5e15kg", "5000Gt"},
+		{"2500kt", "2.5Mt"},
+		{"~1500g", "~1.5kg"},
+		{"0.5lb", "8oz"},
+		{"20oz", "1.25lb"},
+		{"3lb", "3lb"},
+		{"11st", "11st"},
+		{"0.5st", "7lb"},
+		{"0.25cwt", "28lb"},
+		{"2cwt", "2cwt"},
+		{"24 oz t", "2 lb t"},
+		{"0.5 lb t", "6 oz t"},
+		{"0g", "0g"},
+		{"+Inf", "+Inf"},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+
+		if r := w.Humanize(); r.String() != c.want {
+			t.Errorf(`%v.Humanize() should be %s and not %v`, w, c.want, r)
+		} else if !r.Equal(w) && !w.IsInfinite() {
+			t.Errorf(`%v.Humanize() should be Equal to %v`, w, w)
+		}
+	}
+}
+
+func TestWeightMarshalJSONObject(t *testing.T) {
+	cases := []struct {
+		w, want string
+	}{
+		{"1.5kg", `{"value":1.5,"unit":"kg"}`},
+		{"-250g", `{"value":-250,"unit":"g"}`},
+		{"1 oz t", `{"value":1,"unit":"oz t"}`},
+		{"11st", `{"value":11,"unit":"st"}`},
+		{"0mg", `{"value":0,"unit":"mg"}`},
+	}
+
+	for _, c := range cases {
+		w, _ := NewWeightFromString(c.w)
+
+		b, err := w.MarshalJSONObject()
+		if err != nil || string(b) != c.want {
+			t.Errorf(`%v.MarshalJSONObject() should be %s and not %s (err = %v)`, w, c.want, b, err)
+			continue
+		}
+
+		var r Weight
+		if err := r.UnmarshalJSONObject(b); err != nil || r != w {
+			t.Errorf(`UnmarshalJSONObject(%s) should be %v and not %v (err = %v)`, b, w, r, err)
+		}
+	}
+
+	// both the object and the string forms are accepted
+	w, _ := NewWeightFromString("1.5kg")
+	for _, s := range []string{`{"value":1.5,"unit":"kg"}`, ` { "unit": "kg", "value": "1.5" } `, `{"value":1.5}`, `"1.5kg"`, `1.5kg`, `1.5`} {
+		var r Weight
+		if err := r.UnmarshalJSONObject([]byte(s)); err != nil || r != w {
+			t.Errorf(`UnmarshalJSONObject(%s) should be %v and not %v (err = %v)`, s, w, r, err)
+		}
+	}
+
+	for _, s := range []string{`{"value":1.5,"unit":"furlong"}`, `{"value":"x","unit":"kg"}`, `{"value":1.5`, `"1.5 furlong"`} {
+		var r Weight = 99
+		if err := r.UnmarshalJSONObject([]byte(s)); err == nil || r != 99 {
+			t.Errorf(`UnmarshalJSONObject(%s) should return an error and leave the weight unchanged, not %v`, s, r)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package decimal
+
+import "strings"
+
+// YAML support without any dependency: UnmarshalYAML uses the func(interface{}) error unmarshaler
+// interface which is the one of gopkg.in/yaml.v2 and still supported by gopkg.in/yaml.v3.
+
+// MarshalYAML implements the yaml.Marshaler interface, a decimal is emitted as its String representation
+// with a '.' decimal point whatever DecimalSeparator is.
+// As the value is handed over as a string, a YAML encoder quotes it when it would be read back as another type
+// ("1.5", "yes"), which UnmarshalYAML accepts as well.
+func (d Decimal) MarshalYAML() (interface{}, error) {
+	return string(d.BytesTo(nil)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of gopkg.in/yaml.v2 (also accepted by gopkg.in/yaml.v3).
+//
+// Both plain scalars (1.5, yes, no) and quoted strings ("~0", "NaN", "+Inf") are parsed like NewFromString,
+// YAML special floats .inf, -.inf and .nan are understood and a YAML null gives Null.
+func (d *Decimal) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(s) {
+	case ".inf", "+.inf":
+		*d = PositiveInfinity
+
+		return nil
+	case "-.inf":
+		*d = NegativeInfinity
+
+		return nil
+	case ".nan":
+		*d = NaN
+
+		return nil
+	}
+
+	if _d, err := NewFromString(s); err != nil {
+		return err
+	} else {
+		*d = _d
+
+		return nil
+	}
+}
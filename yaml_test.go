@@ -0,0 +1,69 @@
+package decimal
+
+import (
+	"errors"
+	"testing"
+)
+
+// yamlScalar returns an unmarshal function feeding s the way a YAML decoder does for a scalar decoded into a string
+func yamlScalar(s string) func(interface{}) error {
+	return func(v interface{}) error {
+		*v.(*string) = s
+
+		return nil
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	for _, d := range []Decimal{NearZero, NaN, PositiveInfinity, NegativeInfinity, NearNegativeZero, Zero, New(-12345, -2), New(1, -16)} {
+		v, err := d.MarshalYAML()
+		if err != nil || v != d.String() {
+			t.Errorf(`(%v).MarshalYAML() should be %q and not %v (err = %v)`, d, d.String(), v, err)
+			continue
+		}
+
+		// round-trip
+		var r Decimal
+		if err := r.UnmarshalYAML(yamlScalar(v.(string))); err != nil || r.String() != d.String() {
+			t.Errorf(`UnmarshalYAML(%q) should be %v and not %v (err = %v)`, v, d, r, err)
+		}
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	cases := []struct {
+		s    string
+		want Decimal
+	}{
+		{"1.5", New(15, -1)},
+		{"-123.45", New(-12345, -2)},
+		{"~0", NearZero},
+		{"NaN", NaN},
+		{"+Inf", PositiveInfinity},
+		{"yes", 1},
+		{"no", Zero},
+		{".inf", PositiveInfinity},
+		{"+.Inf", PositiveInfinity},
+		{"-.INF", NegativeInfinity},
+		{".NaN", NaN},
+		{"", Null},
+	}
+
+	for _, c := range cases {
+		var d Decimal = 99
+		if err := d.UnmarshalYAML(yamlScalar(c.s)); err != nil || d.String() != c.want.String() || d.IsNaN() != c.want.IsNaN() {
+			t.Errorf(`UnmarshalYAML(%q) should be %v and not %v (err = %v)`, c.s, c.want, d, err)
+		}
+	}
+
+	var d Decimal = 99
+	if err := d.UnmarshalYAML(yamlScalar("1.5.5")); err != ErrSyntax || d != 99 {
+		t.Errorf(`UnmarshalYAML("1.5.5") should return ErrSyntax and not %v (err = %v)`, d, err)
+	}
+
+	// an error of the YAML decoder (a mapping or a sequence instead of a scalar) is returned as is
+	errNode := errors.New("cannot unmarshal !!map into string")
+	if err := d.UnmarshalYAML(func(interface{}) error { return errNode }); err != errNode || d != 99 {
+		t.Errorf(`UnmarshalYAML() should return the decoder error and not %v (err = %v)`, d, err)
+	}
+}